@@ -0,0 +1,194 @@
+package api
+
+// OpenAPIDescription describes the REST API (OpenAPI 3.0).  It's served at /openapi.json
+const OpenAPIDescription = `{
+  "openapi": "3.0.3",
+  "info": {
+    "title": "tfsutil",
+    "description": "TFS / VSTS helpers: projects and variable groups",
+    "version": "1.0.0"
+  },
+  "security": [{ "bearerAuth": [] }],
+  "paths": {
+    "/api/collections/{collection}/projects": {
+      "get": {
+        "summary": "List projects",
+        "operationId": "listProjects",
+        "parameters": [{ "$ref": "#/components/parameters/collection" }],
+        "responses": {
+          "200": { "description": "The projects in the collection", "content": { "application/json": { "schema": { "$ref": "#/components/schemas/ProjectList" } } } },
+          "401": { "$ref": "#/components/responses/Unauthorized" },
+          "502": { "$ref": "#/components/responses/TFSError" }
+        }
+      }
+    },
+    "/api/collections/{collection}/projects/{project}/variablegroups": {
+      "get": {
+        "summary": "List variable groups",
+        "operationId": "listVariableGroups",
+        "parameters": [{ "$ref": "#/components/parameters/collection" }, { "$ref": "#/components/parameters/project" }],
+        "responses": {
+          "200": { "description": "The variable groups in the project", "content": { "application/json": { "schema": { "$ref": "#/components/schemas/VariableGroupList" } } } },
+          "401": { "$ref": "#/components/responses/Unauthorized" },
+          "502": { "$ref": "#/components/responses/TFSError" }
+        }
+      }
+    },
+    "/api/collections/{collection}/projects/{project}/variablegroups/{name}/export": {
+      "get": {
+        "summary": "Export a variable group (in the 'tfsutil vg export' format).  Secret values are never included",
+        "operationId": "exportVariableGroup",
+        "parameters": [{ "$ref": "#/components/parameters/collection" }, { "$ref": "#/components/parameters/project" }, { "$ref": "#/components/parameters/name" }],
+        "responses": {
+          "200": { "description": "The exported group", "content": { "application/json": { "schema": { "$ref": "#/components/schemas/VariableGroupExport" } } } },
+          "401": { "$ref": "#/components/responses/Unauthorized" },
+          "404": { "$ref": "#/components/responses/NotFound" },
+          "502": { "$ref": "#/components/responses/TFSError" }
+        }
+      }
+    },
+    "/api/collections/{collection}/projects/{project}/variablegroups/{name}/diff": {
+      "get": {
+        "summary": "Compare a variable group with another group, or with the group of the same name in another project",
+        "operationId": "diffVariableGroups",
+        "parameters": [
+          { "$ref": "#/components/parameters/collection" },
+          { "$ref": "#/components/parameters/project" },
+          { "$ref": "#/components/parameters/name" },
+          { "name": "with", "in": "query", "description": "The other group (default is the same name)", "schema": { "type": "string" } },
+          { "name": "project", "in": "query", "description": "The other group's project (default is the same project)", "schema": { "type": "string" } }
+        ],
+        "responses": {
+          "200": { "description": "The differences", "content": { "application/json": { "schema": { "$ref": "#/components/schemas/Diff" } } } },
+          "400": { "$ref": "#/components/responses/BadRequest" },
+          "401": { "$ref": "#/components/responses/Unauthorized" },
+          "404": { "$ref": "#/components/responses/NotFound" },
+          "502": { "$ref": "#/components/responses/TFSError" }
+        }
+      }
+    },
+    "/api/collections/{collection}/projects/{project}/variablegroups/{name}/copy": {
+      "post": {
+        "summary": "Copy a variable group",
+        "operationId": "copyVariableGroup",
+        "parameters": [{ "$ref": "#/components/parameters/collection" }, { "$ref": "#/components/parameters/project" }, { "$ref": "#/components/parameters/name" }],
+        "requestBody": {
+          "required": false,
+          "content": { "application/json": { "schema": { "$ref": "#/components/schemas/CopyRequest" } } }
+        },
+        "responses": {
+          "201": { "description": "The new group", "content": { "application/json": { "schema": { "$ref": "#/components/schemas/VariableGroup" } } } },
+          "400": { "$ref": "#/components/responses/BadRequest" },
+          "401": { "$ref": "#/components/responses/Unauthorized" },
+          "404": { "$ref": "#/components/responses/NotFound" },
+          "502": { "$ref": "#/components/responses/TFSError" }
+        }
+      }
+    }
+  },
+  "components": {
+    "securitySchemes": {
+      "bearerAuth": { "type": "http", "scheme": "bearer" }
+    },
+    "parameters": {
+      "collection": { "name": "collection", "in": "path", "required": true, "schema": { "type": "string" }, "example": "DefaultCollection" },
+      "project": { "name": "project", "in": "path", "required": true, "schema": { "type": "string" } },
+      "name": { "name": "name", "in": "path", "required": true, "description": "Variable group name (url encoded)", "schema": { "type": "string" } }
+    },
+    "responses": {
+      "BadRequest": { "description": "The request isn't valid", "content": { "application/json": { "schema": { "$ref": "#/components/schemas/Error" } } } },
+      "Unauthorized": { "description": "The bearer token is missing or wrong", "content": { "application/json": { "schema": { "$ref": "#/components/schemas/Error" } } } },
+      "NotFound": { "description": "The variable group couldn't be found", "content": { "application/json": { "schema": { "$ref": "#/components/schemas/Error" } } } },
+      "TFSError": { "description": "TFS returned an error", "content": { "application/json": { "schema": { "$ref": "#/components/schemas/Error" } } } }
+    },
+    "schemas": {
+      "Error": {
+        "type": "object",
+        "properties": { "error": { "type": "string" } }
+      },
+      "Project": {
+        "type": "object",
+        "properties": {
+          "id": { "type": "string" },
+          "name": { "type": "string" },
+          "description": { "type": "string" },
+          "url": { "type": "string" },
+          "state": { "type": "string" },
+          "visibility": { "type": "string" }
+        }
+      },
+      "ProjectList": {
+        "type": "object",
+        "properties": {
+          "count": { "type": "integer" },
+          "value": { "type": "array", "items": { "$ref": "#/components/schemas/Project" } }
+        }
+      },
+      "Variable": {
+        "type": "object",
+        "properties": {
+          "value": { "type": "string" },
+          "isSecret": { "type": "boolean" }
+        }
+      },
+      "VariableGroup": {
+        "type": "object",
+        "properties": {
+          "id": { "type": "integer" },
+          "name": { "type": "string" },
+          "description": { "type": "string" },
+          "type": { "type": "string" },
+          "variables": { "type": "object", "additionalProperties": { "$ref": "#/components/schemas/Variable" } }
+        }
+      },
+      "VariableGroupList": {
+        "type": "object",
+        "properties": {
+          "count": { "type": "integer" },
+          "value": { "type": "array", "items": { "$ref": "#/components/schemas/VariableGroup" } }
+        }
+      },
+      "VariableGroupExport": {
+        "allOf": [
+          { "$ref": "#/components/schemas/VariableGroup" },
+          { "type": "object", "properties": { "serviceEndpointName": { "type": "string" } } }
+        ]
+      },
+      "GroupReference": {
+        "type": "object",
+        "properties": {
+          "project": { "type": "string" },
+          "name": { "type": "string" },
+          "id": { "type": "integer" }
+        }
+      },
+      "Diff": {
+        "type": "object",
+        "properties": {
+          "from": { "$ref": "#/components/schemas/GroupReference" },
+          "to": { "$ref": "#/components/schemas/GroupReference" },
+          "differences": {
+            "type": "array",
+            "items": {
+              "type": "object",
+              "properties": {
+                "name": { "type": "string" },
+                "change": { "type": "string", "enum": ["added", "removed", "changed"] },
+                "from": { "$ref": "#/components/schemas/Variable" },
+                "to": { "$ref": "#/components/schemas/Variable" }
+              }
+            }
+          }
+        }
+      },
+      "CopyRequest": {
+        "type": "object",
+        "properties": {
+          "name": { "type": "string", "description": "Name of the new group (default is 'Copy of <name> (<unique id>)')" },
+          "project": { "type": "string", "description": "Project to copy the group to (default is the same project)" }
+        }
+      }
+    }
+  }
+}
+`
@@ -0,0 +1,287 @@
+// Package api serves tfsutil operations as a small REST API, so other tools can use them without
+// shelling out to tfsutil.
+//
+// Every route except the OpenAPI description needs an 'Authorization: Bearer <token>' header:
+//
+//	GET  /openapi.json
+//	GET  /api/collections/{collection}/projects
+//	GET  /api/collections/{collection}/projects/{project}/variablegroups
+//	GET  /api/collections/{collection}/projects/{project}/variablegroups/{name}/export
+//	GET  /api/collections/{collection}/projects/{project}/variablegroups/{name}/diff?with={name}&project={project}
+//	POST /api/collections/{collection}/projects/{project}/variablegroups/{name}/copy
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// Server is the REST API.  It's an http.Handler
+type Server struct {
+	// Client is used to talk to TFS
+	Client tfs.Client
+
+	// Token is the bearer token callers must send
+	Token string
+
+	// Logger logs each request.  If it's nil, requests aren't logged
+	Logger *log.Logger
+}
+
+// ErrorResponse is the body sent with an error status
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// DiffResponse is the response for a variable group diff
+type DiffResponse struct {
+	From        GroupReference           `json:"from"`
+	To          GroupReference           `json:"to"`
+	Differences []tfs.VariableDifference `json:"differences"`
+}
+
+// GroupReference identifies a variable group
+type GroupReference struct {
+	Project string `json:"project"`
+	Name    string `json:"name"`
+	ID      int    `json:"id"`
+}
+
+// CopyRequest is the body of a variable group copy request.  Both fields are optional
+type CopyRequest struct {
+	// Name is the name of the new group (default is 'Copy of <name> (<unique id>)')
+	Name string `json:"name"`
+
+	// Project is the project to copy the group to (default is the group's own project)
+	Project string `json:"project"`
+}
+
+// ServeHTTP logs, authenticates and routes a request
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+	s.route(recorder, r)
+
+	if s.Logger != nil {
+		s.Logger.Printf("%s %s %d %s", r.Method, r.URL.RequestURI(), recorder.status, time.Since(start).Round(time.Millisecond))
+	}
+}
+
+// route sends a request to the handler for its path
+func (s *Server) route(w http.ResponseWriter, r *http.Request) {
+
+	//	The API description doesn't need a token
+	if r.URL.Path == "/openapi.json" && r.Method == http.MethodGet {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, OpenAPIDescription)
+		return
+	}
+
+	if !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="tfsutil"`)
+		writeError(w, http.StatusUnauthorized, "A valid bearer token is required")
+		return
+	}
+
+	//	Split the path into its (unescaped) parts
+	parts := []string{}
+	for _, part := range strings.Split(strings.Trim(r.URL.EscapedPath(), "/"), "/") {
+		unescaped, err := url.PathUnescape(part)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid path: %s", err))
+			return
+		}
+		parts = append(parts, unescaped)
+	}
+
+	if len(parts) < 4 || parts[0] != "api" || parts[1] != "collections" || parts[3] != "projects" {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	collection := parts[2]
+	rest := parts[4:]
+
+	switch {
+	case len(rest) == 0 && r.Method == http.MethodGet:
+		s.listProjects(w, r, collection)
+	case len(rest) == 2 && rest[1] == "variablegroups" && r.Method == http.MethodGet:
+		s.listVariableGroups(w, r, collection, rest[0])
+	case len(rest) == 4 && rest[1] == "variablegroups" && rest[3] == "export" && r.Method == http.MethodGet:
+		s.exportVariableGroup(w, r, collection, rest[0], rest[2])
+	case len(rest) == 4 && rest[1] == "variablegroups" && rest[3] == "diff" && r.Method == http.MethodGet:
+		s.diffVariableGroups(w, r, collection, rest[0], rest[2])
+	case len(rest) == 4 && rest[1] == "variablegroups" && rest[3] == "copy" && r.Method == http.MethodPost:
+		s.copyVariableGroup(w, r, collection, rest[0], rest[2])
+	default:
+		writeError(w, http.StatusNotFound, "Not found")
+	}
+}
+
+// authorized returns true if the request has the server's bearer token
+func (s *Server) authorized(r *http.Request) bool {
+	header := r.Header.Get("Authorization")
+	if s.Token == "" || !strings.HasPrefix(header, "Bearer ") {
+		return false
+	}
+
+	token := strings.TrimPrefix(header, "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.Token)) == 1
+}
+
+// listProjects lists the projects in a collection
+func (s *Server) listProjects(w http.ResponseWriter, r *http.Request, collection string) {
+	projects, err := s.Client.GetListOfProjects(collection)
+	if err != nil {
+		writeTFSError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, projects)
+}
+
+// listVariableGroups lists the variable groups in a project
+func (s *Server) listVariableGroups(w http.ResponseWriter, r *http.Request, collection, project string) {
+	groups, err := s.Client.GetListOfVariableGroups(collection, project)
+	if err != nil {
+		writeTFSError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, groups)
+}
+
+// exportVariableGroup exports a variable group, in the same format as 'tfsutil vg export'
+func (s *Server) exportVariableGroup(w http.ResponseWriter, r *http.Request, collection, project, name string) {
+	group, err := s.Client.FindVariableGroup(collection, project, name)
+	if err != nil {
+		writeTFSError(w, err)
+		return
+	}
+
+	export := tfs.VariableGroupExport{VariableGroup: group}
+	if group.ProviderData != nil && group.ProviderData.ServiceEndpointID != "" {
+		endpoints, err := s.Client.GetListOfServiceEndpoints(collection, project)
+		if err != nil {
+			writeTFSError(w, err)
+			return
+		}
+
+		for _, endpoint := range endpoints.ServiceEndpoints {
+			if endpoint.ID == group.ProviderData.ServiceEndpointID {
+				export.ServiceEndpointName = endpoint.Name
+			}
+		}
+	}
+
+	writeJSON(w, http.StatusOK, export)
+}
+
+// diffVariableGroups compares a variable group with another one (named by the 'with' parameter, in the project
+// named by the 'project' parameter).  At least one of them is required
+func (s *Server) diffVariableGroups(w http.ResponseWriter, r *http.Request, collection, project, name string) {
+	otherName := r.URL.Query().Get("with")
+	otherProject := r.URL.Query().Get("project")
+	if otherName == "" && otherProject == "" {
+		writeError(w, http.StatusBadRequest, "The 'with' parameter (another group) or the 'project' parameter (another project) is required")
+		return
+	}
+	if otherName == "" {
+		otherName = name
+	}
+	if otherProject == "" {
+		otherProject = project
+	}
+
+	from, err := s.Client.FindVariableGroup(collection, project, name)
+	if err != nil {
+		writeTFSError(w, err)
+		return
+	}
+
+	to, err := s.Client.FindVariableGroup(collection, otherProject, otherName)
+	if err != nil {
+		writeTFSError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, DiffResponse{
+		From:        GroupReference{Project: project, Name: from.Name, ID: from.ID},
+		To:          GroupReference{Project: otherProject, Name: to.Name, ID: to.ID},
+		Differences: tfs.DiffVariableGroups(from, to),
+	})
+}
+
+// copyVariableGroup copies a variable group, and returns the new group
+func (s *Server) copyVariableGroup(w http.ResponseWriter, r *http.Request, collection, project, name string) {
+	request := CopyRequest{}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid copy request: %s", err))
+			return
+		}
+	}
+
+	group, err := s.Client.FindVariableGroup(collection, project, name)
+	if err != nil {
+		writeTFSError(w, err)
+		return
+	}
+
+	if request.Name == "" {
+		request.Name = tfs.CopyName(group.Name)
+	}
+	if request.Project == "" {
+		request.Project = project
+	}
+
+	created, err := s.Client.CreateVariableGroup(collection, request.Project, group.Copy(request.Name))
+	if err != nil {
+		writeTFSError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// writeTFSError reports an error from the tfs client: 404 if something couldn't be found, and 502 otherwise
+func writeTFSError(w http.ResponseWriter, err error) {
+	if tfs.IsNotFound(err) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeError(w, http.StatusBadGateway, err.Error())
+}
+
+// writeError writes an error response
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	writeJSON(w, statusCode, ErrorResponse{Error: message})
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, statusCode int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(value)
+}
+
+// statusRecorder remembers the status code written, for the request log
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records the status code and writes it
+func (r *statusRecorder) WriteHeader(statusCode int) {
+	r.status = statusCode
+	r.ResponseWriter.WriteHeader(statusCode)
+}
@@ -0,0 +1,170 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danesparza/tfsutil/api"
+	"github.com/danesparza/tfsutil/tfs"
+	"github.com/danesparza/tfsutil/tfs/tfstest"
+)
+
+const testToken = "unicorn-token"
+
+// newTestAPI starts a fake TFS server (with two projects and two groups) and the API in front of it
+func newTestAPI() (*tfstest.Server, *httptest.Server) {
+	server := tfstest.NewServerWithGroup()
+	server.AddProject("DefaultCollection", tfs.Project{Name: "Mobile"})
+	server.AddVariableGroup("DefaultCollection", "Mobile", tfs.VariableGroup{
+		Name: "Unicorn variables",
+		Variables: map[string]tfs.Variable{
+			"Horn":  {Value: "glittery"},
+			"Wings": {Value: "yes"},
+		},
+	})
+
+	return server, httptest.NewServer(&api.Server{Client: server.Client(), Token: testToken})
+}
+
+// call makes a request to the API with the test token
+func call(t *testing.T, method, url string, body []byte) (*http.Response, []byte) {
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("Unable to create the request: %s", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+testToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s failed: %s", method, url, err)
+	}
+	defer resp.Body.Close()
+
+	data, _ := ioutil.ReadAll(resp.Body)
+	return resp, data
+}
+
+// Requests without the token should be refused before they reach TFS, except for the OpenAPI description
+func TestServer_NoToken_ReturnsUnauthorized(t *testing.T) {
+
+	//	Arrange
+	server, apiServer := newTestAPI()
+	defer server.Close()
+	defer apiServer.Close()
+
+	//	Act
+	resp, err := http.Get(apiServer.URL + "/api/collections/DefaultCollection/projects")
+	description, descriptionErr := http.Get(apiServer.URL + "/openapi.json")
+
+	//	Assert
+	if err != nil || descriptionErr != nil {
+		t.Fatalf("Expected no errors but got %v and %v", err, descriptionErr)
+	}
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without a token, but got %v", resp.Status)
+	}
+
+	if description.StatusCode != http.StatusOK {
+		t.Errorf("Expected the OpenAPI description without a token, but got %v", description.Status)
+	}
+
+	if len(server.Requests()) != 0 {
+		t.Errorf("Expected no requests to TFS, but got %v", server.Requests())
+	}
+}
+
+// The projects in a collection should be listed
+func TestServer_ListProjects_ReturnsProjects(t *testing.T) {
+
+	//	Arrange
+	server, apiServer := newTestAPI()
+	defer server.Close()
+	defer apiServer.Close()
+
+	//	Act
+	resp, body := call(t, http.MethodGet, apiServer.URL+"/api/collections/DefaultCollection/projects", nil)
+
+	//	Assert
+	projects := tfs.ProjectResponse{}
+	if err := json.Unmarshal(body, &projects); err != nil {
+		t.Fatalf("Unable to decode %s: %s", body, err)
+	}
+
+	if resp.StatusCode != http.StatusOK || projects.Count != 2 {
+		t.Errorf("Expected 2 projects but got %v: %s", resp.Status, body)
+	}
+}
+
+// The groups with the same name in two projects should be compared, variable by variable
+func TestServer_DiffAcrossProjects_ReturnsDifferences(t *testing.T) {
+
+	//	Arrange
+	server, apiServer := newTestAPI()
+	defer server.Close()
+	defer apiServer.Close()
+
+	//	Act
+	resp, body := call(t, http.MethodGet, apiServer.URL+"/api/collections/DefaultCollection/projects/Website/variablegroups/Unicorn%20variables/diff?project=Mobile", nil)
+
+	//	Assert
+	diff := api.DiffResponse{}
+	if err := json.Unmarshal(body, &diff); err != nil {
+		t.Fatalf("Unable to decode %s: %s", body, err)
+	}
+
+	if resp.StatusCode != http.StatusOK || len(diff.Differences) != 3 {
+		t.Fatalf("Expected 3 differences but got %v: %s", resp.Status, body)
+	}
+
+	expected := []string{"Horn changed", "Mane removed", "Wings added"}
+	for i, difference := range diff.Differences {
+		if difference.Name+" "+difference.Change != expected[i] {
+			t.Errorf("Expected '%s' but got '%s %s'", expected[i], difference.Name, difference.Change)
+		}
+	}
+}
+
+// Copying a group should create the copy, and return it
+func TestServer_CopyVariableGroup_CreatesGroup(t *testing.T) {
+
+	//	Arrange
+	server, apiServer := newTestAPI()
+	defer server.Close()
+	defer apiServer.Close()
+
+	//	Act
+	resp, body := call(t, http.MethodPost, apiServer.URL+"/api/collections/DefaultCollection/projects/Website/variablegroups/Unicorn%20variables/copy",
+		[]byte(`{"name": "Dragon variables"}`))
+
+	//	Assert
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected 201 but got %v: %s", resp.Status, body)
+	}
+
+	groups := tfstest.SortedGroupNames(server.VariableGroups("DefaultCollection", "Website"))
+	if len(groups) != 2 || groups[0] != "Dragon variables" {
+		t.Errorf("Expected the new group to be created, but got %v", groups)
+	}
+}
+
+// Exporting a group that doesn't exist should return a 404
+func TestServer_ExportMissingGroup_ReturnsNotFound(t *testing.T) {
+
+	//	Arrange
+	server, apiServer := newTestAPI()
+	defer server.Close()
+	defer apiServer.Close()
+
+	//	Act
+	resp, body := call(t, http.MethodGet, apiServer.URL+"/api/collections/DefaultCollection/projects/Website/variablegroups/Nothing/export", nil)
+
+	//	Assert
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 but got %v: %s", resp.Status, body)
+	}
+}
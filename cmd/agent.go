@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// agentCmd represents the agent base command
+var agentCmd = &cobra.Command{
+	Use:   "agent",
+	Short: "Agent and agent pool helpers",
+	Long:  `Operations to help with build agents and agent pools.  You can list them and enable or disable agents`,
+	Run: func(cmd *cobra.Command, args []string) {
+		//	This command on it's own should just show help
+		cmd.Help()
+	},
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+
+		//	Verify that we have a tfsurl and a pat
+		if strings.TrimSpace(viper.GetString("tfsurl")) == "" {
+			fmt.Printf("\nThis tool requires a TFS base url to operate.   \n\nPlease specify one on the command line or in the config file 'tfsutil.yml' \nFor help creating a config file, see the command 'tfsutil config create'\n")
+			exit(1)
+		}
+
+		if strings.TrimSpace(viper.GetString("pat")) == "" {
+			fmt.Printf("\nThis tool requires a TFS Personal Access Token (pat) for authentication.  \n\nPlease specify a pat on the command line or in the config file 'tfsutil.yml' \nFor help creating a config file, see the command 'tfsutil config create'\n")
+			exit(1)
+		}
+
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(agentCmd)
+}
+
+// findAgentPool finds a single agent pool by name in the given collection
+func findAgentPool(client tfs.Client, collection, name string) (tfs.AgentPool, error) {
+
+	retval, err := client.GetListOfAgentPools(collection)
+	if err != nil {
+		return tfs.AgentPool{}, fmt.Errorf("Finding agent pool: %s", err)
+	}
+
+	for _, pool := range retval.AgentPools {
+		if strings.EqualFold(pool.Name, name) {
+			return pool, nil
+		}
+	}
+
+	return tfs.AgentPool{}, fmt.Errorf("Sorry -- I couldn't find the agent pool '%s'", name)
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+var (
+	agentPool         string
+	agentCapabilities []string
+)
+
+// agentListCmd represents the agent list command
+var agentListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List agents",
+	Long: `Lists the agents in an agent pool, along with their status, version, enabled state and current job.
+
+Agents can be filtered by system or user capability.  Use --capability more than once
+to require several capabilities.
+
+Example:
+tfsutil agent list --pool Default --capability java --capability Agent.OS=Windows_NT
+
+`,
+	Run: agentlist,
+}
+
+func agentlist(cmd *cobra.Command, args []string) {
+
+	if agentPool == "" {
+		fatalln("[ERROR] Requires an agent pool (use --pool)")
+	}
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	pool, err := findAgentPool(client, viper.GetString("collection"), agentPool)
+	if err != nil {
+		fatalln("[ERROR] ", err)
+	}
+
+	//	Get the list of agents.  Report any errors
+	retval, err := client.GetListOfAgents(viper.GetString("collection"), pool.ID)
+	if err != nil {
+		fatalln("[ERROR] Agent list \n", err)
+	}
+
+	//	Filter the agents by capability
+	agents := []tfs.Agent{}
+	for _, agent := range retval.Agents {
+		matches := true
+		for _, capability := range agentCapabilities {
+			key, value := splitCapability(capability)
+			if !agent.HasCapability(key, value) {
+				matches = false
+			}
+		}
+
+		if matches {
+			agents = append(agents, agent)
+		}
+	}
+
+	//	Sort the agents
+	sort.Slice(agents, func(i, j int) bool {
+		return agents[i].Name < agents[j].Name
+	})
+
+	//	Begin the report:
+	fmt.Printf("\nCollection: %v", viper.GetString("collection"))
+	fmt.Printf("\nAgent pool: %v\n", pool.Name)
+	fmt.Printf("\nAgents found: %v\n================\n", len(agents))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Name\tStatus\tEnabled\tVersion\tCurrent job")
+	for _, agent := range agents {
+		job := ""
+		if agent.AssignedRequest != nil {
+			job = fmt.Sprintf("%s (%s)", agent.AssignedRequest.Definition.Name, agent.AssignedRequest.Owner.Name)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%v\t%s\t%s\n", agent.Name, agent.Status, agent.Enabled, agent.Version, job)
+	}
+	w.Flush()
+}
+
+// splitCapability splits a 'key=value' capability filter.  The value is blank if only a key was given
+func splitCapability(capability string) (string, string) {
+	parts := strings.SplitN(capability, "=", 2)
+	if len(parts) == 1 {
+		return strings.TrimSpace(parts[0]), ""
+	}
+	return strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+}
+
+func init() {
+	agentCmd.AddCommand(agentListCmd)
+
+	agentCmd.PersistentFlags().StringVar(&agentPool, "pool", "", "Agent pool name")
+	agentListCmd.Flags().StringArrayVar(&agentCapabilities, "capability", []string{}, "Only agents with this capability (key or key=value)")
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// agentPoolCmd represents the agent pool command
+var agentPoolCmd = &cobra.Command{
+	Use:   "pool",
+	Short: "Agent pool helpers",
+	Long:  `Operations to help with agent pools.  You can list them`,
+	Run: func(cmd *cobra.Command, args []string) {
+		//	This command on it's own should just show help
+		cmd.Help()
+	},
+}
+
+// agentPoolListCmd represents the agent pool list command
+var agentPoolListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List agent pools",
+	Long:  `Lists the agent pools in the collection, along with the number of agents in each pool`,
+	Run:   agentpoollist,
+}
+
+func agentpoollist(cmd *cobra.Command, args []string) {
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	//	Get the list of agent pools.  Report any errors
+	retval, err := client.GetListOfAgentPools(viper.GetString("collection"))
+	if err != nil {
+		fatalln("[ERROR] Agent pool list \n", err)
+	}
+
+	//	Sort the pools
+	sort.Slice(retval.AgentPools, func(i, j int) bool {
+		return retval.AgentPools[i].Name < retval.AgentPools[j].Name
+	})
+
+	//	Begin the report:
+	fmt.Printf("\nCollection: %v\n", viper.GetString("collection"))
+	fmt.Printf("\nAgent pools found: %v\n=====================\n", retval.Count)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tName\tAgents\tHosted")
+	for _, pool := range retval.AgentPools {
+		fmt.Fprintf(w, "%v\t%s\t%v\t%v\n", pool.ID, pool.Name, pool.Size, pool.IsHosted)
+	}
+	w.Flush()
+}
+
+func init() {
+	agentCmd.AddCommand(agentPoolCmd)
+	agentPoolCmd.AddCommand(agentPoolListCmd)
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// agentSetEnabledCmd represents the agent set-enabled command
+var agentSetEnabledCmd = &cobra.Command{
+	Use:   "set-enabled \"<agent name>\" <true|false>",
+	Short: "Enable or disable an agent",
+	Long: `Enables or disables an agent.  A disabled agent finishes its current job,
+but isn't given new ones -- so disable an agent to drain it for maintenance,
+and enable it again when you're done.
+
+Example:
+tfsutil agent set-enabled BUILD01 false --pool Default
+
+`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 2 {
+			return errors.New("Requires an agent name and true or false")
+		}
+		if _, err := strconv.ParseBool(args[1]); err != nil {
+			return fmt.Errorf("Expected true or false, but got '%s'", args[1])
+		}
+		return nil
+	},
+	Run: agentsetenabled,
+}
+
+func agentsetenabled(cmd *cobra.Command, args []string) {
+
+	if agentPool == "" {
+		fatalln("[ERROR] Requires an agent pool (use --pool)")
+	}
+
+	agentName := args[0]
+	enabled, _ := strconv.ParseBool(args[1])
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	pool, err := findAgentPool(client, viper.GetString("collection"), agentPool)
+	if err != nil {
+		fatalln("[ERROR] ", err)
+	}
+
+	//	Find the agent
+	retval, err := client.GetListOfAgents(viper.GetString("collection"), pool.ID)
+	if err != nil {
+		fatalln("[ERROR] Agent list \n", err)
+	}
+
+	var agent *tfs.Agent
+	for i := range retval.Agents {
+		if strings.EqualFold(retval.Agents[i].Name, agentName) {
+			agent = &retval.Agents[i]
+		}
+	}
+
+	if agent == nil {
+		fatalf("Sorry -- I couldn't find the agent '%s' in the pool '%s'", agentName, pool.Name)
+	}
+
+	//	Update the agent.  Report any errors
+	err = client.SetAgentEnabled(viper.GetString("collection"), pool.ID, agent.ID, enabled)
+	if err != nil {
+		fatalf("[ERROR] Updating the agent %s - \n %s", agent.Name, err)
+	}
+
+	state := "Disabled"
+	if enabled {
+		state = "Enabled"
+	}
+	fmt.Printf("\n%s %s (in %s)\n", state, agent.Name, pool.Name)
+
+	if !enabled && agent.AssignedRequest != nil {
+		fmt.Printf("The agent is still running %s -- it will finish that job first\n", agent.AssignedRequest.Definition.Name)
+	}
+}
+
+func init() {
+	agentCmd.AddCommand(agentSetEnabledCmd)
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// areaCmd represents the area path base command
+var areaCmd = &cobra.Command{
+	Use:   "area",
+	Short: "Area path helpers",
+	Long:  `Operations to help with area paths.  You can list and create them`,
+	Run: func(cmd *cobra.Command, args []string) {
+		//	This command on it's own should just show help
+		cmd.Help()
+	},
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+
+		//	Verify that we have a tfsurl and a pat
+		if strings.TrimSpace(viper.GetString("tfsurl")) == "" {
+			fmt.Printf("\nThis tool requires a TFS base url to operate.   \n\nPlease specify one on the command line or in the config file 'tfsutil.yml' \nFor help creating a config file, see the command 'tfsutil config create'\n")
+			exit(1)
+		}
+
+		if strings.TrimSpace(viper.GetString("pat")) == "" {
+			fmt.Printf("\nThis tool requires a TFS Personal Access Token (pat) for authentication.  \n\nPlease specify a pat on the command line or in the config file 'tfsutil.yml' \nFor help creating a config file, see the command 'tfsutil config create'\n")
+			exit(1)
+		}
+
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(areaCmd)
+}
+
+// ensureClassificationNode makes sure an area or iteration path (relative to the root) exists, creating it
+// (and any missing parents) if it doesn't.  The attributes are only used if the node is created.  The
+// boolean is true if the node was created
+func ensureClassificationNode(client tfs.Client, collection, project, structureGroup, nodePath string, attributes *tfs.ClassificationNodeAttributes) (tfs.ClassificationNode, bool, error) {
+
+	names := tfs.SplitClassificationPath(nodePath)
+	if len(names) == 0 {
+		return tfs.ClassificationNode{}, false, errors.New("Requires a path")
+	}
+
+	root, err := client.GetClassificationNode(collection, project, structureGroup, "", len(names))
+	if err != nil {
+		return tfs.ClassificationNode{}, false, fmt.Errorf("Getting the %s: %s", strings.ToLower(structureGroup), err)
+	}
+
+	//	If the complete path is already there, we're done
+	if node, ok := root.Find(nodePath); ok {
+		return node, false, nil
+	}
+
+	//	Otherwise, create each missing node in turn
+	var node tfs.ClassificationNode
+	for i, name := range names {
+		parentPath := strings.Join(names[:i], "/")
+		if existing, ok := root.Find(strings.Join(names[:i+1], "/")); ok {
+			node = existing
+			continue
+		}
+
+		newNode := tfs.ClassificationNode{Name: name}
+		if i == len(names)-1 {
+			newNode.Attributes = attributes
+		}
+
+		node, err = client.CreateClassificationNode(collection, project, structureGroup, parentPath, newNode)
+		if err != nil {
+			return tfs.ClassificationNode{}, false, fmt.Errorf("Creating '%s': %s", strings.Join(names[:i+1], "/"), err)
+		}
+	}
+
+	return node, true, nil
+}
+
+// printClassificationTree prints an area or iteration tree, indenting each node under its parent.  Iteration
+// trees also show the start and finish dates
+func printClassificationTree(root tfs.ClassificationNode, showDates bool) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if showDates {
+		fmt.Fprintln(w, "Path\tStart\tFinish")
+	}
+
+	for _, item := range root.Flatten() {
+		name := strings.Repeat("  ", item.Depth) + item.Node.Name
+		if !showDates {
+			fmt.Fprintln(w, name)
+			continue
+		}
+
+		start, finish := "", ""
+		if item.Node.Attributes != nil {
+			start = formatDate(item.Node.Attributes.StartDate)
+			finish = formatDate(item.Node.Attributes.FinishDate)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", name, start, finish)
+	}
+	w.Flush()
+}
+
+// formatDate formats an optional date
+func formatDate(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+	return date.UTC().Format("2006-01-02")
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// areaCreateCmd represents the area create command
+var areaCreateCmd = &cobra.Command{
+	Use:   "create \"<path>\"",
+	Short: "Create an area path",
+	Long: `Creates an area path.  The path is relative to the project, and any missing
+parents are created too.
+
+Example:
+tfsutil area create "Squad Unicorn/Frontend"
+
+`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("Requires an area path")
+		}
+		return nil
+	},
+	Run: areacreate,
+}
+
+func areacreate(cmd *cobra.Command, args []string) {
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	node, created, err := ensureClassificationNode(client, viper.GetString("collection"), viper.GetString("project"), tfs.StructureGroupAreas, args[0], nil)
+	if err != nil {
+		fatalln("[ERROR] ", err)
+	}
+
+	if !created {
+		fmt.Printf("\nThe area path %s already exists\n", node.Path)
+		return
+	}
+
+	fmt.Printf("\nCreated area path %s\n", node.Path)
+}
+
+func init() {
+	areaCmd.AddCommand(areaCreateCmd)
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+var areaListDepth int
+
+// areaListCmd represents the area list command
+var areaListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List area paths",
+	Long:  `Lists the area paths in the project as a tree`,
+	Run:   arealist,
+}
+
+func arealist(cmd *cobra.Command, args []string) {
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	//	Get the tree.  Report any errors
+	root, err := client.GetClassificationNode(viper.GetString("collection"), viper.GetString("project"), tfs.StructureGroupAreas, "", areaListDepth)
+	if err != nil {
+		fatalln("[ERROR] Area list \n", err)
+	}
+
+	//	Begin the report:
+	fmt.Printf("\nCollection: %v", viper.GetString("collection"))
+	fmt.Printf("\nProject: %v\n", viper.GetString("project"))
+	fmt.Printf("\nArea paths found: %v\n=======================\n", len(root.Flatten()))
+
+	printClassificationTree(root, false)
+}
+
+func init() {
+	areaCmd.AddCommand(areaListCmd)
+
+	areaListCmd.Flags().IntVar(&areaListDepth, "depth", 5, "How many levels of the tree to list")
+}
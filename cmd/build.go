@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// buildCmd represents the build base command
+var buildCmd = &cobra.Command{
+	Use:   "build",
+	Short: "Build helpers",
+	Long:  `Operations to help with builds.  You can list them and summarize them`,
+	Run: func(cmd *cobra.Command, args []string) {
+		//	This command on it's own should just show help
+		cmd.Help()
+	},
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+
+		//	Verify that we have a tfsurl and a pat
+		if strings.TrimSpace(viper.GetString("tfsurl")) == "" {
+			fmt.Printf("\nThis tool requires a TFS base url to operate.   \n\nPlease specify one on the command line or in the config file 'tfsutil.yml' \nFor help creating a config file, see the command 'tfsutil config create'\n")
+			exit(1)
+		}
+
+		if strings.TrimSpace(viper.GetString("pat")) == "" {
+			fmt.Printf("\nThis tool requires a TFS Personal Access Token (pat) for authentication.  \n\nPlease specify a pat on the command line or in the config file 'tfsutil.yml' \nFor help creating a config file, see the command 'tfsutil config create'\n")
+			exit(1)
+		}
+
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(buildCmd)
+}
@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+var (
+	buildDefinition   string
+	buildBranch       string
+	buildRequestedFor string
+	buildStatus       string
+	buildResult       string
+	buildSince        string
+	buildUntil        string
+	buildTop          int
+	buildSummary      bool
+)
+
+// buildListCmd represents the build list command
+var buildListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List builds",
+	Long: `Lists builds, along with their duration, queue time and result.
+
+Builds can be filtered by definition, branch, requesting user, status,
+result and a finish time window.  Use --summary to aggregate the pass rate
+and build durations for each definition instead of listing each build.  A
+summary includes every build in the window, unless --top is given.
+
+Example:
+tfsutil build list --definition "CI build" --branch master --since 7d --summary
+
+`,
+	Run: buildlist,
+}
+
+func buildlist(cmd *cobra.Command, args []string) {
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	//	Assemble the query from our flags
+	query := tfs.BuildQuery{
+		BranchName:   formatBranchName(buildBranch),
+		RequestedFor: buildRequestedFor,
+		StatusFilter: buildStatus,
+		ResultFilter: buildResult,
+		Top:          buildTop,
+	}
+
+	//	A summary covers every build in the time window, unless we've been given a limit
+	if buildSummary && !cmd.Flags().Changed("top") {
+		query.Top = 0
+	}
+
+	var err error
+	if buildSince != "" {
+		query.MinTime, err = parseTimeOrAge(buildSince)
+		if err != nil {
+			fatalf("[ERROR] Invalid --since value '%s' - \n %s", buildSince, err)
+		}
+	}
+
+	if buildUntil != "" {
+		query.MaxTime, err = parseTimeOrAge(buildUntil)
+		if err != nil {
+			fatalf("[ERROR] Invalid --until value '%s' - \n %s", buildUntil, err)
+		}
+	}
+
+	//	If we have a definition name, find the matching definition id(s)
+	if buildDefinition != "" {
+		defs, err := client.GetListOfBuildDefinitions(viper.GetString("collection"), viper.GetString("project"), buildDefinition)
+		if err != nil {
+			fatalln("[ERROR] Finding build definition \n", err)
+		}
+
+		if defs.Count < 1 {
+			fatalf("Sorry -- I couldn't find the build definition '%s'", buildDefinition)
+		}
+
+		for _, def := range defs.Definitions {
+			query.Definitions = append(query.Definitions, def.ID)
+		}
+	}
+
+	//	Get the list of builds.  Report any errors
+	retval, err := client.GetListOfBuilds(viper.GetString("collection"), viper.GetString("project"), query)
+	if err != nil {
+		fatalln("[ERROR] Build list \n", err)
+	}
+
+	//	Begin the report:
+	fmt.Printf("\nCollection: %v", viper.GetString("collection"))
+	fmt.Printf("\nProject: %v\n", viper.GetString("project"))
+
+	if buildSummary {
+		reportBuildSummary(retval.Builds)
+		if query.Top > 0 && retval.Count >= query.Top {
+			fmt.Printf("\nNOTE: Only the latest %v builds were summarized -- raise --top (or leave it out) to include them all\n", query.Top)
+		}
+		return
+	}
+
+	fmt.Printf("\nBuilds found: %v\n=================\n", retval.Count)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tDefinition\tNumber\tBranch\tRequested for\tFinished\tQueued\tDuration\tResult")
+	for _, build := range retval.Builds {
+		result := build.Result
+		if result == "" {
+			result = build.Status
+		}
+
+		finished := ""
+		if !build.FinishTime.IsZero() {
+			finished = build.FinishTime.Local().Format("2006-01-02 15:04")
+		}
+
+		fmt.Fprintf(w, "%v\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			build.ID,
+			build.Definition.Name,
+			build.BuildNumber,
+			strings.TrimPrefix(build.SourceBranch, "refs/heads/"),
+			build.RequestedFor.DisplayName,
+			finished,
+			formatDuration(build.QueueDuration()),
+			formatDuration(build.Duration()),
+			result)
+	}
+	w.Flush()
+}
+
+// reportBuildSummary prints the pass rate and duration summary for each definition
+func reportBuildSummary(builds []tfs.Build) {
+	summary := tfs.SummarizeBuilds(builds)
+
+	fmt.Printf("\nDefinitions found: %v\n======================\n", len(summary))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Definition\tBuilds\tPassed\tPass rate\tAvg\tP50\tP90\tP95")
+	for _, s := range summary {
+		fmt.Fprintf(w, "%s\t%v\t%v\t%.1f%%\t%s\t%s\t%s\t%s\n",
+			s.Definition,
+			s.Total,
+			s.Succeeded,
+			s.PassRate(),
+			formatDuration(s.Average()),
+			formatDuration(s.Percentile(50)),
+			formatDuration(s.Percentile(90)),
+			formatDuration(s.Percentile(95)))
+	}
+	w.Flush()
+}
+
+// formatBranchName expands a short branch name (like 'master') to a full ref name
+func formatBranchName(branch string) string {
+	if branch == "" || strings.HasPrefix(branch, "refs/") {
+		return branch
+	}
+	return "refs/heads/" + branch
+}
+
+// formatDuration formats a duration rounded to the second (or blank if there is no duration)
+func formatDuration(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	return d.Round(time.Second).String()
+}
+
+// parseAge parses an age like '90d', '2w' or any duration understood by time.ParseDuration
+func parseAge(age string) (time.Duration, error) {
+	age = strings.TrimSpace(age)
+
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if strings.HasSuffix(age, suffix) {
+			n, err := strconv.Atoi(strings.TrimSuffix(age, suffix))
+			if err != nil {
+				return 0, fmt.Errorf("Unable to parse age '%s': %s", age, err)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+
+	return time.ParseDuration(age)
+}
+
+// parseTimeOrAge parses either a date (2006-01-02), an RFC3339 time, or an age relative to now (like '7d')
+func parseTimeOrAge(value string) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	age, err := parseAge(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Expected a date (2006-01-02), a time (RFC3339) or an age (like 7d): %s", err)
+	}
+
+	return time.Now().Add(-age), nil
+}
+
+func init() {
+	buildCmd.AddCommand(buildListCmd)
+
+	buildListCmd.Flags().StringVar(&buildDefinition, "definition", "", "Build definition name")
+	buildListCmd.Flags().StringVar(&buildBranch, "branch", "", "Source branch (like 'master' or 'refs/heads/master')")
+	buildListCmd.Flags().StringVar(&buildRequestedFor, "requestedfor", "", "Only builds requested for this user")
+	buildListCmd.Flags().StringVar(&buildStatus, "status", "", "Build status: inProgress/completed/cancelling/postponed/notStarted/all")
+	buildListCmd.Flags().StringVar(&buildResult, "result", "", "Build result: succeeded/partiallySucceeded/failed/canceled")
+	buildListCmd.Flags().StringVar(&buildSince, "since", "", "Only builds finished after this date or age (like 2018-06-01 or 7d)")
+	buildListCmd.Flags().StringVar(&buildUntil, "until", "", "Only builds finished before this date or age")
+	buildListCmd.Flags().IntVar(&buildTop, "top", 200, "Maximum number of builds to get (a --summary includes every build, unless this is given)")
+	buildListCmd.Flags().BoolVar(&buildSummary, "summary", false, "Summarize pass rate and duration by definition")
+}
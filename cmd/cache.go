@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// cacheCmd represents the cache base command
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Local response cache helpers",
+	Long: `Operations to help with the local response cache.  The lists of projects,
+collections and variable groups from TFS are cached for 'cachettl' (5m by default,
+set it in the config file).  After that they are
+checked with TFS before they are used again.  Use --no-cache with any command to
+skip the cache`,
+	Run: func(cmd *cobra.Command, args []string) {
+		//	This command on it's own should just show help
+		cmd.Help()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// cacheClearCmd represents the cache clear command
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Clear the local response cache",
+	Long:  `Removes every cached response (for every server and set of credentials)`,
+	Run:   cacheclear,
+}
+
+func cacheclear(cmd *cobra.Command, args []string) {
+
+	dir, err := getCacheDir()
+	if err != nil {
+		fatalln("[ERROR] Finding the cache directory \n", err)
+	}
+
+	removed, err := tfs.ClearCache(dir)
+	if err != nil {
+		fatalln("[ERROR] Clearing the cache \n", err)
+	}
+
+	fmt.Printf("\nRemoved %v cached responses from %s\n", removed, dir)
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheClearCmd)
+}
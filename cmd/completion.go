@@ -0,0 +1,274 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// completionNamesTTL is how long names fetched from TFS for completion are kept in memory.  Between
+// tfsutil processes, the response cache keeps them (for 'cachettl')
+const completionNamesTTL = time.Minute
+
+// completionShells are the shells completion scripts can be generated for
+var completionShells = []string{"bash", "zsh", "fish", "powershell"}
+
+// variableGroupArgCommands are the commands whose first argument is a variable group name.  They
+// complete it with completeVariableGroupArg
+var variableGroupArgCommands = map[string]bool{
+	"tfsutil vg authorize":   true,
+	"tfsutil vg copy":        true,
+	"tfsutil vg export":      true,
+	"tfsutil vg permissions": true,
+	"tfsutil vg set":         true,
+	"tfsutil vg usage":       true,
+}
+
+var completionInstall bool
+
+// completionCmd represents the completion command
+var completionCmd = &cobra.Command{
+	Use:   "completion bash|zsh|fish|powershell",
+	Short: "Generate or install shell completion scripts",
+	Long: `Generates the completion script for the given shell.  Completion covers commands, flags,
+project names (--project), collection names (--collection) and variable group names (vg copy and
+the other vg commands that take a group name), fetched from TFS.
+
+Use --install to save the script where the shell will find it:
+
+  bash        ~/.local/share/bash-completion/completions/tfsutil (needs bash-completion 2)
+  zsh         ~/.zsh/completions/_tfsutil (add ~/.zsh/completions to fpath before compinit)
+  fish        ~/.config/fish/completions/tfsutil.fish
+  powershell  ~/.config/powershell/tfsutil.ps1 (add '. ~/.config/powershell/tfsutil.ps1' to $PROFILE)
+
+Or load it for the current session only:
+
+  source <(tfsutil completion bash)
+`,
+	ValidArgs: completionShells,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return errors.New("Requires a shell: bash, zsh, fish or powershell")
+		}
+		for _, shell := range completionShells {
+			if args[0] == shell {
+				return nil
+			}
+		}
+		return fmt.Errorf("Unsupported shell '%s' -- use bash, zsh, fish or powershell", args[0])
+	},
+	Run: completion,
+}
+
+func completion(cmd *cobra.Command, args []string) {
+
+	//	Generate the script
+	script := bytes.Buffer{}
+	var err error
+	switch args[0] {
+	case "bash":
+		err = rootCmd.GenBashCompletionV2(&script, true)
+	case "zsh":
+		err = rootCmd.GenZshCompletion(&script)
+	case "fish":
+		err = rootCmd.GenFishCompletion(&script, true)
+	case "powershell":
+		err = rootCmd.GenPowerShellCompletionWithDesc(&script)
+	}
+	if err != nil {
+		fatalln("[ERROR] Generating the completion script \n", err)
+	}
+
+	if !completionInstall {
+		os.Stdout.Write(script.Bytes())
+		return
+	}
+
+	//	Install it where the shell looks for completions
+	file, err := getCompletionFile(args[0])
+	if err != nil {
+		fatalln("[ERROR] Finding where to install the completion script \n", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		fatalln("[ERROR] Installing the completion script \n", err)
+	}
+
+	if err := ioutil.WriteFile(file, script.Bytes(), 0644); err != nil {
+		fatalln("[ERROR] Installing the completion script \n", err)
+	}
+
+	fmt.Printf("\nInstalled the %s completion script to %s\n", args[0], file)
+	switch args[0] {
+	case "zsh":
+		fmt.Printf("Make sure %s is in your fpath before compinit runs (in ~/.zshrc)\n", filepath.Dir(file))
+	case "powershell":
+		fmt.Printf("Add this line to your PowerShell profile ($PROFILE) to load it:\n. %s\n", file)
+	}
+	fmt.Println("Start a new shell to use it")
+}
+
+// getCompletionFile gets the file a shell's completion script is installed to
+func getCompletionFile(shell string) (string, error) {
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", err
+	}
+
+	switch shell {
+	case "bash":
+		return filepath.Join(home, ".local", "share", "bash-completion", "completions", "tfsutil"), nil
+	case "zsh":
+		return filepath.Join(home, ".zsh", "completions", "_tfsutil"), nil
+	case "fish":
+		return filepath.Join(home, ".config", "fish", "completions", "tfsutil.fish"), nil
+	}
+	return filepath.Join(home, ".config", "powershell", "tfsutil.ps1"), nil
+}
+
+// completeProjects completes project names (for --project)
+func completeProjects(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	client, ok := completionClient()
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	names, _ := projectNames(client, viper.GetString("collection"))
+	return matchingNames(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeCollections completes collection names (for --collection)
+func completeCollections(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	client, ok := completionClient()
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	names, _ := collectionNames(client)
+	return matchingNames(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeVariableGroupArg completes the variable group name argument of the vg commands
+func completeVariableGroupArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	client, ok := completionClient()
+	if !ok || len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	names, _ := variableGroupNames(client, viper.GetString("collection"), viper.GetString("project"))
+	return matchingNames(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completionClient creates a client for completion, if we have a tfsurl and a pat
+func completionClient() (tfs.Client, bool) {
+	client := tfs.Client{TfsURL: viper.GetString("tfsurl")}
+	ok := strings.TrimSpace(client.TfsURL) != "" && strings.TrimSpace(viper.GetString("pat")) != ""
+	return client, ok
+}
+
+// matchingNames gets the names that start with what's been typed so far (ignoring case)
+func matchingNames(names []string, toComplete string) []string {
+	retval := []string{}
+	for _, name := range names {
+		if strings.HasPrefix(strings.ToLower(name), strings.ToLower(toComplete)) {
+			retval = append(retval, name)
+		}
+	}
+	return retval
+}
+
+// completionNames keeps the names fetched for completion, by kind, collection and project
+var completionNames = struct {
+	sync.Mutex
+	lists map[string]completionNameList
+}{lists: map[string]completionNameList{}}
+
+// completionNameList is a list of names fetched from TFS for completion
+type completionNameList struct {
+	names   []string
+	fetched time.Time
+}
+
+// projectNames gets the names of the projects in a collection
+func projectNames(client tfs.Client, collection string) ([]string, error) {
+	return cachedCompletionNames("projects|"+collection, func() ([]string, error) {
+		retval := []string{}
+		projects, err := client.GetListOfProjects(collection)
+		for _, project := range projects.Projects {
+			retval = append(retval, project.Name)
+		}
+		return retval, err
+	})
+}
+
+// collectionNames gets the names of the project collections on the server.  If the server can't list them
+// (VSTS can't), the collection in use is the only one
+func collectionNames(client tfs.Client) ([]string, error) {
+	return cachedCompletionNames("collections", func() ([]string, error) {
+		collections, err := client.GetListOfProjectCollections()
+		if err != nil {
+			log.Printf("[DEBUG] Unable to list project collections: %s", err)
+			return []string{viper.GetString("collection")}, nil
+		}
+
+		retval := []string{}
+		for _, collection := range collections.Collections {
+			retval = append(retval, collection.Name)
+		}
+		return retval, nil
+	})
+}
+
+// variableGroupNames gets the names of the variable groups in a project
+func variableGroupNames(client tfs.Client, collection, project string) ([]string, error) {
+	if project == "" {
+		return nil, errors.New("A project is required to list variable groups")
+	}
+
+	return cachedCompletionNames("groups|"+collection+"|"+project, func() ([]string, error) {
+		retval := []string{}
+		groups, err := client.GetListOfVariableGroups(collection, project)
+		for _, group := range groups.VariableGroups {
+			retval = append(retval, group.Name)
+		}
+		return retval, err
+	})
+}
+
+// cachedCompletionNames gets a list of names, fetching it again if it's older than completionNamesTTL
+func cachedCompletionNames(key string, fetch func() ([]string, error)) ([]string, error) {
+	completionNames.Lock()
+	defer completionNames.Unlock()
+
+	if cached, found := completionNames.lists[key]; found && time.Since(cached.fetched) < completionNamesTTL {
+		return cached.names, nil
+	}
+
+	names, err := fetch()
+	if err != nil {
+		log.Printf("[DEBUG] Unable to get names for completion: %s", err)
+		return nil, err
+	}
+
+	completionNames.lists[key] = completionNameList{names: names, fetched: time.Now()}
+	return names, nil
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+
+	completionCmd.Flags().BoolVar(&completionInstall, "install", false, "Install the script where the shell will find it (instead of printing it)")
+}
@@ -3,7 +3,6 @@ package cmd
 import (
 	"fmt"
 	"io/ioutil"
-	"log"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -22,7 +21,7 @@ To create a new config file, use 'config create'`,
 		//	Read the config file
 		dat, err := ioutil.ReadFile(viper.ConfigFileUsed())
 		if err != nil {
-			log.Fatal(err)
+			fatal(err)
 		}
 
 		//	Print the config file
@@ -12,6 +12,14 @@ tfsurl: http://YOURSERVER:8080/tfs
 pat: YOUR_PERSONAL_ACCESS_TOKEN
 collection: OPTIONAL_DEFAULT_COLLECTION
 project: OPTIONAL_DEFAULT_PROJECT
+# releaseurl: OPTIONAL_RELEASE_MANAGEMENT_URL
+# cachettl: 5m
+# parallel: 4
+# ratelimit: 10
+# servetoken: TOKEN_FOR_TFSUTIL_SERVE
+# mcp:
+#   allow:
+#     - copy_variable_group
 `)
 
 // createCmd represents the create command
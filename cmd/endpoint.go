@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// endpointCmd represents the service endpoint base command
+var endpointCmd = &cobra.Command{
+	Use:   "endpoint",
+	Short: "Service endpoint helpers",
+	Long:  `Operations to help with service endpoints (service connections).  You can list, show, export and copy them`,
+	Run: func(cmd *cobra.Command, args []string) {
+		//	This command on it's own should just show help
+		cmd.Help()
+	},
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+
+		//	Verify that we have a tfsurl and a pat
+		if strings.TrimSpace(viper.GetString("tfsurl")) == "" {
+			fmt.Printf("\nThis tool requires a TFS base url to operate.   \n\nPlease specify one on the command line or in the config file 'tfsutil.yml' \nFor help creating a config file, see the command 'tfsutil config create'\n")
+			exit(1)
+		}
+
+		if strings.TrimSpace(viper.GetString("pat")) == "" {
+			fmt.Printf("\nThis tool requires a TFS Personal Access Token (pat) for authentication.  \n\nPlease specify a pat on the command line or in the config file 'tfsutil.yml' \nFor help creating a config file, see the command 'tfsutil config create'\n")
+			exit(1)
+		}
+
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(endpointCmd)
+}
+
+// findServiceEndpoint finds a single service endpoint by name in the given collection and project
+func findServiceEndpoint(client tfs.Client, collection, project, name string) (tfs.ServiceEndpoint, error) {
+
+	retval, err := client.GetListOfServiceEndpoints(collection, project)
+	if err != nil {
+		return tfs.ServiceEndpoint{}, fmt.Errorf("Finding service endpoint: %s", err)
+	}
+
+	for _, endpoint := range retval.ServiceEndpoints {
+		if strings.EqualFold(endpoint.Name, name) || endpoint.ID == name {
+			return endpoint, nil
+		}
+	}
+
+	return tfs.ServiceEndpoint{}, fmt.Errorf("Sorry -- I couldn't find the service endpoint '%s'", name)
+}
+
+// requireEndpointArg makes sure a service endpoint name was passed
+func requireEndpointArg(cmd *cobra.Command, args []string) error {
+	if len(args) < 1 {
+		return errors.New("Requires a service endpoint name")
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+var (
+	endpointCopyName         string
+	endpointCopyToProject    string
+	endpointCopyToCollection string
+	endpointCopySecretsFile  string
+)
+
+// endpointCopyCmd represents the endpoint copy command
+var endpointCopyCmd = &cobra.Command{
+	Use:   "copy \"<name>\"",
+	Short: "Copy a service endpoint",
+	Long: `Copies a service endpoint to another project (or collection).
+
+TFS never returns secret authorization parameters (like passwords or keys), so
+supply them with a local secrets file.  The secrets file is keyed by endpoint name:
+
+endpoints:
+  "Production Azure":
+    serviceprincipalkey: YOUR_KEY
+
+Example:
+tfsutil endpoint copy "Production Azure" --to-project Website --secrets secrets.yml
+
+`,
+	Args: requireEndpointArg,
+	Run:  endpointcopy,
+}
+
+func endpointcopy(cmd *cobra.Command, args []string) {
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	endpoint, err := findServiceEndpoint(client, viper.GetString("collection"), viper.GetString("project"), args[0])
+	if err != nil {
+		fatalln("[ERROR] ", err)
+	}
+
+	//	Figure out where we're copying to
+	toCollection := endpointCopyToCollection
+	if toCollection == "" {
+		toCollection = viper.GetString("collection")
+	}
+
+	toProject := endpointCopyToProject
+	if toProject == "" {
+		toProject = viper.GetString("project")
+	}
+
+	//	Compose the new endpoint
+	endpointCopy := endpoint
+	endpointCopy.ID = ""
+	endpointCopy.Owner = ""
+	if endpointCopyName != "" {
+		endpointCopy.Name = endpointCopyName
+	}
+
+	if endpointCopy.Name == endpoint.Name && toCollection == viper.GetString("collection") && toProject == viper.GetString("project") {
+		fatalln("[ERROR] The copy needs a new --name, or a different --to-project or --to-collection")
+	}
+
+	//	Fill in the credentials from the secrets file
+	endpointCopy.Authorization.Parameters = map[string]string{}
+	for key, value := range endpoint.Authorization.Parameters {
+		endpointCopy.Authorization.Parameters[key] = value
+	}
+
+	if endpointCopySecretsFile != "" {
+		secrets, err := loadEndpointSecrets(endpointCopySecretsFile, endpoint.Name)
+		if err != nil {
+			fatalln("[ERROR] Reading secrets file \n", err)
+		}
+
+		for key, value := range secrets {
+			endpointCopy.Authorization.Parameters[key] = value
+		}
+	}
+
+	for key, value := range endpointCopy.Authorization.Parameters {
+		if value == "" {
+			log.Printf("[WARN] The authorization parameter '%s' is blank.  You may need to set it in TFS (or with --secrets)", key)
+		}
+	}
+
+	//	Create the copy.  Report any errors
+	created, err := client.CreateServiceEndpoint(toCollection, toProject, endpointCopy)
+	if err != nil {
+		fatalf("[ERROR] Copying the service endpoint %s - \n %s", endpoint.Name, err)
+	}
+
+	fmt.Printf("\nCopied \n %s \nto \n %s (in %s/%s)\n", endpoint.Name, created.Name, toCollection, toProject)
+}
+
+// loadEndpointSecrets reads the authorization parameters for the named endpoint from a secrets file
+func loadEndpointSecrets(secretsFile, endpointName string) (map[string]string, error) {
+	retval := map[string]string{}
+
+	v := viper.New()
+	v.SetConfigFile(secretsFile)
+	if err := v.ReadInConfig(); err != nil {
+		return retval, err
+	}
+
+	endpoints, _ := v.Get("endpoints").(map[string]interface{})
+	for name, params := range endpoints {
+		if !strings.EqualFold(name, endpointName) {
+			continue
+		}
+
+		values, ok := params.(map[string]interface{})
+		if !ok {
+			return retval, fmt.Errorf("The secrets for '%s' should be a map of authorization parameters", name)
+		}
+
+		for key, value := range values {
+			retval[key] = fmt.Sprint(value)
+		}
+	}
+
+	if len(retval) == 0 {
+		log.Printf("[WARN] There are no secrets for '%s' in %s", endpointName, secretsFile)
+	}
+
+	return retval, nil
+}
+
+func init() {
+	endpointCmd.AddCommand(endpointCopyCmd)
+
+	endpointCopyCmd.Flags().StringVar(&endpointCopyName, "name", "", "Name of the new service endpoint (default is the same name)")
+	endpointCopyCmd.Flags().StringVar(&endpointCopyToProject, "to-project", "", "Project to copy to (default is the current project)")
+	endpointCopyCmd.Flags().StringVar(&endpointCopyToCollection, "to-collection", "", "Collection to copy to (default is the current collection)")
+	endpointCopyCmd.Flags().StringVar(&endpointCopySecretsFile, "secrets", "", "Local secrets file with the authorization parameters")
+}
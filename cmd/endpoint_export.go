@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+var endpointExportFile string
+
+// endpointExportCmd represents the endpoint export command
+var endpointExportCmd = &cobra.Command{
+	Use:   "export \"<name>\"",
+	Short: "Export a service endpoint",
+	Long: `Exports a service endpoint as JSON.  The values of all authorization
+parameters are left out, so the export is safe to share.
+
+Example:
+tfsutil endpoint export "Production Azure" --file production-azure.json
+
+`,
+	Args: requireEndpointArg,
+	Run:  endpointexport,
+}
+
+func endpointexport(cmd *cobra.Command, args []string) {
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	endpoint, err := findServiceEndpoint(client, viper.GetString("collection"), viper.GetString("project"), args[0])
+	if err != nil {
+		fatalln("[ERROR] ", err)
+	}
+
+	//	Leave the credentials (and anything specific to this project) out
+	export := endpoint.WithoutCredentials()
+	export.ID = ""
+	export.Owner = ""
+
+	formatted, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		fatalln("[ERROR] Formatting service endpoint \n", err)
+	}
+
+	//	If we don't have a file, just write it out
+	if endpointExportFile == "" {
+		fmt.Println(string(formatted))
+		return
+	}
+
+	if err := ioutil.WriteFile(endpointExportFile, formatted, 0644); err != nil {
+		fatalf("[ERROR] Writing %s - \n %s", endpointExportFile, err)
+	}
+
+	fmt.Printf("\nExported '%s' to %s\n", endpoint.Name, endpointExportFile)
+}
+
+func init() {
+	endpointCmd.AddCommand(endpointExportCmd)
+
+	endpointExportCmd.Flags().StringVarP(&endpointExportFile, "file", "f", "", "File to export to (default is to write to the console)")
+}
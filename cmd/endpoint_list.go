@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// endpointListCmd represents the endpoint list command
+var endpointListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List service endpoints",
+	Long:  `Lists service endpoints, along with their type, url and authorization scheme`,
+	Run:   endpointlist,
+}
+
+func endpointlist(cmd *cobra.Command, args []string) {
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	//	Get the list of service endpoints.  Report any errors
+	retval, err := client.GetListOfServiceEndpoints(viper.GetString("collection"), viper.GetString("project"))
+	if err != nil {
+		fatalln("[ERROR] Service endpoint list \n", err)
+	}
+
+	//	Sort the endpoints
+	sort.Slice(retval.ServiceEndpoints, func(i, j int) bool {
+		return retval.ServiceEndpoints[i].Name < retval.ServiceEndpoints[j].Name
+	})
+
+	//	Begin the report:
+	fmt.Printf("\nCollection: %v", viper.GetString("collection"))
+	fmt.Printf("\nProject: %v\n", viper.GetString("project"))
+	fmt.Printf("\nService endpoints found: %v\n===========================\n", retval.Count)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Name\tType\tUrl\tScheme\tReady")
+	for _, endpoint := range retval.ServiceEndpoints {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%v\n", endpoint.Name, endpoint.Type, endpoint.URL, endpoint.Authorization.Scheme, endpoint.IsReady)
+	}
+	w.Flush()
+}
+
+func init() {
+	endpointCmd.AddCommand(endpointListCmd)
+}
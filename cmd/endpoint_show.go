@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// endpointShowCmd represents the endpoint show command
+var endpointShowCmd = &cobra.Command{
+	Use:   "show \"<name>\"",
+	Short: "Show a service endpoint",
+	Long: `Shows a service endpoint and its settings.  TFS never returns secret
+authorization parameters, so they are shown as blank.`,
+	Args: requireEndpointArg,
+	Run:  endpointshow,
+}
+
+func endpointshow(cmd *cobra.Command, args []string) {
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	endpoint, err := findServiceEndpoint(client, viper.GetString("collection"), viper.GetString("project"), args[0])
+	if err != nil {
+		fatalln("[ERROR] ", err)
+	}
+
+	fmt.Printf("\nService endpoint: %s (id %s)\n", endpoint.Name, endpoint.ID)
+	fmt.Printf("Type: %s\n", endpoint.Type)
+	fmt.Printf("Url: %s\n", endpoint.URL)
+	if endpoint.Description != "" {
+		fmt.Printf("Description: %s\n", endpoint.Description)
+	}
+	fmt.Printf("Ready: %v\n", endpoint.IsReady)
+	fmt.Printf("Created by: %s\n", endpoint.CreatedBy.DisplayName)
+
+	fmt.Printf("\nAuthorization: %s\n==================\n", endpoint.Authorization.Scheme)
+	printStringMap(endpoint.Authorization.Parameters)
+
+	fmt.Printf("\nData\n==================\n")
+	printStringMap(endpoint.Data)
+}
+
+// printStringMap prints a sorted list of keys and values
+func printStringMap(items map[string]string) {
+	keys := []string{}
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fmt.Printf("%s = %s\n", key, items[key])
+	}
+}
+
+func init() {
+	endpointCmd.AddCommand(endpointShowCmd)
+}
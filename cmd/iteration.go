@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// iterationCmd represents the iteration path base command
+var iterationCmd = &cobra.Command{
+	Use:   "iteration",
+	Short: "Iteration path helpers",
+	Long:  `Operations to help with iteration paths (sprints).  You can list and create them`,
+	Run: func(cmd *cobra.Command, args []string) {
+		//	This command on it's own should just show help
+		cmd.Help()
+	},
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+
+		//	Verify that we have a tfsurl and a pat
+		if strings.TrimSpace(viper.GetString("tfsurl")) == "" {
+			fmt.Printf("\nThis tool requires a TFS base url to operate.   \n\nPlease specify one on the command line or in the config file 'tfsutil.yml' \nFor help creating a config file, see the command 'tfsutil config create'\n")
+			exit(1)
+		}
+
+		if strings.TrimSpace(viper.GetString("pat")) == "" {
+			fmt.Printf("\nThis tool requires a TFS Personal Access Token (pat) for authentication.  \n\nPlease specify a pat on the command line or in the config file 'tfsutil.yml' \nFor help creating a config file, see the command 'tfsutil config create'\n")
+			exit(1)
+		}
+
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(iterationCmd)
+}
+
+// iterationAttributes parses the start and finish dates (like 2019-01-07) of an iteration.  Both are optional
+func iterationAttributes(start, finish string) (*tfs.ClassificationNodeAttributes, error) {
+	if start == "" && finish == "" {
+		return nil, nil
+	}
+
+	if start == "" || finish == "" {
+		return nil, errors.New("An iteration needs both a start and a finish date")
+	}
+
+	startDate, err := time.Parse("2006-01-02", start)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid start date '%s' (expected YYYY-MM-DD)", start)
+	}
+
+	finishDate, err := time.Parse("2006-01-02", finish)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid finish date '%s' (expected YYYY-MM-DD)", finish)
+	}
+
+	if finishDate.Before(startDate) {
+		return nil, fmt.Errorf("The finish date (%s) is before the start date (%s)", finish, start)
+	}
+
+	return &tfs.ClassificationNodeAttributes{StartDate: &startDate, FinishDate: &finishDate}, nil
+}
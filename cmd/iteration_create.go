@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+var (
+	iterationCreateStart  string
+	iterationCreateFinish string
+)
+
+// iterationCreateCmd represents the iteration create command
+var iterationCreateCmd = &cobra.Command{
+	Use:   "create \"<path>\"",
+	Short: "Create an iteration path",
+	Long: `Creates an iteration path (like a sprint), with optional start and finish dates.
+The path is relative to the project, and any missing parents are created too.
+
+Example:
+tfsutil iteration create "Release 3/Sprint 12" --start 2019-01-07 --finish 2019-01-18
+
+`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("Requires an iteration path")
+		}
+		return nil
+	},
+	Run: iterationcreate,
+}
+
+func iterationcreate(cmd *cobra.Command, args []string) {
+
+	attributes, err := iterationAttributes(iterationCreateStart, iterationCreateFinish)
+	if err != nil {
+		fatalln("[ERROR] ", err)
+	}
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	node, created, err := ensureClassificationNode(client, viper.GetString("collection"), viper.GetString("project"), tfs.StructureGroupIterations, args[0], attributes)
+	if err != nil {
+		fatalln("[ERROR] ", err)
+	}
+
+	if !created {
+		fmt.Printf("\nThe iteration path %s already exists\n", node.Path)
+		return
+	}
+
+	fmt.Printf("\nCreated iteration path %s", node.Path)
+	if attributes != nil {
+		fmt.Printf(" (%s to %s)", formatDate(attributes.StartDate), formatDate(attributes.FinishDate))
+	}
+	fmt.Println()
+}
+
+func init() {
+	iterationCmd.AddCommand(iterationCreateCmd)
+
+	iterationCreateCmd.Flags().StringVar(&iterationCreateStart, "start", "", "Start date (YYYY-MM-DD)")
+	iterationCreateCmd.Flags().StringVar(&iterationCreateFinish, "finish", "", "Finish date (YYYY-MM-DD)")
+}
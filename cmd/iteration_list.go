@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+var iterationListDepth int
+
+// iterationListCmd represents the iteration list command
+var iterationListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List iteration paths",
+	Long:  `Lists the iteration paths in the project as a tree`,
+	Run:   iterationlist,
+}
+
+func iterationlist(cmd *cobra.Command, args []string) {
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	//	Get the tree.  Report any errors
+	root, err := client.GetClassificationNode(viper.GetString("collection"), viper.GetString("project"), tfs.StructureGroupIterations, "", iterationListDepth)
+	if err != nil {
+		fatalln("[ERROR] Iteration list \n", err)
+	}
+
+	//	Begin the report:
+	fmt.Printf("\nCollection: %v", viper.GetString("collection"))
+	fmt.Printf("\nProject: %v\n", viper.GetString("project"))
+	fmt.Printf("\nIteration paths found: %v\n=======================\n", len(root.Flatten()))
+
+	printClassificationTree(root, true)
+}
+
+func init() {
+	iterationCmd.AddCommand(iterationListCmd)
+
+	iterationListCmd.Flags().IntVar(&iterationListDepth, "depth", 5, "How many levels of the tree to list")
+}
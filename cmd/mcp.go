@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/mcp"
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// mcpCmd represents the mcp command
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Serve tfsutil tools to editor assistants (Model Context Protocol)",
+	Long: `Starts a Model Context Protocol server on stdin and stdout (JSON-RPC 2.0, one message per
+line), so editor assistants can query TFS.  Configure your editor to run 'tfsutil mcp'.
+
+These tools are always available (they don't change anything):
+
+  list_projects, list_variable_groups, show_variable_group, diff_variable_groups
+
+Tools that change things (copy_variable_group, set_variable) are only available if they're
+allowed in the config file:
+
+  mcp:
+    allow:
+      - copy_variable_group
+
+Tool calls that don't name a collection or project use the ones from the config file (or -c/-p).
+Logs are written to stderr, so they don't get in the way of the protocol`,
+	Run: mcpserve,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+
+		//	Verify that we have a tfsurl and a pat.  Stdout is for the protocol, so complain on stderr
+		if strings.TrimSpace(viper.GetString("tfsurl")) == "" {
+			fmt.Fprintf(os.Stderr, "\nThis tool requires a TFS base url to operate.   \n\nPlease specify one on the command line or in the config file 'tfsutil.yml' \nFor help creating a config file, see the command 'tfsutil config create'\n")
+			exit(1)
+		}
+
+		if strings.TrimSpace(viper.GetString("pat")) == "" {
+			fmt.Fprintf(os.Stderr, "\nThis tool requires a TFS Personal Access Token (pat) for authentication.  \n\nPlease specify a pat on the command line or in the config file 'tfsutil.yml' \nFor help creating a config file, see the command 'tfsutil config create'\n")
+			exit(1)
+		}
+
+	},
+}
+
+func mcpserve(cmd *cobra.Command, args []string) {
+
+	server := &mcp.Server{
+		Client:     tfs.Client{TfsURL: viper.GetString("tfsurl")},
+		Collection: viper.GetString("collection"),
+		Project:    viper.GetString("project"),
+		Allow:      viper.GetStringSlice("mcp.allow"),
+	}
+
+	log.Printf("[INFO] Serving the Model Context Protocol on stdin/stdout (allowed mutating tools: %v)", server.Allow)
+	if err := server.Serve(os.Stdin, os.Stdout); err != nil {
+		fatalln("[ERROR] Serving the Model Context Protocol \n", err)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(mcpCmd)
+}
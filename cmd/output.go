@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// outputFormats are the formats understood by writeRows
+var outputFormats = []string{"table", "json", "csv", "tsv"}
+
+// writeRows writes a list of rows to stdout in the given format (table, json, csv or tsv).
+// For json, each row is written as an object keyed by the headers
+func writeRows(format string, headers []string, rows [][]string) error {
+	return writeRowsTo(os.Stdout, format, headers, rows)
+}
+
+// writeRowsTo writes a list of rows to the given writer in the given format
+func writeRowsTo(out io.Writer, format string, headers []string, rows [][]string) error {
+	switch strings.ToLower(format) {
+	case "", "table":
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, strings.Join(headers, "\t"))
+		for _, row := range rows {
+			fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+		return w.Flush()
+
+	case "json":
+		items := []map[string]string{}
+		for _, row := range rows {
+			item := map[string]string{}
+			for i, header := range headers {
+				if i < len(row) {
+					item[header] = row[i]
+				}
+			}
+			items = append(items, item)
+		}
+
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(items)
+
+	case "csv", "tsv":
+		w := csv.NewWriter(out)
+		if strings.ToLower(format) == "tsv" {
+			w.Comma = '\t'
+		}
+		if err := w.Write(headers); err != nil {
+			return err
+		}
+		if err := w.WriteAll(rows); err != nil {
+			return err
+		}
+		w.Flush()
+		return w.Error()
+	}
+
+	return fmt.Errorf("Unknown output format '%s' -- expected one of: %s", format, strings.Join(outputFormats, ", "))
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// policyCmd represents the branch policy base command
+var policyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Branch policy helpers",
+	Long:  `Operations to help with branch policies.  You can list, export and copy them`,
+	Run: func(cmd *cobra.Command, args []string) {
+		//	This command on it's own should just show help
+		cmd.Help()
+	},
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+
+		//	Verify that we have a tfsurl and a pat
+		if strings.TrimSpace(viper.GetString("tfsurl")) == "" {
+			fmt.Printf("\nThis tool requires a TFS base url to operate.   \n\nPlease specify one on the command line or in the config file 'tfsutil.yml' \nFor help creating a config file, see the command 'tfsutil config create'\n")
+			exit(1)
+		}
+
+		if strings.TrimSpace(viper.GetString("pat")) == "" {
+			fmt.Printf("\nThis tool requires a TFS Personal Access Token (pat) for authentication.  \n\nPlease specify a pat on the command line or in the config file 'tfsutil.yml' \nFor help creating a config file, see the command 'tfsutil config create'\n")
+			exit(1)
+		}
+
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(policyCmd)
+}
+
+// selectPolicies gets the policies that apply to the given repository and branch.  Deleted policies are skipped
+func selectPolicies(policies []tfs.PolicyConfiguration, repositoryID, branch string) []tfs.PolicyConfiguration {
+	retval := []tfs.PolicyConfiguration{}
+
+	for _, policy := range policies {
+		if policy.IsDeleted {
+			continue
+		}
+
+		for _, scope := range policy.Scopes() {
+			if scope.AppliesTo(repositoryID, branch) {
+				retval = append(retval, policy)
+				break
+			}
+		}
+	}
+
+	return retval
+}
+
+// describePolicyScopes describes the branches a policy applies to
+func describePolicyScopes(policy tfs.PolicyConfiguration) string {
+	scopes := []string{}
+	for _, scope := range policy.Scopes() {
+		branch := strings.TrimPrefix(scope.RefName, "refs/heads/")
+		if branch == "" {
+			branch = "(all branches)"
+		}
+		if strings.EqualFold(scope.MatchKind, "prefix") {
+			branch += "*"
+		}
+		if scope.RepositoryID == "" {
+			branch += " (all repositories)"
+		}
+		scopes = append(scopes, branch)
+	}
+	return strings.Join(scopes, ", ")
+}
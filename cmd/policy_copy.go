@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+var (
+	policyCopyFromRepo  string
+	policyCopyToRepo    string
+	policyCopyToProject string
+)
+
+// policyCopyCmd represents the policy copy command
+var policyCopyCmd = &cobra.Command{
+	Use:   "copy",
+	Short: "Copy branch policies to another repository",
+	Long: `Copies the branch policies (like minimum reviewers, build validation and
+work item linking) from one repository to another, optionally in another project.
+Use --branch to only copy the policies for a single branch.
+
+When copying to another project, build validation policies are pointed at the
+build definition with the same name in that project.
+
+Example:
+tfsutil policy copy --from-repo Website --to-repo Website-v2 --branch master
+
+`,
+	Run: policycopy,
+}
+
+func policycopy(cmd *cobra.Command, args []string) {
+
+	if policyCopyFromRepo == "" || policyCopyToRepo == "" {
+		fatalln("[ERROR] Requires a repository to copy from and a repository to copy to (use --from-repo and --to-repo)")
+	}
+
+	collection := viper.GetString("collection")
+	fromProject := viper.GetString("project")
+	toProject := policyCopyToProject
+	if toProject == "" {
+		toProject = fromProject
+	}
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	fromRepo, err := client.GetGitRepository(collection, fromProject, policyCopyFromRepo)
+	if err != nil {
+		fatalln("[ERROR] Finding repository to copy from \n", err)
+	}
+
+	toRepo, err := client.GetGitRepository(collection, toProject, policyCopyToRepo)
+	if err != nil {
+		fatalln("[ERROR] Finding repository to copy to \n", err)
+	}
+
+	retval, err := client.GetListOfPolicyConfigurations(collection, fromProject)
+	if err != nil {
+		fatalln("[ERROR] Policy list \n", err)
+	}
+
+	branch := formatBranchName(policyBranch)
+	copied := 0
+	for _, policy := range selectPolicies(retval.PolicyConfigurations, fromRepo.ID, branch) {
+
+		//	Only copy the scopes for the source repository.  Project-wide policies already apply everywhere in the project
+		scopes := []tfs.PolicyScope{}
+		for _, scope := range policy.Scopes() {
+			if strings.EqualFold(scope.RepositoryID, fromRepo.ID) && scope.AppliesTo(fromRepo.ID, branch) {
+				scope.RepositoryID = toRepo.ID
+				scopes = append(scopes, scope)
+			}
+		}
+
+		if len(scopes) == 0 {
+			log.Printf("[DEBUG] Skipping the project-wide policy %v (%s)", policy.ID, policy.Type.DisplayName)
+			continue
+		}
+
+		newPolicy := tfs.PolicyConfiguration{
+			IsEnabled:  policy.IsEnabled,
+			IsBlocking: policy.IsBlocking,
+			Settings:   map[string]interface{}{},
+		}
+		newPolicy.Type.ID = policy.Type.ID
+		for key, value := range policy.Settings {
+			newPolicy.Settings[key] = value
+		}
+		newPolicy.SetScopes(scopes)
+
+		//	Build definitions are different in every project.  Find the one with the same name
+		if id := policy.BuildDefinitionID(); id != 0 && !strings.EqualFold(fromProject, toProject) {
+			newID, err := remapBuildDefinition(client, collection, fromProject, toProject, id)
+			if err != nil {
+				log.Printf("[WARN] Skipping the %s policy %v: %s", policy.Type.DisplayName, policy.ID, err)
+				continue
+			}
+			newPolicy.SetBuildDefinitionID(newID)
+		}
+
+		created, err := client.CreatePolicyConfiguration(collection, toProject, newPolicy)
+		if err != nil {
+			log.Printf("[WARN] Unable to copy the %s policy %v: %s", policy.Type.DisplayName, policy.ID, err)
+			continue
+		}
+
+		copied++
+		fmt.Printf("Copied %s policy %v to %v (%s)\n", policy.Type.DisplayName, policy.ID, created.ID, describePolicyScopes(created))
+	}
+
+	fmt.Printf("\nCopied %v policies from %s to %s\n", copied, fromRepo.Name, toRepo.Name)
+}
+
+// remapBuildDefinition finds the build definition in the target project with the same name as the one in the source project
+func remapBuildDefinition(client tfs.Client, collection, fromProject, toProject string, definitionID int) (int, error) {
+
+	def, err := client.GetBuildDefinition(collection, fromProject, definitionID)
+	if err != nil {
+		return 0, fmt.Errorf("Unable to get build definition %v: %s", definitionID, err)
+	}
+
+	defs, err := client.GetListOfBuildDefinitions(collection, toProject, def.Name)
+	if err != nil {
+		return 0, fmt.Errorf("Unable to find build definition '%s' in %s: %s", def.Name, toProject, err)
+	}
+
+	for _, match := range defs.Definitions {
+		if strings.EqualFold(match.Name, def.Name) {
+			return match.ID, nil
+		}
+	}
+
+	return 0, fmt.Errorf("There is no build definition named '%s' in %s", def.Name, toProject)
+}
+
+func init() {
+	policyCmd.AddCommand(policyCopyCmd)
+
+	policyCopyCmd.Flags().StringVar(&policyCopyFromRepo, "from-repo", "", "Repository to copy the policies from")
+	policyCopyCmd.Flags().StringVar(&policyCopyToRepo, "to-repo", "", "Repository to copy the policies to")
+	policyCopyCmd.Flags().StringVar(&policyCopyToProject, "to-project", "", "Project the repository to copy to is in (default is the current project)")
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+var policyExportFile string
+
+// policyExportCmd represents the policy export command
+var policyExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export branch policies",
+	Long: `Exports the branch policies in the project (optionally for a single repository and branch) as JSON.
+
+Example:
+tfsutil policy export --repo Website --branch master --file website-policies.json
+
+`,
+	Run: policyexport,
+}
+
+func policyexport(cmd *cobra.Command, args []string) {
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	policies, _ := getSelectedPolicies(client)
+
+	formatted, err := json.MarshalIndent(policies, "", "  ")
+	if err != nil {
+		fatalln("[ERROR] Formatting policies \n", err)
+	}
+
+	//	If we don't have a file, just write it out
+	if policyExportFile == "" {
+		fmt.Println(string(formatted))
+		return
+	}
+
+	if err := ioutil.WriteFile(policyExportFile, formatted, 0644); err != nil {
+		fatalf("[ERROR] Writing %s - \n %s", policyExportFile, err)
+	}
+
+	fmt.Printf("\nExported %v policies to %s\n", len(policies), policyExportFile)
+}
+
+func init() {
+	policyCmd.AddCommand(policyExportCmd)
+
+	policyExportCmd.Flags().StringVarP(&policyExportFile, "file", "f", "", "File to export to (default is to write to the console)")
+}
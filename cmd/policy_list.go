@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+var (
+	policyRepo   string
+	policyBranch string
+)
+
+// policyListCmd represents the policy list command
+var policyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List branch policies",
+	Long: `Lists the branch policies in the project, optionally for a single repository and branch.
+
+Example:
+tfsutil policy list --repo Website --branch master
+
+`,
+	Run: policylist,
+}
+
+func policylist(cmd *cobra.Command, args []string) {
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	policies, repoID := getSelectedPolicies(client)
+
+	//	Begin the report:
+	fmt.Printf("\nCollection: %v", viper.GetString("collection"))
+	fmt.Printf("\nProject: %v\n", viper.GetString("project"))
+	if repoID != "" {
+		fmt.Printf("Repository: %v\n", policyRepo)
+	}
+	fmt.Printf("\nPolicies found: %v\n==================\n", len(policies))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tType\tBranches\tEnabled\tBlocking")
+	for _, policy := range policies {
+		fmt.Fprintf(w, "%v\t%s\t%s\t%v\t%v\n", policy.ID, policy.Type.DisplayName, describePolicyScopes(policy), policy.IsEnabled, policy.IsBlocking)
+	}
+	w.Flush()
+}
+
+// getSelectedPolicies gets the policies for the --repo and --branch flags (and the id of the repository, if there is one)
+func getSelectedPolicies(client tfs.Client) ([]tfs.PolicyConfiguration, string) {
+
+	repoID := ""
+	if policyRepo != "" {
+		repo, err := client.GetGitRepository(viper.GetString("collection"), viper.GetString("project"), policyRepo)
+		if err != nil {
+			fatalln("[ERROR] Finding repository \n", err)
+		}
+		repoID = repo.ID
+	}
+
+	//	Get the list of policies.  Report any errors
+	retval, err := client.GetListOfPolicyConfigurations(viper.GetString("collection"), viper.GetString("project"))
+	if err != nil {
+		fatalln("[ERROR] Policy list \n", err)
+	}
+
+	return selectPolicies(retval.PolicyConfigurations, repoID, formatBranchName(policyBranch)), repoID
+}
+
+func init() {
+	policyCmd.AddCommand(policyListCmd)
+
+	policyCmd.PersistentFlags().StringVar(&policyRepo, "repo", "", "Repository name (default is every repository)")
+	policyCmd.PersistentFlags().StringVar(&policyBranch, "branch", "", "Branch name (default is every branch)")
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// prCmd represents the pull request base command
+var prCmd = &cobra.Command{
+	Use:   "pr",
+	Short: "Pull request helpers",
+	Long:  `Operations to help with pull requests.  You can list, show and create them, and vote on them`,
+	Run: func(cmd *cobra.Command, args []string) {
+		//	This command on it's own should just show help
+		cmd.Help()
+	},
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+
+		//	Verify that we have a tfsurl and a pat
+		if strings.TrimSpace(viper.GetString("tfsurl")) == "" {
+			fmt.Printf("\nThis tool requires a TFS base url to operate.   \n\nPlease specify one on the command line or in the config file 'tfsutil.yml' \nFor help creating a config file, see the command 'tfsutil config create'\n")
+			exit(1)
+		}
+
+		if strings.TrimSpace(viper.GetString("pat")) == "" {
+			fmt.Printf("\nThis tool requires a TFS Personal Access Token (pat) for authentication.  \n\nPlease specify a pat on the command line or in the config file 'tfsutil.yml' \nFor help creating a config file, see the command 'tfsutil config create'\n")
+			exit(1)
+		}
+
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(prCmd)
+}
+
+// resolveIdentityID finds the identity id for a display name, account name or email address.
+// 'me' is the authenticated user
+func resolveIdentityID(client tfs.Client, collection, name string) (string, error) {
+
+	if strings.EqualFold(name, "me") {
+		connection, err := client.GetConnectionData(collection)
+		if err != nil {
+			return "", fmt.Errorf("Finding the authenticated user: %s", err)
+		}
+		return connection.AuthenticatedUser.ID, nil
+	}
+
+	retval, err := client.GetListOfIdentities(collection, name)
+	if err != nil {
+		return "", fmt.Errorf("Finding the user '%s': %s", name, err)
+	}
+
+	if retval.Count < 1 {
+		return "", fmt.Errorf("Sorry -- I couldn't find the user '%s'", name)
+	}
+
+	if retval.Count > 1 {
+		return "", fmt.Errorf("Sorry -- Too many users match '%s' -- please be more specific", name)
+	}
+
+	return retval.Identities[0].ID, nil
+}
+
+// requirePullRequestIDArg makes sure a (numeric) pull request id was passed
+func requirePullRequestIDArg(cmd *cobra.Command, args []string) error {
+	if len(args) < 1 {
+		return errors.New("Requires a pull request id")
+	}
+	if _, err := strconv.Atoi(args[0]); err != nil {
+		return fmt.Errorf("Expected a pull request id, but got '%s'", args[0])
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+var (
+	prCreateRepo        string
+	prCreateSource      string
+	prCreateTarget      string
+	prCreateTitle       string
+	prCreateDescription string
+)
+
+// prCreateCmd represents the pr create command
+var prCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a pull request",
+	Long: `Creates a pull request.  If --target isn't given, the repository's default branch is used.
+
+Example:
+tfsutil pr create --repo Website --source feature/login --target master --title "Add login page"
+
+`,
+	Run: prcreate,
+}
+
+func prcreate(cmd *cobra.Command, args []string) {
+
+	if prCreateRepo == "" || prCreateSource == "" || prCreateTitle == "" {
+		fatalln("[ERROR] Requires a repository, a source branch and a title (use --repo, --source and --title)")
+	}
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	repo, err := client.GetGitRepository(viper.GetString("collection"), viper.GetString("project"), prCreateRepo)
+	if err != nil {
+		fatalln("[ERROR] Finding repository \n", err)
+	}
+
+	target := formatBranchName(prCreateTarget)
+	if target == "" {
+		target = repo.DefaultBranch
+	}
+
+	newPullRequest := tfs.PullRequest{
+		Title:         prCreateTitle,
+		Description:   prCreateDescription,
+		SourceRefName: formatBranchName(prCreateSource),
+		TargetRefName: target,
+	}
+
+	created, err := client.CreatePullRequest(viper.GetString("collection"), viper.GetString("project"), repo.ID, newPullRequest)
+	if err != nil {
+		fatalln("[ERROR] Creating pull request \n", err)
+	}
+
+	fmt.Printf("\nCreated pull request %v: %s\n", created.PullRequestID, created.Title)
+}
+
+func init() {
+	prCmd.AddCommand(prCreateCmd)
+
+	prCreateCmd.Flags().StringVar(&prCreateRepo, "repo", "", "Repository name")
+	prCreateCmd.Flags().StringVar(&prCreateSource, "source", "", "Source branch")
+	prCreateCmd.Flags().StringVar(&prCreateTarget, "target", "", "Target branch (default is the repository's default branch)")
+	prCreateCmd.Flags().StringVar(&prCreateTitle, "title", "", "Pull request title")
+	prCreateCmd.Flags().StringVar(&prCreateDescription, "description", "", "Pull request description")
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+var (
+	prRepo     string
+	prStatus   string
+	prCreator  string
+	prReviewer string
+	prTop      int
+)
+
+// prListCmd represents the pr list command
+var prListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List pull requests",
+	Long: `Lists pull requests, filtered by repository, status, creator and reviewer.
+Use 'me' as the creator or reviewer to mean yourself.
+
+Example:
+tfsutil pr list --repo Website --reviewer me
+
+`,
+	Run: prlist,
+}
+
+func prlist(cmd *cobra.Command, args []string) {
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	//	Assemble the query from our flags
+	query := tfs.PullRequestQuery{
+		Status: prStatus,
+		Top:    prTop,
+	}
+
+	if prRepo != "" {
+		repo, err := client.GetGitRepository(viper.GetString("collection"), viper.GetString("project"), prRepo)
+		if err != nil {
+			fatalln("[ERROR] Finding repository \n", err)
+		}
+		query.RepositoryID = repo.ID
+	}
+
+	var err error
+	if prCreator != "" {
+		query.CreatorID, err = resolveIdentityID(client, viper.GetString("collection"), prCreator)
+		if err != nil {
+			fatalln("[ERROR] ", err)
+		}
+	}
+
+	if prReviewer != "" {
+		query.ReviewerID, err = resolveIdentityID(client, viper.GetString("collection"), prReviewer)
+		if err != nil {
+			fatalln("[ERROR] ", err)
+		}
+	}
+
+	//	Get the list of pull requests.  Report any errors
+	retval, err := client.GetListOfPullRequests(viper.GetString("collection"), viper.GetString("project"), query)
+	if err != nil {
+		fatalln("[ERROR] Pull request list \n", err)
+	}
+
+	//	Begin the report:
+	fmt.Printf("\nCollection: %v", viper.GetString("collection"))
+	fmt.Printf("\nProject: %v\n", viper.GetString("project"))
+	fmt.Printf("\nPull requests found: %v\n=======================\n", retval.Count)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tRepository\tTitle\tSource\tTarget\tCreated by\tStatus\tVotes")
+	for _, pr := range retval.PullRequests {
+		repoName, createdBy := "", ""
+		if pr.Repository != nil {
+			repoName = pr.Repository.Name
+		}
+		if pr.CreatedBy != nil {
+			createdBy = pr.CreatedBy.DisplayName
+		}
+
+		fmt.Fprintf(w, "%v\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			pr.PullRequestID,
+			repoName,
+			pr.Title,
+			strings.TrimPrefix(pr.SourceRefName, "refs/heads/"),
+			strings.TrimPrefix(pr.TargetRefName, "refs/heads/"),
+			createdBy,
+			pr.Status,
+			summarizeVotes(pr.Reviewers))
+	}
+	w.Flush()
+}
+
+// summarizeVotes summarizes reviewer votes (like '2 approved, 1 waiting for author')
+func summarizeVotes(reviewers []tfs.IdentityRefWithVote) string {
+	counts := map[string]int{}
+	order := []string{}
+	for _, reviewer := range reviewers {
+		if reviewer.Vote == tfs.VoteNone {
+			continue
+		}
+
+		vote := tfs.VoteDescription(reviewer.Vote)
+		if counts[vote] == 0 {
+			order = append(order, vote)
+		}
+		counts[vote]++
+	}
+
+	summary := []string{}
+	for _, vote := range order {
+		summary = append(summary, fmt.Sprintf("%v %s", counts[vote], vote))
+	}
+	return strings.Join(summary, ", ")
+}
+
+func init() {
+	prCmd.AddCommand(prListCmd)
+
+	prListCmd.Flags().StringVar(&prRepo, "repo", "", "Repository name")
+	prListCmd.Flags().StringVar(&prStatus, "status", "active", "Pull request status: active/abandoned/completed/all")
+	prListCmd.Flags().StringVar(&prCreator, "creator", "", "Only pull requests created by this user ('me' for yourself)")
+	prListCmd.Flags().StringVar(&prReviewer, "reviewer", "", "Only pull requests with this reviewer ('me' for yourself)")
+	prListCmd.Flags().IntVar(&prTop, "top", 100, "Maximum number of pull requests to get")
+}
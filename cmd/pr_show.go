@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// prShowCmd represents the pr show command
+var prShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Show a pull request",
+	Long:  `Shows a pull request, along with its reviewers and their votes`,
+	Args:  requirePullRequestIDArg,
+	Run:   prshow,
+}
+
+func prshow(cmd *cobra.Command, args []string) {
+
+	id, _ := strconv.Atoi(args[0])
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	pr, err := client.GetPullRequest(viper.GetString("collection"), viper.GetString("project"), id)
+	if err != nil {
+		fatalln("[ERROR] Getting pull request \n", err)
+	}
+
+	fmt.Printf("\nPull request %v: %s\n", pr.PullRequestID, pr.Title)
+	if pr.Repository != nil {
+		fmt.Printf("Repository: %s\n", pr.Repository.Name)
+	}
+	fmt.Printf("Merging: %s into %s\n", strings.TrimPrefix(pr.SourceRefName, "refs/heads/"), strings.TrimPrefix(pr.TargetRefName, "refs/heads/"))
+	if pr.CreatedBy != nil {
+		fmt.Printf("Created: %s by %s\n", pr.CreationDate.Local().Format("2006-01-02 15:04"), pr.CreatedBy.DisplayName)
+	}
+	fmt.Printf("Status: %s (merge status: %s)\n", pr.Status, pr.MergeStatus)
+	if pr.IsDraft {
+		fmt.Printf("Draft: true\n")
+	}
+
+	if pr.Description != "" {
+		fmt.Printf("\n%s\n", pr.Description)
+	}
+
+	fmt.Printf("\nReviewers: %v\n==============\n", len(pr.Reviewers))
+	for _, reviewer := range pr.Reviewers {
+		required := ""
+		if reviewer.IsRequired {
+			required = " (required)"
+		}
+		fmt.Printf("%s%s: %s\n", reviewer.DisplayName, required, tfs.VoteDescription(reviewer.Vote))
+	}
+}
+
+func init() {
+	prCmd.AddCommand(prShowCmd)
+}
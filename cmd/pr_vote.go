@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// prVotes maps the vote names used on the command line to TFS votes
+var prVotes = map[string]int{
+	"approve":             tfs.VoteApproved,
+	"approve-suggestions": tfs.VoteApprovedWithSuggestions,
+	"reset":               tfs.VoteNone,
+	"wait":                tfs.VoteWaitingForAuthor,
+	"reject":              tfs.VoteRejected,
+}
+
+// prVoteCmd represents the pr vote command
+var prVoteCmd = &cobra.Command{
+	Use:   "vote <id> approve|approve-suggestions|wait|reject|reset",
+	Short: "Vote on a pull request",
+	Long: `Votes on a pull request as yourself.  If you aren't a reviewer yet, you are added.
+
+Example:
+tfsutil pr vote 1234 approve
+
+`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := requirePullRequestIDArg(cmd, args); err != nil {
+			return err
+		}
+		if len(args) < 2 {
+			return errors.New("Requires a vote: approve, approve-suggestions, wait, reject or reset")
+		}
+		if _, ok := prVotes[args[1]]; !ok {
+			return fmt.Errorf("Unknown vote '%s' -- expected approve, approve-suggestions, wait, reject or reset", args[1])
+		}
+		return nil
+	},
+	Run: prvote,
+}
+
+func prvote(cmd *cobra.Command, args []string) {
+
+	id, _ := strconv.Atoi(args[0])
+	vote := prVotes[args[1]]
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	pr, err := client.GetPullRequest(viper.GetString("collection"), viper.GetString("project"), id)
+	if err != nil {
+		fatalln("[ERROR] Getting pull request \n", err)
+	}
+
+	if pr.Repository == nil {
+		fatalf("[ERROR] Pull request %v doesn't have a repository", pr.PullRequestID)
+	}
+
+	reviewerID, err := resolveIdentityID(client, viper.GetString("collection"), "me")
+	if err != nil {
+		fatalln("[ERROR] ", err)
+	}
+
+	_, err = client.SetPullRequestVote(viper.GetString("collection"), viper.GetString("project"), pr.Repository.ID, pr.PullRequestID, reviewerID, vote)
+	if err != nil {
+		fatalln("[ERROR] Voting on pull request \n", err)
+	}
+
+	fmt.Printf("\nVoted '%s' on pull request %v: %s\n", tfs.VoteDescription(vote), pr.PullRequestID, pr.Title)
+}
+
+func init() {
+	prCmd.AddCommand(prVoteCmd)
+}
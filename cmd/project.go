@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -23,12 +22,12 @@ var projectCmd = &cobra.Command{
 		//	Verify that we have a tfsurl and a pat
 		if strings.TrimSpace(viper.GetString("tfsurl")) == "" {
 			fmt.Printf("\nThis tool requires a TFS base url to operate.   \n\nPlease specify one on the command line or in the config file 'tfsutil.yml' \nFor help creating a config file, see the command 'tfsutil config create'\n")
-			os.Exit(1)
+			exit(1)
 		}
 
 		if strings.TrimSpace(viper.GetString("pat")) == "" {
 			fmt.Printf("\nThis tool requires a TFS Personal Access Token (pat) for authentication.  \n\nPlease specify a pat on the command line or in the config file 'tfsutil.yml' \nFor help creating a config file, see the command 'tfsutil config create'\n")
-			os.Exit(1)
+			exit(1)
 		}
 
 	},
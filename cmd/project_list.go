@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"log"
 	"sort"
 
 	"github.com/danesparza/tfsutil/tfs"
@@ -28,7 +27,7 @@ func projectlist(cmd *cobra.Command, args []string) {
 	//	Get the list of Projects.  Report any errors
 	retval, err := client.GetListOfProjects(viper.GetString("collection"))
 	if err != nil {
-		log.Fatalln("[ERROR] Project list \n", err)
+		fatalln("[ERROR] Project list \n", err)
 	}
 
 	// Closure(s) that orders the VariableGroup structure.
@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+var releaseExportFile string
+
+// releaseDefCmd represents the release def command
+var releaseDefCmd = &cobra.Command{
+	Use:   "def",
+	Short: "Release definition helpers",
+	Long:  `Operations to help with classic release definitions.  You can list, show and export them`,
+	Run: func(cmd *cobra.Command, args []string) {
+		//	This command on it's own should just show help
+		cmd.Help()
+	},
+}
+
+// releaseDefListCmd represents the release def list command
+var releaseDefListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List release definitions",
+	Long:  `Lists release definitions, along with their environments and the count of their variables`,
+	Run:   releasedeflist,
+}
+
+// releaseDefShowCmd represents the release def show command
+var releaseDefShowCmd = &cobra.Command{
+	Use:   "show \"<name or id>\"",
+	Short: "Show a release definition",
+	Long: `Shows a release definition, its environments, linked variable groups and variables.
+
+Example:
+tfsutil release def show "Website release"
+
+`,
+	Args: requireReleaseDefinitionArg,
+	Run:  releasedefshow,
+}
+
+// releaseDefExportCmd represents the release def export command
+var releaseDefExportCmd = &cobra.Command{
+	Use:   "export \"<name or id>\"",
+	Short: "Export a release definition",
+	Long: `Exports the complete JSON for a release definition, exactly as TFS returns it.
+
+Example:
+tfsutil release def export "Website release" --file website-release.json
+
+`,
+	Args: requireReleaseDefinitionArg,
+	Run:  releasedefexport,
+}
+
+func releasedeflist(cmd *cobra.Command, args []string) {
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL:     viper.GetString("tfsurl"),
+		ReleaseURL: viper.GetString("releaseurl"),
+	}
+
+	//	Get the list of release definitions.  Report any errors
+	retval, err := client.GetListOfReleaseDefinitions(viper.GetString("collection"), viper.GetString("project"), "")
+	if err != nil {
+		fatalln("[ERROR] Release definition list \n", err)
+	}
+
+	//	Sort the definitions
+	sort.Slice(retval.Definitions, func(i, j int) bool {
+		return retval.Definitions[i].Name < retval.Definitions[j].Name
+	})
+
+	//	Begin the report:
+	fmt.Printf("\nCollection: %v", viper.GetString("collection"))
+	fmt.Printf("\nProject: %v\n", viper.GetString("project"))
+	fmt.Printf("\nRelease definitions found: %v\n=============================\n", retval.Count)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tName\tEnvironments\tVariables")
+	for _, def := range retval.Definitions {
+		envs := []string{}
+		for _, env := range def.Environments {
+			envs = append(envs, env.Name)
+		}
+		fmt.Fprintf(w, "%v\t%s\t%s\t%v\n", def.ID, def.Name, strings.Join(envs, ", "), len(def.Variables))
+	}
+	w.Flush()
+}
+
+func releasedefshow(cmd *cobra.Command, args []string) {
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL:     viper.GetString("tfsurl"),
+		ReleaseURL: viper.GetString("releaseurl"),
+	}
+
+	def, err := findReleaseDefinition(client, args[0])
+	if err != nil {
+		fatalln("[ERROR] Finding release definition \n", err)
+	}
+
+	fmt.Printf("\nRelease definition: %s (id %v, revision %v)\n", def.Name, def.ID, def.Revision)
+	if def.Path != "" {
+		fmt.Printf("Path: %s\n", def.Path)
+	}
+	if def.Description != "" {
+		fmt.Printf("Description: %s\n", def.Description)
+	}
+	fmt.Printf("Modified: %s by %s\n", def.ModifiedOn.Local().Format("2006-01-02 15:04"), def.ModifiedBy.DisplayName)
+
+	fmt.Printf("\nRelease variables (variable groups: %v)\n==================\n", formatIDs(def.VariableGroups))
+	printVariables(def.Variables)
+
+	for _, env := range def.Environments {
+		fmt.Printf("\nEnvironment: %s (variable groups: %v)\n==================\n", env.Name, formatIDs(env.VariableGroups))
+		printVariables(env.Variables)
+	}
+}
+
+func releasedefexport(cmd *cobra.Command, args []string) {
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL:     viper.GetString("tfsurl"),
+		ReleaseURL: viper.GetString("releaseurl"),
+	}
+
+	def, err := findReleaseDefinition(client, args[0])
+	if err != nil {
+		fatalln("[ERROR] Finding release definition \n", err)
+	}
+
+	raw, err := client.ExportReleaseDefinition(viper.GetString("collection"), viper.GetString("project"), def.ID)
+	if err != nil {
+		fatalln("[ERROR] Exporting release definition \n", err)
+	}
+
+	//	Make the export readable
+	formatted := new(bytes.Buffer)
+	if err := json.Indent(formatted, raw, "", "  "); err != nil {
+		fatalln("[ERROR] Formatting release definition \n", err)
+	}
+
+	//	If we don't have a file, just write it out
+	if releaseExportFile == "" {
+		fmt.Println(formatted.String())
+		return
+	}
+
+	if err := ioutil.WriteFile(releaseExportFile, formatted.Bytes(), 0644); err != nil {
+		fatalf("[ERROR] Writing %s - \n %s", releaseExportFile, err)
+	}
+
+	fmt.Printf("\nExported '%s' to %s\n", def.Name, releaseExportFile)
+}
+
+// requireReleaseDefinitionArg makes sure a release definition name or id was passed
+func requireReleaseDefinitionArg(cmd *cobra.Command, args []string) error {
+	if len(args) < 1 {
+		return errors.New("Requires a release definition name or id")
+	}
+	return nil
+}
+
+// findReleaseDefinition finds a single release definition by id or by name
+func findReleaseDefinition(client tfs.Client, nameOrID string) (tfs.ReleaseDefinition, error) {
+
+	//	If it looks like an id, get the definition directly
+	if id, err := strconv.Atoi(nameOrID); err == nil {
+		return client.GetReleaseDefinition(viper.GetString("collection"), viper.GetString("project"), id)
+	}
+
+	//	Otherwise, search for it by name
+	defs, err := client.GetListOfReleaseDefinitions(viper.GetString("collection"), viper.GetString("project"), nameOrID)
+	if err != nil {
+		return tfs.ReleaseDefinition{}, err
+	}
+
+	matches := []tfs.ReleaseDefinition{}
+	for _, def := range defs.Definitions {
+		if strings.EqualFold(def.Name, nameOrID) {
+			return client.GetReleaseDefinition(viper.GetString("collection"), viper.GetString("project"), def.ID)
+		}
+		matches = append(matches, def)
+	}
+
+	if len(matches) < 1 {
+		return tfs.ReleaseDefinition{}, fmt.Errorf("Sorry -- I couldn't find the release definition '%s'", nameOrID)
+	}
+
+	if len(matches) > 1 {
+		return tfs.ReleaseDefinition{}, fmt.Errorf("Sorry -- Too many release definitions match '%s' -- please be more specific", nameOrID)
+	}
+
+	return client.GetReleaseDefinition(viper.GetString("collection"), viper.GetString("project"), matches[0].ID)
+}
+
+// printVariables prints a sorted list of variables.  Secret values are masked
+func printVariables(variables map[string]tfs.Variable) {
+	names := []string{}
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		variable := variables[name]
+		value := variable.Value
+		if variable.IsSecret {
+			value = "********"
+		}
+		fmt.Printf("%s = %s\n", name, value)
+	}
+}
+
+// formatIDs formats a list of ids for display
+func formatIDs(ids []int) string {
+	if len(ids) == 0 {
+		return "none"
+	}
+
+	formatted := []string{}
+	for _, id := range ids {
+		formatted = append(formatted, strconv.Itoa(id))
+	}
+	return strings.Join(formatted, ", ")
+}
+
+func init() {
+	releaseCmd.AddCommand(releaseDefCmd)
+	releaseDefCmd.AddCommand(releaseDefListCmd)
+	releaseDefCmd.AddCommand(releaseDefShowCmd)
+	releaseDefCmd.AddCommand(releaseDefExportCmd)
+
+	releaseDefExportCmd.Flags().StringVarP(&releaseExportFile, "file", "f", "", "File to export to (default is to write to the console)")
+}
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+var (
+	releaseVarsEnvironment   string
+	releaseVarsSecret        bool
+	releaseVarsAllowOverride bool
+)
+
+// releaseVarsCmd represents the release vars command
+var releaseVarsCmd = &cobra.Command{
+	Use:   "vars \"<definition name or id>\"",
+	Short: "List release variables",
+	Long: `Lists the release-level and environment-level variables for a release definition.
+Use 'release vars set' and 'release vars delete' to edit them.
+
+Example:
+tfsutil release vars "Website release" --environment Production
+
+`,
+	Args: requireReleaseDefinitionArg,
+	Run:  releasevarslist,
+}
+
+// releaseVarsSetCmd represents the release vars set command
+var releaseVarsSetCmd = &cobra.Command{
+	Use:   "set \"<definition name or id>\" <variable> <value>",
+	Short: "Set a release variable",
+	Long: `Adds or updates a release variable.  By default, the release-level variable is set.
+Use --environment to set an environment-level variable instead.
+
+Example:
+tfsutil release vars set "Website release" ApiUrl https://api.example.com --environment Production
+
+`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 3 {
+			return errors.New("Requires a release definition, a variable name and a value")
+		}
+		return nil
+	},
+	Run: releasevarsset,
+}
+
+// releaseVarsDeleteCmd represents the release vars delete command
+var releaseVarsDeleteCmd = &cobra.Command{
+	Use:   "delete \"<definition name or id>\" <variable>",
+	Short: "Delete a release variable",
+	Long: `Removes a release variable.  By default, the release-level variable is removed.
+Use --environment to remove an environment-level variable instead.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 2 {
+			return errors.New("Requires a release definition and a variable name")
+		}
+		return nil
+	},
+	Run: releasevarsdelete,
+}
+
+func releasevarslist(cmd *cobra.Command, args []string) {
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL:     viper.GetString("tfsurl"),
+		ReleaseURL: viper.GetString("releaseurl"),
+	}
+
+	def, err := findReleaseDefinition(client, args[0])
+	if err != nil {
+		fatalln("[ERROR] Finding release definition \n", err)
+	}
+
+	fmt.Printf("\nRelease definition: %s\n", def.Name)
+
+	if releaseVarsEnvironment == "" {
+		fmt.Printf("\nRelease variables: %v\n==================\n", len(def.Variables))
+		printVariables(def.Variables)
+	}
+
+	for _, env := range def.Environments {
+		if releaseVarsEnvironment != "" && !strings.EqualFold(env.Name, releaseVarsEnvironment) {
+			continue
+		}
+
+		fmt.Printf("\nEnvironment: %s variables: %v\n==================\n", env.Name, len(env.Variables))
+		printVariables(env.Variables)
+	}
+}
+
+func releasevarsset(cmd *cobra.Command, args []string) {
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL:     viper.GetString("tfsurl"),
+		ReleaseURL: viper.GetString("releaseurl"),
+	}
+
+	def, err := findReleaseDefinition(client, args[0])
+	if err != nil {
+		fatalln("[ERROR] Finding release definition \n", err)
+	}
+
+	if _, err := releaseVariablesFor(def, releaseVarsEnvironment); err != nil {
+		fatalln("[ERROR] ", err)
+	}
+
+	variable := tfs.Variable{
+		Value:         args[2],
+		IsSecret:      releaseVarsSecret,
+		AllowOverride: releaseVarsAllowOverride,
+	}
+
+	err = client.SetReleaseDefinitionVariable(viper.GetString("collection"), viper.GetString("project"), def.ID, releaseVarsEnvironment, args[1], variable)
+	if err != nil {
+		fatalf("[ERROR] Updating the release definition %s - \n %s", def.Name, err)
+	}
+
+	fmt.Printf("\nSet %s in %s\n", args[1], describeReleaseScope(def, releaseVarsEnvironment))
+}
+
+func releasevarsdelete(cmd *cobra.Command, args []string) {
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL:     viper.GetString("tfsurl"),
+		ReleaseURL: viper.GetString("releaseurl"),
+	}
+
+	def, err := findReleaseDefinition(client, args[0])
+	if err != nil {
+		fatalln("[ERROR] Finding release definition \n", err)
+	}
+
+	variables, err := releaseVariablesFor(def, releaseVarsEnvironment)
+	if err != nil {
+		fatalln("[ERROR] ", err)
+	}
+
+	if _, ok := variables[args[1]]; !ok {
+		fatalf("Sorry -- I couldn't find the variable '%s' in %s", args[1], describeReleaseScope(def, releaseVarsEnvironment))
+	}
+
+	err = client.DeleteReleaseDefinitionVariable(viper.GetString("collection"), viper.GetString("project"), def.ID, releaseVarsEnvironment, args[1])
+	if err != nil {
+		fatalf("[ERROR] Updating the release definition %s - \n %s", def.Name, err)
+	}
+
+	fmt.Printf("\nDeleted %s from %s\n", args[1], describeReleaseScope(def, releaseVarsEnvironment))
+}
+
+// releaseVariablesFor gets the release-level variables, or the variables for the given environment
+func releaseVariablesFor(def tfs.ReleaseDefinition, environment string) (map[string]tfs.Variable, error) {
+	var variables map[string]tfs.Variable
+
+	if environment == "" {
+		variables = def.Variables
+	} else {
+		found := false
+		for _, env := range def.Environments {
+			if strings.EqualFold(env.Name, environment) {
+				variables = env.Variables
+				found = true
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("Sorry -- '%s' doesn't have an environment named '%s'", def.Name, environment)
+		}
+	}
+
+	if variables == nil {
+		variables = map[string]tfs.Variable{}
+	}
+
+	return variables, nil
+}
+
+// describeReleaseScope describes where a release variable lives
+func describeReleaseScope(def tfs.ReleaseDefinition, environment string) string {
+	if environment == "" {
+		return fmt.Sprintf("'%s'", def.Name)
+	}
+	return fmt.Sprintf("'%s' environment '%s'", def.Name, environment)
+}
+
+func init() {
+	releaseCmd.AddCommand(releaseVarsCmd)
+	releaseVarsCmd.AddCommand(releaseVarsSetCmd)
+	releaseVarsCmd.AddCommand(releaseVarsDeleteCmd)
+
+	releaseVarsCmd.PersistentFlags().StringVarP(&releaseVarsEnvironment, "environment", "e", "", "Environment (stage) name.  The default is the release-level variables")
+	releaseVarsSetCmd.Flags().BoolVar(&releaseVarsSecret, "secret", false, "Store the value as a secret")
+	releaseVarsSetCmd.Flags().BoolVar(&releaseVarsAllowOverride, "allow-override", false, "Allow the value to be overridden at release time")
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// repoBranchesCmd represents the repo branches command
+var repoBranchesCmd = &cobra.Command{
+	Use:   "branches \"<repository>\"",
+	Short: "List the branches in a git repository",
+	Long: `Lists the branches in a git repository, along with how far ahead and
+behind each branch is compared to the default branch.
+
+Example:
+tfsutil repo branches Website
+
+`,
+	Args: requireRepoArg,
+	Run:  repobranches,
+}
+
+func repobranches(cmd *cobra.Command, args []string) {
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	repo, err := client.GetGitRepository(viper.GetString("collection"), viper.GetString("project"), args[0])
+	if err != nil {
+		fatalln("[ERROR] Finding repository \n", err)
+	}
+
+	//	Get the branches, and how they compare to the default branch
+	refs, err := client.GetListOfGitRefs(viper.GetString("collection"), viper.GetString("project"), repo.ID, "heads/")
+	if err != nil {
+		fatalln("[ERROR] Branch list \n", err)
+	}
+
+	stats, err := client.GetListOfGitBranchStats(viper.GetString("collection"), viper.GetString("project"), repo.ID, repo.DefaultBranch)
+	if err != nil {
+		fatalln("[ERROR] Branch statistics \n", err)
+	}
+
+	statsByName := map[string]tfs.GitBranchStats{}
+	for _, branch := range stats.Branches {
+		statsByName[branch.Name] = branch
+	}
+
+	//	Sort the branches
+	sort.Slice(refs.Refs, func(i, j int) bool {
+		return refs.Refs[i].Name < refs.Refs[j].Name
+	})
+
+	//	Begin the report:
+	fmt.Printf("\nRepository: %v", repo.Name)
+	fmt.Printf("\nDefault branch: %v\n", strings.TrimPrefix(repo.DefaultBranch, "refs/heads/"))
+	fmt.Printf("\nBranches found: %v\n==================\n", refs.Count)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Branch\tAhead\tBehind\tLast commit\tBy\tCreated by")
+	for _, ref := range refs.Refs {
+		name := strings.TrimPrefix(ref.Name, "refs/heads/")
+		branch := statsByName[name]
+
+		lastCommit := ""
+		if !branch.Commit.Committer.Date.IsZero() {
+			lastCommit = branch.Commit.Committer.Date.Local().Format("2006-01-02")
+		}
+
+		fmt.Fprintf(w, "%s\t%v\t%v\t%s\t%s\t%s\n", name, branch.AheadCount, branch.BehindCount, lastCommit, branch.Commit.Author.Name, ref.Creator.DisplayName)
+	}
+	w.Flush()
+}
+
+func init() {
+	repoCmd.AddCommand(repoBranchesCmd)
+}
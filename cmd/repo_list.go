@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// repoListCmd represents the repo list command
+var repoListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List git repositories",
+	Long:  `Lists git repositories, along with their size, default branch and clone urls`,
+	Run:   repolist,
+}
+
+func repolist(cmd *cobra.Command, args []string) {
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	//	Get the list of repositories.  Report any errors
+	retval, err := client.GetListOfGitRepositories(viper.GetString("collection"), viper.GetString("project"))
+	if err != nil {
+		fatalln("[ERROR] Repository list \n", err)
+	}
+
+	//	Sort the repositories
+	sort.Slice(retval.Repositories, func(i, j int) bool {
+		return retval.Repositories[i].Name < retval.Repositories[j].Name
+	})
+
+	//	Begin the report:
+	fmt.Printf("\nCollection: %v", viper.GetString("collection"))
+	fmt.Printf("\nProject: %v\n", viper.GetString("project"))
+	fmt.Printf("\nRepositories found: %v\n======================\n", retval.Count)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Name\tSize\tDefault branch\tClone url\tSSH url")
+	for _, repo := range retval.Repositories {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			repo.Name,
+			formatBytes(repo.Size),
+			strings.TrimPrefix(repo.DefaultBranch, "refs/heads/"),
+			repo.RemoteURL,
+			repo.SSHURL)
+	}
+	w.Flush()
+}
+
+// formatBytes formats a size in bytes for display (like 12.3 MB)
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+func init() {
+	repoCmd.AddCommand(repoListCmd)
+}
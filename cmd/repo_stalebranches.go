@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+var repoOlderThan string
+
+// repoStaleBranchesCmd represents the repo stale-branches command
+var repoStaleBranchesCmd = &cobra.Command{
+	Use:   "stale-branches [\"<repository>\"]",
+	Short: "Find stale branches",
+	Long: `Lists the branches that haven't had a commit in a while.  If a repository isn't
+given, every repository in the project is checked.  The default branch is never stale.
+
+Example:
+tfsutil repo stale-branches --older-than 90d
+
+`,
+	Run: repostalebranches,
+}
+
+func repostalebranches(cmd *cobra.Command, args []string) {
+
+	age, err := parseAge(repoOlderThan)
+	if err != nil {
+		fatalf("[ERROR] Invalid --older-than value '%s' - \n %s", repoOlderThan, err)
+	}
+	cutoff := time.Now().Add(-age)
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	//	Figure out which repositories to check
+	repos := []tfs.GitRepository{}
+	if len(args) > 0 {
+		repo, err := client.GetGitRepository(viper.GetString("collection"), viper.GetString("project"), args[0])
+		if err != nil {
+			fatalln("[ERROR] Finding repository \n", err)
+		}
+		repos = append(repos, repo)
+	} else {
+		retval, err := client.GetListOfGitRepositories(viper.GetString("collection"), viper.GetString("project"))
+		if err != nil {
+			fatalln("[ERROR] Repository list \n", err)
+		}
+		repos = retval.Repositories
+	}
+
+	sort.Slice(repos, func(i, j int) bool {
+		return repos[i].Name < repos[j].Name
+	})
+
+	//	Begin the report:
+	fmt.Printf("\nCollection: %v", viper.GetString("collection"))
+	fmt.Printf("\nProject: %v\n", viper.GetString("project"))
+	fmt.Printf("\nBranches without a commit since %s\n=============================================\n", cutoff.Format("2006-01-02"))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Repository\tBranch\tLast commit\tBy\tAhead\tBehind")
+	found := 0
+	for _, repo := range repos {
+
+		//	Empty repositories don't have a default branch (or any other branches)
+		if repo.DefaultBranch == "" {
+			continue
+		}
+
+		stats, err := client.GetListOfGitBranchStats(viper.GetString("collection"), viper.GetString("project"), repo.ID, repo.DefaultBranch)
+		if err != nil {
+			log.Printf("[WARN] Unable to get the branches for '%s': %s", repo.Name, err)
+			continue
+		}
+
+		sort.Slice(stats.Branches, func(i, j int) bool {
+			return stats.Branches[i].Commit.Committer.Date.Before(stats.Branches[j].Commit.Committer.Date)
+		})
+
+		for _, branch := range stats.Branches {
+			if branch.IsBaseVersion || branch.Name == strings.TrimPrefix(repo.DefaultBranch, "refs/heads/") {
+				continue
+			}
+
+			if branch.Commit.Committer.Date.After(cutoff) {
+				continue
+			}
+
+			found++
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%v\t%v\n",
+				repo.Name,
+				branch.Name,
+				branch.Commit.Committer.Date.Local().Format("2006-01-02"),
+				branch.Commit.Author.Name,
+				branch.AheadCount,
+				branch.BehindCount)
+		}
+	}
+	w.Flush()
+
+	fmt.Printf("\nStale branches found: %v\n", found)
+}
+
+func init() {
+	repoCmd.AddCommand(repoStaleBranchesCmd)
+
+	repoStaleBranchesCmd.Flags().StringVar(&repoOlderThan, "older-than", "90d", "Age of the last commit (like 90d, 12w or 720h)")
+}
@@ -4,11 +4,15 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/hashicorp/logutils"
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
 )
 
 var (
@@ -19,6 +23,9 @@ var (
 	collection            string
 	project               string
 	loglevel              string
+	recordDir             string
+	replayDir             string
+	noCache               bool
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -42,6 +49,28 @@ func Execute() {
 	}
 }
 
+// exit ends tfsutil with the given status code.  The shell replaces it while it runs a command, so a
+// command that fails only ends itself
+var exit = os.Exit
+
+// fatal logs the message and exits, like log.Fatal
+func fatal(v ...interface{}) {
+	log.Print(v...)
+	exit(1)
+}
+
+// fatalf logs the formatted message and exits, like log.Fatalf
+func fatalf(format string, v ...interface{}) {
+	log.Printf(format, v...)
+	exit(1)
+}
+
+// fatalln logs the message and exits, like log.Fatalln
+func fatalln(v ...interface{}) {
+	log.Println(v...)
+	exit(1)
+}
+
 func init() {
 	cobra.OnInitialize(initConfig)
 
@@ -55,6 +84,10 @@ func init() {
 	rootCmd.PersistentFlags().StringVarP(&personalaccesstoken, "pat", "t", "", "Personal access token (available in TFS)")
 	rootCmd.PersistentFlags().StringVarP(&collection, "collection", "c", "DefaultCollection", "TFS collection")
 	rootCmd.PersistentFlags().StringVarP(&loglevel, "loglevel", "l", "WARN", "Log level: DEBUG/INFO/WARN/ERROR")
+	rootCmd.PersistentFlags().StringVar(&recordDir, "record", "", "Record every TFS request and response to this directory (credentials and secrets are redacted)")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Don't use (or update) the local response cache")
+	rootCmd.PersistentFlags().StringVar(&replayDir, "replay", "", "Replay the TFS responses recorded in this directory, instead of contacting TFS")
+	rootCmd.PersistentFlags().Float64("rate", 0, "Most requests per second to send to TFS (default is 'ratelimit' from the config file, or no limit)")
 
 	//	Bind config flags for optional config file override:
 	viper.BindPFlag("url", rootCmd.PersistentFlags().Lookup("url"))
@@ -62,11 +95,21 @@ func init() {
 	viper.BindPFlag("collection", rootCmd.PersistentFlags().Lookup("collection"))
 	viper.BindPFlag("project", rootCmd.PersistentFlags().Lookup("project"))
 	viper.BindPFlag("loglevel", rootCmd.PersistentFlags().Lookup("loglevel"))
+	viper.BindPFlag("ratelimit", rootCmd.PersistentFlags().Lookup("rate"))
+
+	//	Complete project and collection names from TFS
+	rootCmd.RegisterFlagCompletionFunc("project", completeProjects)
+	rootCmd.RegisterFlagCompletionFunc("collection", completeCollections)
 }
 
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
 
+	//	The shell keeps the config (and the transport) it was started with for every command
+	if shellStarted {
+		return
+	}
+
 	if cfgFile != "" {
 		// Use config file from the flag.
 		viper.SetConfigFile(cfgFile)
@@ -76,7 +119,7 @@ func initConfig() {
 		home, err := homedir.Dir()
 		if err != nil {
 			fmt.Println(err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		viper.SetConfigName("tfsutil") // name of config file (without extension)
@@ -91,6 +134,11 @@ func initConfig() {
 	viper.SetDefault("pat", "")
 	viper.SetDefault("collection", "")
 	viper.SetDefault("project", "")
+	viper.SetDefault("releaseurl", "")
+	viper.SetDefault("cachettl", "5m")
+	viper.SetDefault("cachedir", "")
+	viper.SetDefault("ratelimit", 0)
+	viper.SetDefault("parallel", tfs.DefaultParallelism)
 	viper.SetDefault("loglevel", "WARN")
 
 	// If a config file is found, read it in
@@ -130,4 +178,101 @@ func initConfig() {
 	if viper.GetString("project") != "" {
 		log.Printf("[DEBUG] Using TFS project: %s\n", viper.GetString("project"))
 	}
+
+	//	Limit how fast we send requests (cached responses don't count), if we've been asked to
+	if rate := viper.GetFloat64("ratelimit"); rate > 0 && replayDir == "" {
+		tfs.Transport = tfs.NewRateLimiter(rate, tfs.Transport)
+		log.Printf("[DEBUG] Sending at most %v requests a second", rate)
+	}
+
+	//	Record or replay requests, if we've been asked to.  Otherwise, cache responses
+	initCassette()
+	if recordDir == "" && replayDir == "" && !noCache {
+		initCache()
+	}
+}
+
+// skipCache stops reading from the local response cache, for commands that change things and then read them
+// back.  The changes still invalidate the cached lists, so later commands don't see stale ones
+func skipCache() {
+	if cache, ok := tfs.Transport.(*tfs.Cache); ok {
+		readThrough := *cache
+		readThrough.SkipReads = true
+		tfs.Transport = &readThrough
+	}
+}
+
+// initCache sets up the tfs transport to keep responses in the local cache
+func initCache() {
+
+	ttl, err := time.ParseDuration(viper.GetString("cachettl"))
+	if err != nil {
+		log.Printf("[WARN] Invalid cachettl '%s' -- not using the cache: %s", viper.GetString("cachettl"), err)
+		return
+	}
+
+	dir, err := getCacheDir()
+	if err != nil {
+		log.Printf("[WARN] Unable to find the cache directory -- not using the cache: %s", err)
+		return
+	}
+
+	//	Keep the responses for different servers and credentials apart
+	cache, err := tfs.NewCache(dir, tfs.CacheProfile(viper.GetString("tfsurl")+"\n"+viper.GetString("pat")), ttl, tfs.Transport)
+	if err != nil {
+		log.Printf("[WARN] Unable to use the cache directory %s: %s", dir, err)
+		return
+	}
+
+	tfs.Transport = cache
+	log.Printf("[DEBUG] Caching responses in %s for %s", dir, ttl)
+}
+
+// getCacheDir gets the directory for the local response cache
+func getCacheDir() (string, error) {
+	if viper.GetString("cachedir") != "" {
+		return viper.GetString("cachedir"), nil
+	}
+
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".tfsutil", "cache"), nil
+}
+
+// initCassette sets up the tfs transport to record requests to (or replay them from) a cassette directory
+func initCassette() {
+
+	if recordDir != "" && replayDir != "" {
+		fmt.Println("Please use either --record or --replay, not both")
+		exit(1)
+	}
+
+	if recordDir != "" {
+		recorder, err := tfs.NewRecorder(recordDir, viper.GetString("tfsurl"), tfs.Transport)
+		if err != nil {
+			fatalf("[ERROR] Unable to record to %s - \n %s", recordDir, err)
+		}
+		tfs.Transport = recorder
+		log.Printf("[DEBUG] Recording requests to %s", recordDir)
+	}
+
+	if replayDir != "" {
+		replayer, err := tfs.NewReplayer(replayDir)
+		if err != nil {
+			fatalf("[ERROR] Unable to replay from %s - \n %s", replayDir, err)
+		}
+		tfs.Transport = replayer
+		log.Printf("[DEBUG] Replaying requests from %s", replayDir)
+
+		//	We won't contact TFS, so we don't need real settings
+		if viper.GetString("tfsurl") == "" {
+			viper.Set("tfsurl", replayer.Info.TfsURL)
+		}
+		if viper.GetString("pat") == "" {
+			viper.Set("pat", "replay")
+		}
+	}
 }
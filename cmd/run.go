@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/script"
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+var (
+	runVars       []string
+	runOnError    string
+	runNoRollback bool
+	runDryRun     bool
+	runOutput     string
+)
+
+// runCmd represents the run command
+var runCmd = &cobra.Command{
+	Use:   "run <script.yml>",
+	Short: "Run a list of operations from a script",
+	Long: `Runs the steps in a YAML script in order, and reports what happened to each one.
+The actions are create-project, create-group, copy-group, set-variables and delete-group.
+
+Every step is checked before anything is run.  If a step fails, the rest are skipped
+and the steps that created a project or group are undone (newest first) -- use
+--no-rollback to keep them, or --on-error continue to run the rest of the steps anyway.
+
+The script is a template: it can use its 'vars' (override them with --var name=value)
+and environment variables, like '{{env "DB_PASSWORD"}}':
+
+vars:
+  env: qa
+project: Website
+steps:
+- action: create-project
+  project: Website {{.env}}
+  process: Agile
+- action: copy-group
+  group: Website variables
+  to: Website variables ({{.env}})
+  toProject: Website {{.env}}
+- action: set-variables
+  project: Website {{.env}}
+  group: Website variables ({{.env}})
+  variables:
+    Environment: "{{.env}}"
+  secrets:
+    DbPassword: '{{env "DB_PASSWORD"}}'
+  remove: [DevOnly]
+
+Example:
+tfsutil run bootstrap.yml --var env=prod --dry-run
+
+`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("Requires a script file")
+		}
+		if runOnError != "" && runOnError != script.Stop && runOnError != script.Continue {
+			return errors.New("--on-error should be stop or continue")
+		}
+		return nil
+	},
+	Run: run,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+
+		//	Verify that we have a tfsurl and a pat
+		if strings.TrimSpace(viper.GetString("tfsurl")) == "" {
+			fmt.Printf("\nThis tool requires a TFS base url to operate.   \n\nPlease specify one on the command line or in the config file 'tfsutil.yml' \nFor help creating a config file, see the command 'tfsutil config create'\n")
+			exit(1)
+		}
+
+		if strings.TrimSpace(viper.GetString("pat")) == "" {
+			fmt.Printf("\nThis tool requires a TFS Personal Access Token (pat) for authentication.  \n\nPlease specify a pat on the command line or in the config file 'tfsutil.yml' \nFor help creating a config file, see the command 'tfsutil config create'\n")
+			exit(1)
+		}
+
+		//	Scripts create things and then look them up (or wait for them), so they don't read from the cache
+		skipCache()
+	},
+}
+
+func run(cmd *cobra.Command, args []string) {
+
+	//	Read the --var overrides
+	vars := map[string]string{}
+	for _, item := range runVars {
+		parts := strings.SplitN(item, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			fatalf("[ERROR] Invalid --var '%s' (expected name=value)", item)
+		}
+		vars[parts[0]] = parts[1]
+	}
+
+	data, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		fatalf("[ERROR] Reading %s - \n %s", args[0], err)
+	}
+
+	steps, err := script.Load(data, vars)
+	if err != nil {
+		fatalf("[ERROR] Reading %s - \n %s", args[0], err)
+	}
+
+	runner := &script.Runner{
+		Client:     tfs.Client{TfsURL: viper.GetString("tfsurl")},
+		Collection: viper.GetString("collection"),
+		Project:    viper.GetString("project"),
+		OnError:    runOnError,
+		NoRollback: runNoRollback,
+		DryRun:     runDryRun,
+		Progress: func(result script.StepResult) {
+			fmt.Fprintf(os.Stderr, "[%v/%v] %s %s: %s\n", result.Step, len(steps.Steps), result.Action, result.Target, result.Result)
+		},
+	}
+
+	report := runner.Run(steps)
+
+	//	Begin the report:
+	if runOutput == "" || runOutput == "table" {
+		fmt.Printf("\nScript: %v", args[0])
+		fmt.Printf("\nSteps: %v (%v failed, %v rolled back)\n===================\n", len(report.Steps), report.Failed, len(report.Rollback))
+	}
+
+	rows := [][]string{}
+	for _, result := range report.Steps {
+		rows = append(rows, []string{strconv.Itoa(result.Step), result.Action, result.Target, result.Result})
+	}
+	for _, result := range report.Rollback {
+		rows = append(rows, []string{strconv.Itoa(result.Step), "rollback " + result.Action, result.Target, result.Result})
+	}
+
+	if err := writeRows(runOutput, []string{"Step", "Action", "Target", "Result"}, rows); err != nil {
+		fatalln("[ERROR] Writing the report \n", err)
+	}
+
+	if report.Failed > 0 {
+		exit(1)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(runCmd)
+
+	runCmd.Flags().StringArrayVar(&runVars, "var", []string{}, "Set a script variable (name=value).  Can be used more than once")
+	runCmd.Flags().StringVar(&runOnError, "on-error", "", "What to do when a step fails: stop/continue (default is the script's onError, or stop)")
+	runCmd.Flags().BoolVar(&runNoRollback, "no-rollback", false, "Don't undo the steps that worked when a step fails")
+	runCmd.Flags().BoolVar(&runDryRun, "dry-run", false, "Show the steps that would run, without changing anything")
+	runCmd.Flags().StringVarP(&runOutput, "output", "o", "table", "Output format: table/json/csv/tsv")
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/danesparza/tfsutil/script"
+	"github.com/danesparza/tfsutil/tfs"
+	"github.com/danesparza/tfsutil/tfs/tfstest"
+)
+
+// A script shouldn't read cached lists, but the things it changes should still be fetched again afterwards (rather
+// than listed from a stale cached copy)
+func TestSkipCache_MutatingScript_ListIsNotStale(t *testing.T) {
+
+	//	Arrange
+	server := tfstest.NewServerWithGroup()
+	defer server.Close()
+	client := server.Client()
+
+	dir, err := ioutil.TempDir("", "cache")
+	if err != nil {
+		t.Fatalf("Unable to create a temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	saved := tfs.Transport
+	defer func() { tfs.Transport = saved }()
+
+	cache, err := tfs.NewCache(dir, tfs.CacheProfile("test"), time.Hour, tfs.Transport)
+	if err != nil {
+		t.Fatalf("Unable to create the cache: %s", err)
+	}
+	tfs.Transport = cache
+
+	before, _ := client.GetListOfVariableGroups("DefaultCollection", "Website")
+
+	s, err := script.Load([]byte(`
+project: Website
+steps:
+- action: create-group
+  group: Dragon variables
+  variables:
+    Wings: leathery
+`), nil)
+	if err != nil {
+		t.Fatalf("Unable to load the script: %s", err)
+	}
+
+	//	Act
+	skipCache()
+	report := (&script.Runner{Client: client, Collection: "DefaultCollection", OperationInterval: time.Millisecond}).Run(s)
+	tfs.Transport = cache
+	after, _ := client.GetListOfVariableGroups("DefaultCollection", "Website")
+
+	//	Assert
+	if report.Failed != 0 {
+		t.Fatalf("Expected the script to work, but got %+v", report)
+	}
+
+	if before.Count != 1 || after.Count != 2 {
+		t.Errorf("Expected 1 group before the script and 2 after, but got %v and %v", before.Count, after.Count)
+	}
+
+	if cache.SkipReads {
+		t.Errorf("Expected skipCache to leave the shared cache reading cached responses")
+	}
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// securefileCmd represents the secure file base command
+var securefileCmd = &cobra.Command{
+	Use:   "securefile",
+	Short: "Secure file helpers",
+	Long:  `Operations to help with secure files (like signing certificates).  You can list, upload (or replace) and delete them`,
+	Run: func(cmd *cobra.Command, args []string) {
+		//	This command on it's own should just show help
+		cmd.Help()
+	},
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+
+		//	Verify that we have a tfsurl and a pat
+		if strings.TrimSpace(viper.GetString("tfsurl")) == "" {
+			fmt.Printf("\nThis tool requires a TFS base url to operate.   \n\nPlease specify one on the command line or in the config file 'tfsutil.yml' \nFor help creating a config file, see the command 'tfsutil config create'\n")
+			exit(1)
+		}
+
+		if strings.TrimSpace(viper.GetString("pat")) == "" {
+			fmt.Printf("\nThis tool requires a TFS Personal Access Token (pat) for authentication.  \n\nPlease specify a pat on the command line or in the config file 'tfsutil.yml' \nFor help creating a config file, see the command 'tfsutil config create'\n")
+			exit(1)
+		}
+
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(securefileCmd)
+}
+
+// findSecureFile finds a single secure file by name (or id) in the given collection and project.  The boolean
+// is false if the file wasn't found
+func findSecureFile(client tfs.Client, collection, project, name string) (tfs.SecureFile, bool, error) {
+
+	retval, err := client.GetListOfSecureFiles(collection, project)
+	if err != nil {
+		return tfs.SecureFile{}, false, fmt.Errorf("Finding secure file: %s", err)
+	}
+
+	for _, secureFile := range retval.SecureFiles {
+		if strings.EqualFold(secureFile.Name, name) || secureFile.ID == name {
+			return secureFile, true, nil
+		}
+	}
+
+	return tfs.SecureFile{}, false, nil
+}
+
+// requireSecureFileArg makes sure a secure file name was passed
+func requireSecureFileArg(cmd *cobra.Command, args []string) error {
+	if len(args) < 1 {
+		return errors.New("Requires a secure file name")
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// securefileDeleteCmd represents the securefile delete command
+var securefileDeleteCmd = &cobra.Command{
+	Use:   "delete \"<name>\"",
+	Short: "Delete a secure file",
+	Long: `Deletes a secure file.  Pipelines that use the file will fail until it is uploaded again.
+
+Example:
+tfsutil securefile delete signing-2019Q1.pfx
+
+`,
+	Args: requireSecureFileArg,
+	Run:  securefiledelete,
+}
+
+func securefiledelete(cmd *cobra.Command, args []string) {
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	secureFile, found, err := findSecureFile(client, viper.GetString("collection"), viper.GetString("project"), args[0])
+	if err != nil {
+		fatalln("[ERROR] ", err)
+	}
+	if !found {
+		fatalf("[ERROR] Sorry -- I couldn't find the secure file '%s'", args[0])
+	}
+
+	if err := client.DeleteSecureFile(viper.GetString("collection"), viper.GetString("project"), secureFile.ID); err != nil {
+		fatalln("[ERROR] Deleting secure file \n", err)
+	}
+
+	fmt.Printf("\nDeleted secure file '%s'\n", secureFile.Name)
+}
+
+func init() {
+	securefileCmd.AddCommand(securefileDeleteCmd)
+}
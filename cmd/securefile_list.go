@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// securefileListCmd represents the securefile list command
+var securefileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List secure files",
+	Long:  `Lists secure files, along with who last changed them, their properties and whether all pipelines can use them`,
+	Run:   securefilelist,
+}
+
+func securefilelist(cmd *cobra.Command, args []string) {
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	//	Get the list of secure files.  Report any errors
+	retval, err := client.GetListOfSecureFiles(viper.GetString("collection"), viper.GetString("project"))
+	if err != nil {
+		fatalln("[ERROR] Secure file list \n", err)
+	}
+
+	//	Sort the secure files
+	sort.Slice(retval.SecureFiles, func(i, j int) bool {
+		return strings.ToLower(retval.SecureFiles[i].Name) < strings.ToLower(retval.SecureFiles[j].Name)
+	})
+
+	//	Begin the report:
+	fmt.Printf("\nCollection: %v", viper.GetString("collection"))
+	fmt.Printf("\nProject: %v\n", viper.GetString("project"))
+	fmt.Printf("\nSecure files found: %v\n======================\n", retval.Count)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Name\tModified\tModified by\tAll pipelines\tProperties")
+	for _, secureFile := range retval.SecureFiles {
+
+		//	Older servers don't have the pipeline permissions API, so don't stop if we can't get them
+		authorized := "?"
+		permissions, err := client.GetPipelinePermissions(viper.GetString("collection"), viper.GetString("project"), "securefile", secureFile.ID)
+		if err != nil {
+			log.Printf("[DEBUG] Unable to get the pipeline permissions for '%s': %s", secureFile.Name, err)
+		} else {
+			authorized = fmt.Sprint(permissions.AllPipelinesAuthorized())
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", secureFile.Name, secureFile.ModifiedOn.Local().Format("2006-01-02"), secureFile.ModifiedBy.DisplayName, authorized, formatProperties(secureFile.Properties))
+	}
+	w.Flush()
+}
+
+// formatProperties formats a map of properties as a sorted list of key=value pairs
+func formatProperties(properties map[string]string) string {
+	items := []string{}
+	for key, value := range properties {
+		items = append(items, key+"="+value)
+	}
+	sort.Strings(items)
+	return strings.Join(items, ", ")
+}
+
+func init() {
+	securefileCmd.AddCommand(securefileListCmd)
+}
@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+var (
+	securefileUploadName       string
+	securefileUploadReplace    bool
+	securefileUploadProperties []string
+	securefileUploadAuthorize  bool
+)
+
+// securefileUploadCmd represents the securefile upload command
+var securefileUploadCmd = &cobra.Command{
+	Use:   "upload <path>",
+	Short: "Upload (or replace) a secure file",
+	Long: `Uploads a file as a secure file.  The secure file is named after the file, unless --name is given.
+
+Use --replace to replace an existing secure file with the same name.  The existing file
+is set aside until the new one is uploaded, and is only deleted once the upload works.
+Its properties, its 'authorize for use in all pipelines' setting and the pipelines
+authorized to use it are carried over to the new file (use --property and --authorize
+to change them).  If the authorizations can't be carried over, the existing file is kept
+under the name it was set aside with.
+
+Example:
+tfsutil securefile upload ./certs/signing.pfx --replace --property expires=2019-12-31
+
+`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("Requires the path of the file to upload")
+		}
+		return nil
+	},
+	Run: securefileupload,
+}
+
+func securefileupload(cmd *cobra.Command, args []string) {
+
+	collection := viper.GetString("collection")
+	project := viper.GetString("project")
+
+	name := securefileUploadName
+	if name == "" {
+		name = filepath.Base(args[0])
+	}
+
+	//	Gather the properties from our flags
+	properties := map[string]string{}
+	for _, item := range securefileUploadProperties {
+		parts := strings.SplitN(item, "=", 2)
+		if len(parts) != 2 {
+			fatalf("[ERROR] Expected --property key=value, but got '%s'", item)
+		}
+		properties[strings.TrimSpace(parts[0])] = parts[1]
+	}
+
+	content, err := os.Open(args[0])
+	if err != nil {
+		fatalf("[ERROR] Reading %s - \n %s", args[0], err)
+	}
+	defer content.Close()
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	existing, found, err := findSecureFile(client, collection, project, name)
+	if err != nil {
+		fatalln("[ERROR] ", err)
+	}
+
+	if found && !securefileUploadReplace {
+		fatalf("[ERROR] The secure file '%s' already exists (use --replace to replace it)", name)
+	}
+
+	//	Set the existing file aside, keeping its properties and authorizations
+	authorize := securefileUploadAuthorize
+	pipelineIDs := []int{}
+	if found {
+		for key, value := range existing.Properties {
+			if _, ok := properties[key]; !ok {
+				properties[key] = value
+			}
+		}
+
+		permissions, err := client.GetPipelinePermissions(collection, project, "securefile", existing.ID)
+		if err != nil {
+			log.Printf("[WARN] Unable to get the pipeline permissions for '%s' -- they won't be carried over: %s", name, err)
+		} else {
+			pipelineIDs = permissions.AuthorizedPipelineIDs()
+			if !cmd.Flags().Changed("authorize") {
+				authorize = permissions.AllPipelinesAuthorized()
+			}
+		}
+
+		existing.Name = fmt.Sprintf("%s.replaced-%s", name, time.Now().Format("20060102150405"))
+		if _, err := client.UpdateSecureFile(collection, project, existing); err != nil {
+			fatalln("[ERROR] Setting the existing secure file aside \n", err)
+		}
+	}
+
+	created, err := client.UploadSecureFile(collection, project, name, content)
+	if err != nil {
+		//	Put the existing file back the way it was
+		if found {
+			existing.Name = name
+			if _, err := client.UpdateSecureFile(collection, project, existing); err != nil {
+				log.Printf("[ERROR] Unable to restore the name of the existing secure file (it is now '%s'): %s", existing.Name, err)
+			}
+		}
+		fatalln("[ERROR] Uploading secure file \n", err)
+	}
+	fmt.Printf("\nUploaded %s as secure file '%s' (id %s)\n", args[0], created.Name, created.ID)
+
+	if len(properties) > 0 {
+		created.Properties = properties
+		if _, err := client.UpdateSecureFile(collection, project, created); err != nil {
+			log.Printf("[ERROR] Unable to set the properties: %s", err)
+		} else {
+			fmt.Printf("Properties: %s\n", formatProperties(properties))
+		}
+	}
+
+	authorized := true
+	if authorize || len(pipelineIDs) > 0 {
+		permissions := tfs.ResourcePipelinePermissions{}
+		if authorize {
+			permissions.AllPipelines = &tfs.PipelinePermission{Authorized: true}
+		}
+		for _, id := range pipelineIDs {
+			permissions.Pipelines = append(permissions.Pipelines, tfs.PipelinePermission{ID: id, Authorized: true})
+		}
+
+		if _, err := client.SetPipelinePermissions(collection, project, "securefile", created.ID, permissions); err != nil {
+			log.Printf("[ERROR] Unable to authorize the secure file (for all pipelines: %v, for pipelines: %v): %s", authorize, pipelineIDs, err)
+			authorized = false
+		} else {
+			if authorize {
+				fmt.Println("Authorized for use in all pipelines")
+			}
+			if len(pipelineIDs) > 0 {
+				fmt.Printf("Authorized for use in pipelines: %v\n", pipelineIDs)
+			}
+		}
+	}
+
+	//	Keep the replaced file if its authorizations weren't copied, so they aren't lost
+	if found && !authorized {
+		log.Printf("[WARN] Keeping the replaced secure file '%s' (its authorizations weren't carried over)", existing.Name)
+	} else if found {
+		if err := client.DeleteSecureFile(collection, project, existing.ID); err != nil {
+			log.Printf("[ERROR] Unable to delete the replaced secure file '%s': %s", existing.Name, err)
+		} else {
+			fmt.Println("Deleted the secure file it replaced")
+		}
+	}
+}
+
+func init() {
+	securefileCmd.AddCommand(securefileUploadCmd)
+
+	securefileUploadCmd.Flags().StringVar(&securefileUploadName, "name", "", "Secure file name (default is the name of the file)")
+	securefileUploadCmd.Flags().BoolVar(&securefileUploadReplace, "replace", false, "Replace an existing secure file with the same name")
+	securefileUploadCmd.Flags().StringArrayVar(&securefileUploadProperties, "property", []string{}, "A property to set, as key=value")
+	securefileUploadCmd.Flags().BoolVar(&securefileUploadAuthorize, "authorize", false, "Authorize the secure file for use in all pipelines")
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/api"
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+var serveListen string
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve tfsutil operations as a REST API",
+	Long: `Starts a small REST API for other tools, using the TFS url and PAT from the config file.
+It can list projects, and list, diff, copy and export variable groups.  The API is described
+(OpenAPI 3.0) at /openapi.json.
+
+Every other request needs an 'Authorization: Bearer <token>' header.  Set the token with
+--token or 'servetoken' in the config file -- if there isn't one, a random token is made
+and printed when the server starts.  Each request is logged to the console.
+
+The server listens on localhost:8080 unless --listen says otherwise (like :8080 to
+accept requests from other machines).
+
+Example:
+tfsutil serve --token s3cr3t
+curl -H "Authorization: Bearer s3cr3t" http://localhost:8080/api/collections/DefaultCollection/projects
+
+`,
+	Run: serve,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+
+		//	Verify that we have a tfsurl and a pat
+		if strings.TrimSpace(viper.GetString("tfsurl")) == "" {
+			fmt.Printf("\nThis tool requires a TFS base url to operate.   \n\nPlease specify one on the command line or in the config file 'tfsutil.yml' \nFor help creating a config file, see the command 'tfsutil config create'\n")
+			exit(1)
+		}
+
+		if strings.TrimSpace(viper.GetString("pat")) == "" {
+			fmt.Printf("\nThis tool requires a TFS Personal Access Token (pat) for authentication.  \n\nPlease specify a pat on the command line or in the config file 'tfsutil.yml' \nFor help creating a config file, see the command 'tfsutil config create'\n")
+			exit(1)
+		}
+
+	},
+}
+
+func serve(cmd *cobra.Command, args []string) {
+
+	//	If we don't have a token, make one up
+	token := viper.GetString("servetoken")
+	if token == "" {
+		random := make([]byte, 24)
+		if _, err := rand.Read(random); err != nil {
+			fatalln("[ERROR] Creating a token \n", err)
+		}
+		token = hex.EncodeToString(random)
+		fmt.Printf("\nNo token was given, so this one was made up: %s\n", token)
+	}
+
+	handler := &api.Server{
+		Client: tfs.Client{TfsURL: viper.GetString("tfsurl")},
+		Token:  token,
+		Logger: log.New(os.Stdout, "", log.LstdFlags),
+	}
+
+	server := &http.Server{
+		Addr:         serveListen,
+		Handler:      handler,
+		ReadTimeout:  30 * time.Second,
+		WriteTimeout: 5 * time.Minute,
+	}
+
+	//	Stop cleanly (finishing the requests in progress) on Ctrl-C
+	stopped := make(chan struct{})
+	go func() {
+		interrupts := make(chan os.Signal, 1)
+		signal.Notify(interrupts, os.Interrupt)
+		<-interrupts
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		server.Shutdown(ctx)
+		close(stopped)
+	}()
+
+	fmt.Printf("\nServing the tfsutil API on %s (described at /openapi.json)\n", serveListen)
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		fatalln("[ERROR] Serving the API \n", err)
+	}
+
+	<-stopped
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().StringVar(&serveListen, "listen", "localhost:8080", "Address to listen on (use :8080 to listen on every interface)")
+	serveCmd.Flags().String("token", "", "Bearer token callers must send (default is 'servetoken' from the config file)")
+	viper.BindPFlag("servetoken", serveCmd.Flags().Lookup("token"))
+}
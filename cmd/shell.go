@@ -0,0 +1,528 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"github.com/chzyer/readline"
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// shellCmd represents the shell command
+var shellCmd = &cobra.Command{
+	Use:   "shell",
+	Short: "Start an interactive tfsutil shell",
+	Long: `Starts an interactive shell for running several tfsutil commands in a row against the
+same collection and project.  Type commands without 'tfsutil' in front of them:
+
+  use project Website
+  vg list
+  vg copy "Unicorn variables"
+
+Use 'use collection <name>' and 'use project <name>' to change the collection and project
+the following commands use (-c and -p still work for a single command).  Press TAB to
+complete command names, flags, and live project and variable group names.  History is
+kept in ~/.tfsutil/shell_history.  Type 'exit' (or press Ctrl-D) to leave the shell.
+
+Every command uses the config (and the response cache) the shell was started with, so
+global flags like --loglevel and --no-cache belong on the 'tfsutil shell' command line.
+An error in one command doesn't end the shell`,
+	Run: shell,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+
+		//	Verify that we have a tfsurl and a pat
+		if strings.TrimSpace(viper.GetString("tfsurl")) == "" {
+			fmt.Printf("\nThis tool requires a TFS base url to operate.   \n\nPlease specify one on the command line or in the config file 'tfsutil.yml' \nFor help creating a config file, see the command 'tfsutil config create'\n")
+			exit(1)
+		}
+
+		if strings.TrimSpace(viper.GetString("pat")) == "" {
+			fmt.Printf("\nThis tool requires a TFS Personal Access Token (pat) for authentication.  \n\nPlease specify a pat on the command line or in the config file 'tfsutil.yml' \nFor help creating a config file, see the command 'tfsutil config create'\n")
+			exit(1)
+		}
+
+	},
+}
+
+// shellSession is the state kept between the commands in a shell
+type shellSession struct {
+	client      tfs.Client
+	collection  string
+	project     string
+	globalFlags map[string]shellFlag
+}
+
+// shellLine is a line typed into the shell, split into words
+type shellLine struct {
+	// Words are the complete words in the line
+	Words []string
+
+	// Partial is the word being typed (if the line doesn't end with a space)
+	Partial string
+
+	// PartialLength is the number of characters typed for the partial word (including quotes)
+	PartialLength int
+
+	// Quote is the quote the partial word was started with, if it's still open
+	Quote rune
+}
+
+func shell(cmd *cobra.Command, args []string) {
+
+	session := &shellSession{
+		client:     tfs.Client{TfsURL: viper.GetString("tfsurl")},
+		collection: viper.GetString("collection"),
+		project:    viper.GetString("project"),
+	}
+	session.saveFlags()
+	shellStarted = true
+
+	history, err := getShellHistoryFile()
+	if err != nil {
+		log.Printf("[WARN] Unable to keep the shell history: %s", err)
+	}
+
+	reader, err := readline.NewEx(&readline.Config{
+		Prompt:            session.prompt(),
+		HistoryFile:       history,
+		HistorySearchFold: true,
+		AutoComplete:      session,
+		InterruptPrompt:   "^C",
+		EOFPrompt:         "exit",
+	})
+	if err != nil {
+		fatalln("[ERROR] Starting the shell \n", err)
+	}
+	defer reader.Close()
+
+	fmt.Println("tfsutil shell -- type 'help' for help, 'exit' to leave")
+
+	for {
+		input, err := reader.Readline()
+		if err == readline.ErrInterrupt {
+			continue
+		}
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			fatalln("[ERROR] Reading from the shell \n", err)
+		}
+
+		line := splitShellLine(input)
+		if line.Quote != 0 {
+			fmt.Printf("There's a missing closing %c\n", line.Quote)
+			continue
+		}
+
+		words := line.Words
+		if line.Partial != "" || line.PartialLength > 0 {
+			words = append(words, line.Partial)
+		}
+		if len(words) == 0 {
+			continue
+		}
+
+		switch words[0] {
+		case "exit", "quit":
+			return
+		case "help":
+			if len(words) == 1 {
+				printShellHelp()
+				continue
+			}
+		case "use":
+			session.use(words[1:])
+			reader.SetPrompt(session.prompt())
+			continue
+		case "shell":
+			fmt.Println("You're already in the shell")
+			continue
+		}
+
+		session.run(words)
+	}
+}
+
+// printShellHelp shows the shell's own commands, and the tfsutil commands
+func printShellHelp() {
+	fmt.Println(`Shell commands:
+  use                      Show the collection and project commands use
+  use collection <name>    Use a different collection
+  use project <name>       Use a different project
+  help [command]           Show this help, or the help for a command
+  exit                     Leave the shell
+
+tfsutil commands:`)
+	for _, command := range rootCmd.Commands() {
+		if command.IsAvailableCommand() && command.Name() != "shell" {
+			fmt.Printf("  %-24s %s\n", command.Name(), command.Short)
+		}
+	}
+}
+
+// prompt is the shell prompt, showing the collection and project in use
+func (s *shellSession) prompt() string {
+	if s.project == "" {
+		return fmt.Sprintf("tfsutil %s> ", s.collection)
+	}
+	return fmt.Sprintf("tfsutil %s/%s> ", s.collection, s.project)
+}
+
+// use changes the collection or project the following commands use
+func (s *shellSession) use(args []string) {
+	switch {
+	case len(args) == 0:
+		fmt.Printf("Collection: %v\nProject: %v\n", s.collection, s.project)
+	case len(args) == 2 && args[0] == "collection":
+		s.collection = args[1]
+	case len(args) == 2 && args[0] == "project":
+		s.project = args[1]
+	default:
+		fmt.Println("Usage: use collection <name> | use project <name>")
+	}
+}
+
+// shellExit is what a command panics with when it exits in the shell
+type shellExit struct {
+	code int
+}
+
+// run runs a tfsutil command (in the shell's process, with its config and client), with the collection
+// and project in use
+func (s *shellSession) run(args []string) {
+	s.resetFlags()
+
+	if s.collection != "" {
+		rootCmd.PersistentFlags().Set("collection", s.collection)
+	}
+	if s.project != "" {
+		rootCmd.PersistentFlags().Set("project", s.project)
+	}
+
+	//	A command that exits (or fails) only ends itself.  Ctrl-C is ignored while it runs, so it doesn't end
+	//	the shell
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+
+	transport := tfs.Transport
+	exit = func(code int) { panic(shellExit{code}) }
+	defer func() {
+		exit = os.Exit
+		tfs.Transport = transport
+
+		if r := recover(); r != nil {
+			if _, exited := r.(shellExit); !exited {
+				panic(r)
+			}
+		}
+		fmt.Println()
+	}()
+
+	//	Cobra shows any error with the command line itself
+	rootCmd.SetArgs(args)
+	rootCmd.Execute()
+}
+
+// saveFlags keeps the values of the global flags the shell was started with, so they can be put back
+// before each command
+func (s *shellSession) saveFlags() {
+	s.globalFlags = map[string]shellFlag{}
+	rootCmd.PersistentFlags().VisitAll(func(flag *pflag.Flag) {
+		s.globalFlags[flag.Name] = shellFlag{value: flag.Value.String(), changed: flag.Changed}
+	})
+}
+
+// resetFlags puts every flag back to its default (and the global flags back to the values the shell was
+// started with), so the flags used with one command aren't used with the next
+func (s *shellSession) resetFlags() {
+	var reset func(command *cobra.Command)
+	reset = func(command *cobra.Command) {
+		command.Flags().VisitAll(func(flag *pflag.Flag) { setShellFlag(flag, flag.DefValue, false) })
+		command.PersistentFlags().VisitAll(func(flag *pflag.Flag) { setShellFlag(flag, flag.DefValue, false) })
+		for _, sub := range command.Commands() {
+			reset(sub)
+		}
+	}
+	reset(rootCmd)
+
+	rootCmd.PersistentFlags().VisitAll(func(flag *pflag.Flag) {
+		if saved, found := s.globalFlags[flag.Name]; found {
+			setShellFlag(flag, saved.value, saved.changed)
+		}
+	})
+}
+
+// shellFlag is the value of a flag, saved by the shell
+type shellFlag struct {
+	value   string
+	changed bool
+}
+
+// setShellFlag sets a flag's value (as it's shown, like the default value) and whether it was changed.  A list
+// flag's value is replaced, rather than added to
+func setShellFlag(flag *pflag.Flag, value string, changed bool) {
+	if list, ok := flag.Value.(pflag.SliceValue); ok {
+		values := []string{}
+		if trimmed := strings.Trim(value, "[]"); trimmed != "" {
+			values = strings.Split(trimmed, ",")
+		}
+		list.Replace(values)
+	} else {
+		flag.Value.Set(value)
+	}
+	flag.Changed = changed
+}
+
+// Do completes the word being typed.  It's called by readline when TAB is pressed
+func (s *shellSession) Do(input []rune, pos int) ([][]rune, int) {
+	line := splitShellLine(string(input[:pos]))
+
+	retval := [][]rune{}
+	for _, candidate := range s.candidates(line.Words, line.Partial) {
+		if !strings.HasPrefix(candidate, line.Partial) {
+			continue
+		}
+
+		rest := candidate[len(line.Partial):]
+		if line.Quote != 0 {
+			rest += string(line.Quote)
+		} else {
+			rest = escapeShellWord(rest)
+		}
+		retval = append(retval, []rune(rest+" "))
+	}
+
+	return retval, line.PartialLength
+}
+
+// candidates gets the possible completions for the next word, given the words before it
+func (s *shellSession) candidates(words []string, partial string) []string {
+
+	//	The shell's own commands
+	if len(words) == 0 {
+		retval := []string{"use", "help", "exit"}
+		for _, command := range rootCmd.Commands() {
+			if command.IsAvailableCommand() && command.Name() != "shell" {
+				retval = append(retval, command.Name())
+			}
+		}
+		return retval
+	}
+
+	if words[0] == "use" {
+		switch {
+		case len(words) == 1:
+			return []string{"collection", "project"}
+		case len(words) == 2 && words[1] == "collection":
+			names, _ := collectionNames(s.client)
+			return names
+		case len(words) == 2 && words[1] == "project":
+			names, _ := projectNames(s.client, s.collection)
+			return names
+		}
+		return nil
+	}
+
+	if words[0] == "help" {
+		words = words[1:]
+	}
+
+	//	Find the command being typed, skipping flags (and their values)
+	command := rootCmd
+	collection, project := s.collection, s.project
+	positional := 0
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+
+		if strings.HasPrefix(word, "-") {
+			flag := lookupShellFlag(command, word)
+			if flag == nil || flag.NoOptDefVal != "" || strings.Contains(word, "=") {
+				continue
+			}
+
+			//	The flag's value is the next word (or the one being typed)
+			if i+1 == len(words) {
+				switch flag.Name {
+				case "collection":
+					names, _ := collectionNames(s.client)
+					return names
+				case "project":
+					names, _ := projectNames(s.client, collection)
+					return names
+				}
+				return nil
+			}
+
+			i++
+			switch flag.Name {
+			case "collection":
+				collection = words[i]
+			case "project":
+				project = words[i]
+			}
+			continue
+		}
+
+		if sub := findShellSubcommand(command, word); sub != nil && positional == 0 {
+			command = sub
+			continue
+		}
+		positional++
+	}
+
+	if strings.HasPrefix(partial, "-") {
+		return shellFlagNames(command)
+	}
+
+	if positional == 0 && command.HasAvailableSubCommands() {
+		retval := []string{}
+		for _, sub := range command.Commands() {
+			if sub.IsAvailableCommand() {
+				retval = append(retval, sub.Name())
+			}
+		}
+		return retval
+	}
+
+	if positional == 0 && variableGroupArgCommands[command.CommandPath()] {
+		names, _ := variableGroupNames(s.client, collection, project)
+		return names
+	}
+
+	return nil
+}
+
+// findShellSubcommand finds a subcommand by name or alias
+func findShellSubcommand(command *cobra.Command, name string) *cobra.Command {
+	for _, sub := range command.Commands() {
+		if sub.Name() == name || sub.HasAlias(name) {
+			return sub
+		}
+	}
+	return nil
+}
+
+// lookupShellFlag finds the flag a word (like -p or --project=Website) refers to
+func lookupShellFlag(command *cobra.Command, word string) *pflag.Flag {
+	name := strings.SplitN(strings.TrimLeft(word, "-"), "=", 2)[0]
+
+	flags := pflag.NewFlagSet(command.Name(), pflag.ContinueOnError)
+	flags.AddFlagSet(command.Flags())
+	flags.AddFlagSet(command.InheritedFlags())
+
+	if strings.HasPrefix(word, "--") {
+		return flags.Lookup(name)
+	}
+	if len(name) != 1 {
+		return nil
+	}
+	return flags.ShorthandLookup(name)
+}
+
+// shellFlagNames gets the long names of the flags a command takes
+func shellFlagNames(command *cobra.Command) []string {
+	retval := []string{}
+
+	add := func(flag *pflag.Flag) {
+		if !flag.Hidden {
+			retval = append(retval, "--"+flag.Name)
+		}
+	}
+	command.Flags().VisitAll(add)
+	command.InheritedFlags().VisitAll(add)
+
+	return retval
+}
+
+// splitShellLine splits a line into words, like a shell does: words can be quoted with double or
+// single quotes, and a backslash escapes the next character
+func splitShellLine(input string) shellLine {
+	retval := shellLine{Words: []string{}}
+
+	word := strings.Builder{}
+	inWord := false
+	escaped := false
+	length := 0
+
+	for _, r := range input {
+		if inWord {
+			length++
+		}
+
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case r == '\\' && retval.Quote != '\'':
+			escaped = true
+		case retval.Quote != 0:
+			if r == retval.Quote {
+				retval.Quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			retval.Quote = r
+		case r == ' ' || r == '\t':
+			if inWord {
+				retval.Words = append(retval.Words, word.String())
+				word.Reset()
+				inWord = false
+			}
+			continue
+		default:
+			word.WriteRune(r)
+		}
+
+		if !inWord {
+			inWord = true
+			length = 1
+		}
+	}
+
+	if inWord {
+		retval.Partial = word.String()
+		retval.PartialLength = length
+	}
+
+	return retval
+}
+
+// escapeShellWord escapes the characters splitShellLine would treat specially
+func escapeShellWord(word string) string {
+	return strings.NewReplacer(`\`, `\\`, " ", `\ `, `"`, `\"`, "'", `\'`, "\t", "\\\t").Replace(word)
+}
+
+// getShellHistoryFile gets the file the shell history is kept in
+func getShellHistoryFile() (string, error) {
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(home, ".tfsutil")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "shell_history"), nil
+}
+
+// shellStarted is true once the shell has started, and runs the commands it's given itself
+var shellStarted bool
+
+func init() {
+	rootCmd.AddCommand(shellCmd)
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/danesparza/tfsutil/tfs"
+	"github.com/danesparza/tfsutil/tfs/tfstest"
+)
+
+// Lines should be split into words like a shell does, with the word being typed kept apart
+func TestSplitShellLine_Lines_ReturnsWords(t *testing.T) {
+
+	//	Arrange
+	tests := []struct {
+		input   string
+		words   []string
+		partial string
+		length  int
+		quote   rune
+	}{
+		{"", []string{}, "", 0, 0},
+		{"vg list", []string{"vg"}, "list", 4, 0},
+		{"vg list ", []string{"vg", "list"}, "", 0, 0},
+		{`vg copy "Unicorn variables" `, []string{"vg", "copy", "Unicorn variables"}, "", 0, 0},
+		{`vg copy 'Unicorn var`, []string{"vg", "copy"}, "Unicorn var", 12, '\''},
+		{`vg copy Unicorn\ var`, []string{"vg", "copy"}, "Unicorn var", 12, 0},
+		{`vg copy "say \"hi\"" x`, []string{"vg", "copy", `say "hi"`}, "x", 1, 0},
+		{`vg copy 'C:\temp' `, []string{"vg", "copy", `C:\temp`}, "", 0, 0},
+		{"use\tproject  Website", []string{"use", "project"}, "Website", 7, 0},
+	}
+
+	for _, test := range tests {
+		//	Act
+		line := splitShellLine(test.input)
+
+		//	Assert
+		if !reflect.DeepEqual(line.Words, test.words) || line.Partial != test.partial || line.PartialLength != test.length || line.Quote != test.quote {
+			t.Errorf("Expected '%s' to split into %q + '%s' (length %v, quote %q), but got %q + '%s' (length %v, quote %q)",
+				test.input, test.words, test.partial, test.length, test.quote, line.Words, line.Partial, line.PartialLength, line.Quote)
+		}
+	}
+}
+
+// Escaped words should split back into the same word
+func TestEscapeShellWord_Words_SplitBackToTheSameWord(t *testing.T) {
+
+	//	Arrange
+	tests := map[string]string{
+		"Website":           "Website",
+		"Unicorn variables": `Unicorn\ variables`,
+		`say "hi"`:          `say\ \"hi\"`,
+		"it's":              `it\'s`,
+		`C:\temp`:           `C:\\temp`,
+	}
+
+	for word, expected := range tests {
+		//	Act
+		escaped := escapeShellWord(word)
+		line := splitShellLine(escaped)
+
+		//	Assert
+		if escaped != expected {
+			t.Errorf("Expected '%s' to be escaped as '%s', but got '%s'", word, expected, escaped)
+		}
+
+		if line.Partial != word || len(line.Words) != 0 {
+			t.Errorf("Expected '%s' to split back into '%s', but got %q + '%s'", escaped, word, line.Words, line.Partial)
+		}
+	}
+}
+
+// The completions should be the shell's commands, subcommands, flags, or live names (depending on what's
+// been typed so far)
+func TestShellSession_Candidates_ReturnsCompletions(t *testing.T) {
+
+	//	Arrange
+	server := tfstest.NewServer()
+	defer server.Close()
+	server.AddProject("ShellCollection", tfs.Project{Name: "Website"})
+	server.AddProject("ShellCollection", tfs.Project{Name: "Mobile"})
+	server.AddVariableGroup("ShellCollection", "Website", tfs.VariableGroup{Name: "Unicorn variables"})
+	server.AddVariableGroup("ShellCollection", "Mobile", tfs.VariableGroup{Name: "Dragon variables"})
+
+	session := &shellSession{client: server.Client(), collection: "ShellCollection", project: "Website"}
+
+	tests := []struct {
+		words    []string
+		partial  string
+		includes []string
+		excludes []string
+	}{
+		{[]string{}, "", []string{"use", "help", "exit", "vg", "project"}, []string{"shell"}},
+		{[]string{"use"}, "", []string{"collection", "project"}, []string{"vg"}},
+		{[]string{"use", "project"}, "", []string{"Website", "Mobile"}, nil},
+		{[]string{"vg"}, "", []string{"list", "copy", "set"}, []string{"vg"}},
+		{[]string{"help", "vg"}, "", []string{"list", "copy"}, nil},
+		{[]string{"vg", "list"}, "--p", []string{"--project", "--collection"}, nil},
+		{[]string{"vg", "list", "--project"}, "", []string{"Website", "Mobile"}, nil},
+		{[]string{"vg", "copy"}, "", []string{"Unicorn variables"}, []string{"Dragon variables"}},
+		{[]string{"vg", "copy", "-p", "Mobile"}, "", []string{"Dragon variables"}, []string{"Unicorn variables"}},
+		{[]string{"vg", "copy", "Unicorn variables"}, "", nil, []string{"Unicorn variables"}},
+	}
+
+	for _, test := range tests {
+		//	Act
+		candidates := session.candidates(test.words, test.partial)
+
+		//	Assert
+		found := map[string]bool{}
+		for _, candidate := range candidates {
+			found[candidate] = true
+		}
+
+		for _, expected := range test.includes {
+			if !found[expected] {
+				t.Errorf("Expected the completions after %q to include '%s', but got %q", test.words, expected, candidates)
+			}
+		}
+
+		for _, unexpected := range test.excludes {
+			if found[unexpected] {
+				t.Errorf("Expected the completions after %q not to include '%s', but got %q", test.words, unexpected, candidates)
+			}
+		}
+	}
+}
+
+// The flags used with one command shouldn't be used with the next, but the global flags the shell was started
+// with should be kept
+func TestShellSession_ResetFlags_RestoresDefaultsAndGlobalFlags(t *testing.T) {
+
+	//	Arrange
+	rootCmd.PersistentFlags().Set("loglevel", "ERROR")
+	defer rootCmd.PersistentFlags().Set("loglevel", "WARN")
+
+	session := &shellSession{}
+	session.saveFlags()
+
+	vgSetCmd.Flags().Set("remove", "Horn")
+	vgSetCmd.Flags().Set("secret", "true")
+	rootCmd.PersistentFlags().Set("loglevel", "DEBUG")
+	rootCmd.PersistentFlags().Set("project", "Mobile")
+
+	//	Act
+	session.resetFlags()
+
+	//	Assert
+	if len(vgSetRemove) != 0 || vgSetSecret || vgSetCmd.Flags().Changed("remove") {
+		t.Errorf("Expected vg set's flags to be reset, but got --remove %q and --secret %v", vgSetRemove, vgSetSecret)
+	}
+
+	if loglevel != "ERROR" || project != "" || rootCmd.PersistentFlags().Changed("project") {
+		t.Errorf("Expected the global flags the shell started with, but got --loglevel %s and --project '%s'", loglevel, project)
+	}
+}
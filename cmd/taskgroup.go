@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// taskgroupCmd represents the task group base command
+var taskgroupCmd = &cobra.Command{
+	Use:   "taskgroup",
+	Short: "Task group helpers",
+	Long:  `Operations to help with task groups.  You can list, show, export and import them (with their version history)`,
+	Run: func(cmd *cobra.Command, args []string) {
+		//	This command on it's own should just show help
+		cmd.Help()
+	},
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+
+		//	Verify that we have a tfsurl and a pat
+		if strings.TrimSpace(viper.GetString("tfsurl")) == "" {
+			fmt.Printf("\nThis tool requires a TFS base url to operate.   \n\nPlease specify one on the command line or in the config file 'tfsutil.yml' \nFor help creating a config file, see the command 'tfsutil config create'\n")
+			exit(1)
+		}
+
+		if strings.TrimSpace(viper.GetString("pat")) == "" {
+			fmt.Printf("\nThis tool requires a TFS Personal Access Token (pat) for authentication.  \n\nPlease specify a pat on the command line or in the config file 'tfsutil.yml' \nFor help creating a config file, see the command 'tfsutil config create'\n")
+			exit(1)
+		}
+
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(taskgroupCmd)
+}
+
+// findTaskGroup finds the latest version of a single task group by name (or id) in the given collection and project
+func findTaskGroup(client tfs.Client, collection, project, name string) (tfs.TaskGroup, error) {
+
+	retval, err := client.GetListOfTaskGroups(collection, project)
+	if err != nil {
+		return tfs.TaskGroup{}, fmt.Errorf("Finding task group: %s", err)
+	}
+
+	for _, taskGroup := range tfs.LatestTaskGroupVersions(retval.TaskGroups) {
+		if strings.EqualFold(taskGroup.Name, name) || taskGroup.ID == name {
+			return taskGroup, nil
+		}
+	}
+
+	return tfs.TaskGroup{}, fmt.Errorf("Sorry -- I couldn't find the task group '%s'", name)
+}
+
+// requireTaskGroupArg makes sure a task group name was passed
+func requireTaskGroupArg(cmd *cobra.Command, args []string) error {
+	if len(args) < 1 {
+		return errors.New("Requires a task group name")
+	}
+	return nil
+}
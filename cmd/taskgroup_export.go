@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+var (
+	taskgroupExportFile string
+	taskgroupExportAll  bool
+)
+
+// taskgroupExportCmd represents the taskgroup export command
+var taskgroupExportCmd = &cobra.Command{
+	Use:   "export \"<name>\" [\"<name>\"...]",
+	Short: "Export task groups with their version history",
+	Long: `Exports one or more task groups (or all of them, with --all) as JSON.  Every version
+of each task group is exported, oldest first.  Any task groups they use as nested
+steps are exported too, so the file can be imported into another collection with
+'tfsutil taskgroup import'.
+
+Example:
+tfsutil taskgroup export "Deploy web app" --file taskgroups.json
+
+`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 && !taskgroupExportAll {
+			return errors.New("Requires a task group name (or --all)")
+		}
+		return nil
+	},
+	Run: taskgroupexport,
+}
+
+func taskgroupexport(cmd *cobra.Command, args []string) {
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	retval, err := client.GetListOfTaskGroups(viper.GetString("collection"), viper.GetString("project"))
+	if err != nil {
+		fatalln("[ERROR] Task group list \n", err)
+	}
+
+	latest := tfs.LatestTaskGroupVersions(retval.TaskGroups)
+	byID := map[string]tfs.TaskGroup{}
+	for _, taskGroup := range latest {
+		byID[taskGroup.ID] = taskGroup
+	}
+
+	//	Figure out which task groups were asked for
+	pending := []string{}
+	if taskgroupExportAll {
+		for _, taskGroup := range latest {
+			pending = append(pending, taskGroup.ID)
+		}
+	}
+	for _, name := range args {
+		found := false
+		for _, taskGroup := range latest {
+			if strings.EqualFold(taskGroup.Name, name) || taskGroup.ID == name {
+				pending = append(pending, taskGroup.ID)
+				found = true
+			}
+		}
+		if !found {
+			fatalf("[ERROR] Sorry -- I couldn't find the task group '%s'", name)
+		}
+	}
+
+	//	Export each task group (and any nested task groups it uses)
+	export := tfs.TaskGroupExport{
+		TaskGroups: []tfs.TaskGroupHistory{},
+		References: map[string]string{},
+	}
+	exported := map[string]bool{}
+
+	for len(pending) > 0 {
+		id := pending[0]
+		pending = pending[1:]
+		if exported[id] {
+			continue
+		}
+		exported[id] = true
+
+		versions, err := client.GetTaskGroupVersions(viper.GetString("collection"), viper.GetString("project"), id)
+		if err != nil {
+			fatalf("[ERROR] Getting the versions of '%s' - \n %s", byID[id].Name, err)
+		}
+		tfs.SortTaskGroupVersions(versions.TaskGroups)
+
+		history := tfs.TaskGroupHistory{
+			Name:     byID[id].Name,
+			Versions: versions.TaskGroups,
+		}
+
+		for _, nestedID := range history.NestedTaskGroupIDs() {
+			nested, ok := byID[nestedID]
+			if !ok {
+				log.Printf("[WARN] '%s' uses a task group (%s) that no longer exists", history.Name, nestedID)
+				continue
+			}
+			export.References[nestedID] = nested.Name
+			pending = append(pending, nestedID)
+		}
+
+		export.TaskGroups = append(export.TaskGroups, history)
+	}
+
+	formatted, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		fatalln("[ERROR] Formatting task groups \n", err)
+	}
+
+	//	If we don't have a file, just write it out
+	if taskgroupExportFile == "" {
+		fmt.Println(string(formatted))
+		return
+	}
+
+	if err := ioutil.WriteFile(taskgroupExportFile, formatted, 0644); err != nil {
+		fatalf("[ERROR] Writing %s - \n %s", taskgroupExportFile, err)
+	}
+
+	fmt.Printf("\nExported %v task groups to %s\n", len(export.TaskGroups), taskgroupExportFile)
+}
+
+func init() {
+	taskgroupCmd.AddCommand(taskgroupExportCmd)
+
+	taskgroupExportCmd.Flags().StringVarP(&taskgroupExportFile, "file", "f", "", "File to export to (default is to write to the console)")
+	taskgroupExportCmd.Flags().BoolVar(&taskgroupExportAll, "all", false, "Export every task group in the project")
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+var taskgroupImportDryRun bool
+
+// taskgroupImportCmd represents the taskgroup import command
+var taskgroupImportCmd = &cobra.Command{
+	Use:   "import <file.json>",
+	Short: "Import task groups exported with 'taskgroup export'",
+	Long: `Imports the task groups in a file created by 'tfsutil taskgroup export' into the project.
+Nested task groups are imported before the task groups that use them, and nested
+references are remapped by name to the task groups in this project.  The versions of
+each task group are created in order, oldest first, so the version history is kept.
+
+Task groups that already exist in the project (by name) are left alone, but are still
+used when remapping nested references.
+
+Example:
+tfsutil taskgroup import taskgroups.json --collection OtherCollection --project Web
+
+`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("Requires an exported task group file")
+		}
+		return nil
+	},
+	Run: taskgroupimport,
+}
+
+func taskgroupimport(cmd *cobra.Command, args []string) {
+
+	data, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		fatalf("[ERROR] Reading %s - \n %s", args[0], err)
+	}
+
+	export := tfs.TaskGroupExport{}
+	if err := json.Unmarshal(data, &export); err != nil {
+		fatalf("[ERROR] Decoding %s - \n %s", args[0], err)
+	}
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	collection := viper.GetString("collection")
+	project := viper.GetString("project")
+
+	//	Find the task groups that already exist in the project
+	retval, err := client.GetListOfTaskGroups(collection, project)
+	if err != nil {
+		fatalln("[ERROR] Task group list \n", err)
+	}
+
+	existing := map[string]string{}
+	for _, taskGroup := range tfs.LatestTaskGroupVersions(retval.TaskGroups) {
+		existing[strings.ToLower(taskGroup.Name)] = taskGroup.ID
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Printf("\nCollection: %v", collection)
+	fmt.Printf("\nProject: %v\n", project)
+	fmt.Printf("\nTask groups to import: %v\n=========================\n", len(export.TaskGroups))
+	fmt.Fprintln(w, "Name\tVersions\tResult")
+
+	failed := 0
+	for _, history := range export.ImportOrder() {
+		result, err := importTaskGroupHistory(client, collection, project, history, export.References, existing)
+		if err != nil {
+			failed++
+			result = "error: " + err.Error()
+		}
+		fmt.Fprintf(w, "%s\t%v\t%s\n", history.Name, len(history.Versions), result)
+	}
+	w.Flush()
+
+	if failed > 0 {
+		exit(1)
+	}
+}
+
+// importTaskGroupHistory creates a task group and each of its versions, in order.  The existing map (lowercase name to id)
+// is updated with the new task group, so task groups imported later can reference it
+func importTaskGroupHistory(client tfs.Client, collection, project string, history tfs.TaskGroupHistory, references, existing map[string]string) (string, error) {
+
+	if _, ok := existing[strings.ToLower(history.Name)]; ok {
+		return "already exists", nil
+	}
+
+	if len(history.Versions) == 0 {
+		return "", errors.New("the export doesn't have any versions")
+	}
+
+	//	Map the old nested task group ids to the ids in this project
+	newIDs := map[string]string{}
+	for oldID, name := range references {
+		if newID, ok := existing[strings.ToLower(name)]; ok {
+			newIDs[oldID] = newID
+		}
+	}
+
+	versions := make([]tfs.TaskGroup, len(history.Versions))
+	copy(versions, history.Versions)
+	tfs.SortTaskGroupVersions(versions)
+
+	for i := range versions {
+		if missing := tfs.RemapTaskGroupReferences(&versions[i], newIDs); len(missing) > 0 {
+			names := []string{}
+			for _, id := range missing {
+				names = append(names, fmt.Sprintf("'%s'", references[id]))
+			}
+			return "", fmt.Errorf("version %s uses task groups that aren't in this project: %s", versions[i].Version, strings.Join(names, ", "))
+		}
+	}
+
+	if taskgroupImportDryRun {
+		existing[strings.ToLower(history.Name)] = "(dry run)"
+		return "would create", nil
+	}
+
+	//	Create the first version, then add each version after it
+	var created tfs.TaskGroup
+	for i, version := range versions {
+		version.Revision = created.Revision
+		version.ParentDefinitionID = ""
+
+		var err error
+		if i == 0 {
+			version.ID = ""
+			created, err = client.CreateTaskGroup(collection, project, version)
+		} else {
+			version.ID = created.ID
+			created, err = client.UpdateTaskGroup(collection, project, version)
+		}
+
+		if err != nil {
+			if i > 0 {
+				return "", fmt.Errorf("created up to version %s, but version %s failed: %s", versions[i-1].Version, version.Version, err)
+			}
+			return "", err
+		}
+	}
+
+	existing[strings.ToLower(history.Name)] = created.ID
+	return fmt.Sprintf("created (id %s)", created.ID), nil
+}
+
+func init() {
+	taskgroupCmd.AddCommand(taskgroupImportCmd)
+
+	taskgroupImportCmd.Flags().BoolVar(&taskgroupImportDryRun, "dry-run", false, "Show what would be imported, without changing anything")
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// taskgroupListCmd represents the taskgroup list command
+var taskgroupListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List task groups",
+	Long:  `Lists task groups (the latest version of each), along with their category and number of steps`,
+	Run:   taskgrouplist,
+}
+
+func taskgrouplist(cmd *cobra.Command, args []string) {
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	//	Get the list of task groups.  Report any errors
+	retval, err := client.GetListOfTaskGroups(viper.GetString("collection"), viper.GetString("project"))
+	if err != nil {
+		fatalln("[ERROR] Task group list \n", err)
+	}
+
+	//	Sort the task groups
+	taskGroups := tfs.LatestTaskGroupVersions(retval.TaskGroups)
+	sort.Slice(taskGroups, func(i, j int) bool {
+		return strings.ToLower(taskGroups[i].Name) < strings.ToLower(taskGroups[j].Name)
+	})
+
+	//	Begin the report:
+	fmt.Printf("\nCollection: %v", viper.GetString("collection"))
+	fmt.Printf("\nProject: %v\n", viper.GetString("project"))
+	fmt.Printf("\nTask groups found: %v\n=====================\n", len(taskGroups))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Name\tVersion\tCategory\tSteps\tModified")
+	for _, taskGroup := range taskGroups {
+		version := taskGroup.Version.String()
+		if taskGroup.Version.IsTest {
+			version += " (draft)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%v\t%s\n", taskGroup.Name, version, taskGroup.Category, len(taskGroup.Tasks), taskGroup.ModifiedOn.Local().Format("2006-01-02"))
+	}
+	w.Flush()
+}
+
+func init() {
+	taskgroupCmd.AddCommand(taskgroupListCmd)
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// taskgroupShowCmd represents the taskgroup show command
+var taskgroupShowCmd = &cobra.Command{
+	Use:   "show \"<name>\"",
+	Short: "Show a task group",
+	Long: `Shows a task group: its version history, its parameters and its steps.
+Steps that run a nested task group are marked.
+
+Example:
+tfsutil taskgroup show "Deploy web app"
+
+`,
+	Args: requireTaskGroupArg,
+	Run:  taskgroupshow,
+}
+
+func taskgroupshow(cmd *cobra.Command, args []string) {
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	taskGroup, err := findTaskGroup(client, viper.GetString("collection"), viper.GetString("project"), args[0])
+	if err != nil {
+		fatalln("[ERROR] ", err)
+	}
+
+	versions, err := client.GetTaskGroupVersions(viper.GetString("collection"), viper.GetString("project"), taskGroup.ID)
+	if err != nil {
+		fatalln("[ERROR] Task group versions \n", err)
+	}
+	tfs.SortTaskGroupVersions(versions.TaskGroups)
+
+	//	Get the names of any nested task groups
+	names := map[string]string{}
+	all, err := client.GetListOfTaskGroups(viper.GetString("collection"), viper.GetString("project"))
+	if err != nil {
+		fatalln("[ERROR] Task group list \n", err)
+	}
+	for _, item := range all.TaskGroups {
+		names[item.ID] = item.Name
+	}
+
+	fmt.Printf("\nTask group: %s (id %s)\n", taskGroup.Name, taskGroup.ID)
+	fmt.Printf("Category: %s\n", taskGroup.Category)
+	if taskGroup.Description != "" {
+		fmt.Printf("Description: %s\n", taskGroup.Description)
+	}
+
+	fmt.Printf("\nVersions: %v\n==================\n", len(versions.TaskGroups))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Version\tRevision\tDraft\tModified\tComment")
+	for _, version := range versions.TaskGroups {
+		fmt.Fprintf(w, "%s\t%v\t%v\t%s\t%s\n", version.Version, version.Revision, version.Version.IsTest, version.ModifiedOn.Local().Format("2006-01-02 15:04"), version.Comment)
+	}
+	w.Flush()
+
+	fmt.Printf("\nParameters: %v\n==================\n", len(taskGroup.Inputs))
+	for _, input := range taskGroup.Inputs {
+		fmt.Printf("%v = %v\n", input["name"], input["defaultValue"])
+	}
+
+	fmt.Printf("\nSteps: %v\n==================\n", len(taskGroup.Tasks))
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Step\tTask\tVersion\tEnabled")
+	for _, step := range taskGroup.Tasks {
+		task := step.Task.ID
+		if step.IsTaskGroup() {
+			task = fmt.Sprintf("task group '%s'", names[step.Task.ID])
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%v\n", step.DisplayName, task, step.Task.VersionSpec, step.Enabled)
+	}
+	w.Flush()
+}
+
+func init() {
+	taskgroupCmd.AddCommand(taskgroupShowCmd)
+}
@@ -0,0 +1,376 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+var (
+	teamBootstrapTeam   string
+	teamBootstrapDryRun bool
+)
+
+// teamTemplate is the structure of a team bootstrap template
+type teamTemplate struct {
+	Team        string   `yaml:"team"`
+	Description string   `yaml:"description"`
+	Projects    []string `yaml:"projects"`
+
+	// Areas are the area paths the team owns.  The default area is the first one, unless DefaultArea is set
+	Areas       []string `yaml:"areas"`
+	DefaultArea string   `yaml:"defaultArea"`
+
+	// Iterations are the iteration paths the team works in
+	Iterations []teamTemplateIteration `yaml:"iterations"`
+
+	// Sprints generates a series of iterations of the same length
+	Sprints *teamTemplateSprints `yaml:"sprints"`
+}
+
+// teamTemplateIteration is a single iteration in a team bootstrap template
+type teamTemplateIteration struct {
+	Path   string `yaml:"path"`
+	Start  string `yaml:"start"`
+	Finish string `yaml:"finish"`
+}
+
+// teamTemplateSprints generates a series of sprints, like 'Sprint 1' to 'Sprint 6'
+type teamTemplateSprints struct {
+	Parent string `yaml:"parent"`
+	Prefix string `yaml:"prefix"`
+	First  int    `yaml:"first"`
+	Count  int    `yaml:"count"`
+	Start  string `yaml:"start"`
+	Days   int    `yaml:"days"`
+}
+
+// iterations generates the sprint iterations
+func (s teamTemplateSprints) iterations() ([]teamTemplateIteration, error) {
+	retval := []teamTemplateIteration{}
+
+	start, err := time.Parse("2006-01-02", s.Start)
+	if err != nil {
+		return retval, fmt.Errorf("Invalid sprint start date '%s' (expected YYYY-MM-DD)", s.Start)
+	}
+
+	if s.Days < 1 {
+		return retval, errors.New("Sprints need a length in days")
+	}
+
+	prefix := s.Prefix
+	if prefix == "" {
+		prefix = "Sprint"
+	}
+
+	first := s.First
+	if first == 0 {
+		first = 1
+	}
+
+	for i := 0; i < s.Count; i++ {
+		finish := start.AddDate(0, 0, s.Days-1)
+		retval = append(retval, teamTemplateIteration{
+			Path:   strings.Trim(s.Parent+"/"+fmt.Sprintf("%s %v", prefix, first+i), "/"),
+			Start:  start.Format("2006-01-02"),
+			Finish: finish.Format("2006-01-02"),
+		})
+		start = start.AddDate(0, 0, s.Days)
+	}
+
+	return retval, nil
+}
+
+// teamBootstrapCmd represents the team bootstrap command
+var teamBootstrapCmd = &cobra.Command{
+	Use:   "bootstrap <template.yml>",
+	Short: "Set up a team, its area paths and its iterations from a template",
+	Long: `Creates a team, its area paths and its iteration paths from a YAML template, then
+selects those areas and iterations for the team.  Anything that already exists is
+left alone, so the same template can be run again safely.
+
+The template can use {{.Team}} and {{.Project}} (in quoted values), so it can be
+reused for other teams (with --team) and in several projects (with 'projects:'):
+
+team: Squad Unicorn
+description: The unicorn squad
+projects: [Web, Mobile]
+areas:
+- "{{.Team}}"
+- "{{.Team}}/Frontend"
+iterations:
+- path: Release 3
+  start: 2019-01-07
+  finish: 2019-03-29
+sprints:
+  parent: Release 3
+  prefix: Sprint
+  count: 6
+  start: 2019-01-07
+  days: 14
+
+Example:
+tfsutil team bootstrap squad.yml --team "Squad Narwhal" --dry-run
+
+`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("Requires a team template file")
+		}
+		return nil
+	},
+	Run: teambootstrap,
+}
+
+// teamBootstrapResult is the result of a single bootstrap step
+type teamBootstrapResult struct {
+	Project string
+	Step    string
+	Item    string
+	Result  string
+}
+
+func teambootstrap(cmd *cobra.Command, args []string) {
+
+	data, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		fatalf("[ERROR] Reading %s - \n %s", args[0], err)
+	}
+
+	//	Read the template once to find the team and projects
+	first, err := renderTeamTemplate(string(data), teamBootstrapTeam, viper.GetString("project"))
+	if err != nil {
+		fatalf("[ERROR] Reading %s - \n %s", args[0], err)
+	}
+
+	projects := first.Projects
+	if len(projects) == 0 {
+		projects = []string{viper.GetString("project")}
+	}
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	results := []teamBootstrapResult{}
+	failed := 0
+	for _, project := range projects {
+		tmpl, err := renderTeamTemplate(string(data), first.Team, project)
+		if err != nil {
+			fatalf("[ERROR] Reading %s for project %s - \n %s", args[0], project, err)
+		}
+
+		for _, result := range bootstrapTeam(client, viper.GetString("collection"), project, tmpl) {
+			if strings.HasPrefix(result.Result, "error") {
+				failed++
+			}
+			results = append(results, result)
+		}
+	}
+
+	//	Begin the report:
+	fmt.Printf("\nCollection: %v", viper.GetString("collection"))
+	fmt.Printf("\nTeam: %v\n", first.Team)
+	fmt.Printf("\nSteps: %v (%v failed)\n===================\n", len(results), failed)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Project\tStep\tItem\tResult")
+	for _, result := range results {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", result.Project, result.Step, result.Item, result.Result)
+	}
+	w.Flush()
+
+	if failed > 0 {
+		exit(1)
+	}
+}
+
+// renderTeamTemplate fills in the template for a team and project, and reads it
+func renderTeamTemplate(text, team, project string) (teamTemplate, error) {
+	retval := teamTemplate{}
+
+	//	The team name can come from the template itself, so read it first if we need to
+	if team == "" {
+		raw := teamTemplate{}
+		if err := yaml.Unmarshal([]byte(text), &raw); err != nil {
+			return retval, err
+		}
+		team = raw.Team
+	}
+
+	parsed, err := template.New("team").Parse(text)
+	if err != nil {
+		return retval, err
+	}
+
+	rendered := new(bytes.Buffer)
+	if err := parsed.Execute(rendered, map[string]string{"Team": team, "Project": project}); err != nil {
+		return retval, err
+	}
+
+	if err := yaml.Unmarshal(rendered.Bytes(), &retval); err != nil {
+		return retval, err
+	}
+
+	retval.Team = team
+	if retval.Team == "" {
+		return retval, errors.New("The template needs a team name (or use --team)")
+	}
+
+	return retval, nil
+}
+
+// bootstrapTeam creates the team, areas and iterations in a single project, and selects them for the team
+func bootstrapTeam(client tfs.Client, collection, project string, tmpl teamTemplate) []teamBootstrapResult {
+	results := []teamBootstrapResult{}
+	report := func(step, item, result string) {
+		results = append(results, teamBootstrapResult{Project: project, Step: step, Item: item, Result: result})
+	}
+
+	//	The team
+	teams, err := client.GetListOfTeams(collection, project)
+	if err != nil {
+		report("team", tmpl.Team, "error: "+err.Error())
+		return results
+	}
+
+	exists := false
+	for _, team := range teams.Teams {
+		if strings.EqualFold(team.Name, tmpl.Team) {
+			exists = true
+		}
+	}
+
+	switch {
+	case exists:
+		report("team", tmpl.Team, "already exists")
+	case teamBootstrapDryRun:
+		report("team", tmpl.Team, "would create")
+	default:
+		if _, err := client.CreateTeam(collection, project, tfs.Team{Name: tmpl.Team, Description: tmpl.Description}); err != nil {
+			report("team", tmpl.Team, "error: "+err.Error())
+			return results
+		}
+		report("team", tmpl.Team, "created")
+	}
+
+	//	The area paths
+	fieldValues := tfs.TeamFieldValues{}
+	for _, area := range tmpl.Areas {
+		result, _, err := bootstrapClassificationNode(client, collection, project, tfs.StructureGroupAreas, area, nil)
+		if err != nil {
+			report("area", area, "error: "+err.Error())
+			continue
+		}
+		report("area", area, result)
+
+		value := project + "\\" + strings.Join(tfs.SplitClassificationPath(area), "\\")
+		fieldValues.Values = append(fieldValues.Values, tfs.TeamFieldValue{Value: value, IncludeChildren: true})
+	}
+
+	//	The iteration paths
+	iterations := tmpl.Iterations
+	if tmpl.Sprints != nil {
+		sprints, err := tmpl.Sprints.iterations()
+		if err != nil {
+			report("iteration", "sprints", "error: "+err.Error())
+		}
+		iterations = append(iterations, sprints...)
+	}
+
+	selected := []teamTemplateIteration{}
+	selectedIDs := []string{}
+	for _, iteration := range iterations {
+		attributes, err := iterationAttributes(iteration.Start, iteration.Finish)
+		if err != nil {
+			report("iteration", iteration.Path, "error: "+err.Error())
+			continue
+		}
+
+		result, node, err := bootstrapClassificationNode(client, collection, project, tfs.StructureGroupIterations, iteration.Path, attributes)
+		if err != nil {
+			report("iteration", iteration.Path, "error: "+err.Error())
+			continue
+		}
+		report("iteration", iteration.Path, result)
+		selected = append(selected, iteration)
+		selectedIDs = append(selectedIDs, node.Identifier)
+	}
+
+	//	Select the areas and iterations for the team
+	if len(fieldValues.Values) > 0 {
+		fieldValues.DefaultValue = fieldValues.Values[0].Value
+		if tmpl.DefaultArea != "" {
+			fieldValues.DefaultValue = project + "\\" + strings.Join(tfs.SplitClassificationPath(tmpl.DefaultArea), "\\")
+		}
+
+		if teamBootstrapDryRun {
+			report("team areas", fieldValues.DefaultValue, "would select")
+		} else if _, err := client.SetTeamFieldValues(collection, project, tmpl.Team, fieldValues); err != nil {
+			report("team areas", fieldValues.DefaultValue, "error: "+err.Error())
+		} else {
+			report("team areas", fieldValues.DefaultValue, fmt.Sprintf("selected %v areas", len(fieldValues.Values)))
+		}
+	}
+
+	for i, iteration := range selected {
+		switch {
+		case teamBootstrapDryRun:
+			report("team iterations", iteration.Path, "would select")
+		default:
+			if _, err := client.AddTeamIteration(collection, project, tmpl.Team, selectedIDs[i]); err != nil {
+				report("team iterations", iteration.Path, "error: "+err.Error())
+			} else {
+				report("team iterations", iteration.Path, "selected")
+			}
+		}
+	}
+
+	return results
+}
+
+// bootstrapClassificationNode makes sure an area or iteration path exists (unless this is a dry run), and describes what was done
+func bootstrapClassificationNode(client tfs.Client, collection, project, structureGroup, nodePath string, attributes *tfs.ClassificationNodeAttributes) (string, tfs.ClassificationNode, error) {
+
+	if teamBootstrapDryRun {
+		root, err := client.GetClassificationNode(collection, project, structureGroup, "", len(tfs.SplitClassificationPath(nodePath)))
+		if err != nil {
+			return "", tfs.ClassificationNode{}, err
+		}
+
+		if node, ok := root.Find(nodePath); ok {
+			return "already exists", node, nil
+		}
+		return "would create", tfs.ClassificationNode{}, nil
+	}
+
+	node, created, err := ensureClassificationNode(client, collection, project, structureGroup, nodePath, attributes)
+	if err != nil {
+		return "", node, err
+	}
+
+	if created {
+		return "created", node, nil
+	}
+	return "already exists", node, nil
+}
+
+func init() {
+	teamCmd.AddCommand(teamBootstrapCmd)
+
+	teamBootstrapCmd.Flags().StringVar(&teamBootstrapTeam, "team", "", "Team name (default is the team in the template)")
+	teamBootstrapCmd.Flags().BoolVar(&teamBootstrapDryRun, "dry-run", false, "Show what would be created, without changing anything")
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+var teamCreateDescription string
+
+// teamCreateCmd represents the team create command
+var teamCreateCmd = &cobra.Command{
+	Use:   "create \"<name>\"",
+	Short: "Create a team",
+	Long: `Creates a team in the project.  To create a team along with its area and
+iteration paths, see 'tfsutil team bootstrap'.
+
+Example:
+tfsutil team create "Squad Unicorn" --description "The unicorn squad"
+
+`,
+	Args: requireTeamArg,
+	Run:  teamcreate,
+}
+
+func teamcreate(cmd *cobra.Command, args []string) {
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	team, err := client.CreateTeam(viper.GetString("collection"), viper.GetString("project"), tfs.Team{Name: args[0], Description: teamCreateDescription})
+	if err != nil {
+		fatalf("[ERROR] Creating the team %s - \n %s", args[0], err)
+	}
+
+	fmt.Printf("\nCreated team '%s' (id %s)\n", team.Name, team.ID)
+}
+
+func init() {
+	teamCmd.AddCommand(teamCreateCmd)
+
+	teamCreateCmd.Flags().StringVar(&teamCreateDescription, "description", "", "Description")
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// teamListCmd represents the team list command
+var teamListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List teams",
+	Long:  `Lists the teams in the project`,
+	Run:   teamlist,
+}
+
+func teamlist(cmd *cobra.Command, args []string) {
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	//	Get the list of teams.  Report any errors
+	retval, err := client.GetListOfTeams(viper.GetString("collection"), viper.GetString("project"))
+	if err != nil {
+		fatalln("[ERROR] Team list \n", err)
+	}
+
+	//	Sort the teams
+	sort.Slice(retval.Teams, func(i, j int) bool {
+		return strings.ToLower(retval.Teams[i].Name) < strings.ToLower(retval.Teams[j].Name)
+	})
+
+	//	Begin the report:
+	fmt.Printf("\nCollection: %v", viper.GetString("collection"))
+	fmt.Printf("\nProject: %v\n", viper.GetString("project"))
+	fmt.Printf("\nTeams found: %v\n===============\n", retval.Count)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Name\tDescription")
+	for _, team := range retval.Teams {
+		fmt.Fprintf(w, "%s\t%s\n", team.Name, team.Description)
+	}
+	w.Flush()
+}
+
+func init() {
+	teamCmd.AddCommand(teamListCmd)
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// teamMembersCmd represents the team members command
+var teamMembersCmd = &cobra.Command{
+	Use:   "members \"<team>\"",
+	Short: "List the members of a team",
+	Long: `Lists the members of a team, and which of them are team administrators.
+
+Example:
+tfsutil team members "Squad Unicorn"
+
+`,
+	Args: requireTeamArg,
+	Run:  teammembers,
+}
+
+func teammembers(cmd *cobra.Command, args []string) {
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	//	Get the list of members.  Report any errors
+	retval, err := client.GetListOfTeamMembers(viper.GetString("collection"), viper.GetString("project"), args[0])
+	if err != nil {
+		fatalln("[ERROR] Team member list \n", err)
+	}
+
+	//	Sort the members
+	sort.Slice(retval.Members, func(i, j int) bool {
+		return strings.ToLower(retval.Members[i].Identity.DisplayName) < strings.ToLower(retval.Members[j].Identity.DisplayName)
+	})
+
+	//	Begin the report:
+	fmt.Printf("\nCollection: %v", viper.GetString("collection"))
+	fmt.Printf("\nProject: %v", viper.GetString("project"))
+	fmt.Printf("\nTeam: %v\n", args[0])
+	fmt.Printf("\nMembers found: %v\n=================\n", retval.Count)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Name\tUnique name\tAdmin")
+	for _, member := range retval.Members {
+		fmt.Fprintf(w, "%s\t%s\t%v\n", member.Identity.DisplayName, member.Identity.UniqueName, member.IsTeamAdmin)
+	}
+	w.Flush()
+}
+
+func init() {
+	teamCmd.AddCommand(teamMembersCmd)
+}
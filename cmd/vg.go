@@ -3,10 +3,14 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
 )
 
 // vgCmd represents the variable group base command
@@ -23,12 +27,12 @@ var vgCmd = &cobra.Command{
 		//	Verify that we have a tfsurl and a pat
 		if strings.TrimSpace(viper.GetString("tfsurl")) == "" {
 			fmt.Printf("\nThis tool requires a TFS base url to operate.   \n\nPlease specify one on the command line or in the config file 'tfsutil.yml' \nFor help creating a config file, see the command 'tfsutil config create'\n")
-			os.Exit(1)
+			exit(1)
 		}
 
 		if strings.TrimSpace(viper.GetString("pat")) == "" {
 			fmt.Printf("\nThis tool requires a TFS Personal Access Token (pat) for authentication.  \n\nPlease specify a pat on the command line or in the config file 'tfsutil.yml' \nFor help creating a config file, see the command 'tfsutil config create'\n")
-			os.Exit(1)
+			exit(1)
 		}
 
 	},
@@ -37,3 +41,122 @@ var vgCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(vgCmd)
 }
+
+var (
+	vgProjects string
+	vgParallel int
+)
+
+// addProjectsFlags adds the flags for running a vg command in several projects at once
+func addProjectsFlags(command *cobra.Command) {
+	command.Flags().StringVar(&vgProjects, "projects", "", "Run in several projects: a comma separated list of names or globs (like 'Web*'), or 'all'")
+	command.Flags().IntVar(&vgParallel, "parallel", 0, "Most projects to work on at once with --projects (default is 'parallel' from the config file, or 4)")
+	command.RegisterFlagCompletionFunc("projects", completeProjects)
+}
+
+// runInSelectedProjects runs an operation in each project --projects selects (several at once), shows the
+// progress and then a table of the results.  It returns false, without doing anything, if --projects wasn't used
+func runInSelectedProjects(operation func(client tfs.Client, collection, project string) (string, error)) bool {
+	if vgProjects == "" {
+		return false
+	}
+
+	collection := viper.GetString("collection")
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	results := forEachSelectedProject(client, collection, func(project string) (string, error) {
+		return operation(client, collection, project)
+	})
+
+	failed := 0
+	rows := [][]string{}
+	for _, result := range results {
+		status, detail := "ok", result.Result
+		if result.Err != nil {
+			status, detail = "failed", result.Err.Error()
+			failed++
+		}
+		rows = append(rows, []string{result.Project, status, detail, result.Duration.Round(time.Millisecond).String()})
+	}
+
+	//	Begin the report:
+	fmt.Printf("\nCollection: %v", collection)
+	fmt.Printf("\nProjects: %v (%v failed)\n===================\n", len(results), failed)
+	writeRows("table", []string{"Project", "Status", "Result", "Time"}, rows)
+
+	if failed > 0 {
+		exit(1)
+	}
+	return true
+}
+
+// forEachSelectedProject runs an operation in each project --projects selects (several at once, showing the
+// progress), or in the configured project if --projects wasn't used.  The results are in project order
+func forEachSelectedProject(client tfs.Client, collection string, operation func(project string) (string, error)) []tfs.ProjectResult {
+
+	if vgProjects == "" {
+		project := viper.GetString("project")
+		if strings.TrimSpace(project) == "" {
+			fatalln("[ERROR] A project (or --projects) is required")
+		}
+		return client.ForEachProject([]string{project}, tfs.PoolOptions{}, operation)
+	}
+
+	projects, err := client.SelectProjects(collection, vgProjects)
+	if err != nil {
+		fatalln("[ERROR] Selecting projects \n", err)
+	}
+
+	parallel := vgParallel
+	if parallel < 1 {
+		parallel = viper.GetInt("parallel")
+	}
+
+	failed := 0
+	return client.ForEachProject(projects, tfs.PoolOptions{
+		Parallelism: parallel,
+		Progress: func(done, total int, result tfs.ProjectResult) {
+			if result.Err != nil {
+				failed++
+			}
+			fmt.Fprintf(os.Stderr, "[%v/%v, %v failed] %s\n", done, total, failed, result.Project)
+		},
+	}, operation)
+}
+
+// authorizeVariableGroup authorizes pipelines to use a variable group -- either every pipeline in the project,
+// or the given pipelines (build definition ids).  If authorized is false, the pipelines are unauthorized instead
+func authorizeVariableGroup(client tfs.Client, collection, project string, groupID int, allPipelines bool, pipelineIDs []int, authorized bool) (tfs.ResourcePipelinePermissions, error) {
+	permissions := tfs.ResourcePipelinePermissions{}
+
+	if allPipelines {
+		permissions.AllPipelines = &tfs.PipelinePermission{Authorized: authorized}
+	}
+
+	for _, id := range pipelineIDs {
+		permissions.Pipelines = append(permissions.Pipelines, tfs.PipelinePermission{ID: id, Authorized: authorized})
+	}
+
+	return client.SetPipelinePermissions(collection, project, "variablegroup", strconv.Itoa(groupID), permissions)
+}
+
+// findProjectID finds the id of a project by name in the given collection
+func findProjectID(client tfs.Client, collection, project string) (string, error) {
+
+	retval, err := client.GetListOfProjects(collection)
+	if err != nil {
+		return "", fmt.Errorf("Finding project: %s", err)
+	}
+
+	for _, item := range retval.Projects {
+		if strings.EqualFold(item.Name, project) || item.ID == project {
+			return item.ID, nil
+		}
+	}
+
+	return "", fmt.Errorf("Sorry -- I couldn't find the project '%s'", project)
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+var (
+	vgAuthorizeAllPipelines bool
+	vgAuthorizePipelines    []int
+	vgAuthorizeRevoke       bool
+)
+
+// vgAuthorizeCmd represents the vg authorize command
+var vgAuthorizeCmd = &cobra.Command{
+	Use:   "authorize \"<name>\" [--all-pipelines | --pipeline <id>...]",
+	Short: "Authorize pipelines to use a variable group",
+	Long: `Authorizes pipelines to use a variable group -- either every pipeline in the project
+(--all-pipelines) or individual pipelines by build definition id (--pipeline, repeatable).
+Use --revoke to remove the authorization instead, and --projects to authorize the group
+with the same name in several projects at once.
+
+Example:
+tfsutil vg authorize "Special unicorn variables" --pipeline 12 --pipeline 15
+tfsutil vg authorize "Special unicorn variables" --all-pipelines --projects all
+
+`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("Requires a variable group name")
+		}
+		if !vgAuthorizeAllPipelines && len(vgAuthorizePipelines) == 0 {
+			return errors.New("Requires --all-pipelines or at least one --pipeline")
+		}
+		return nil
+	},
+	ValidArgsFunction: completeVariableGroupArg,
+	Run:               vgauthorize,
+}
+
+func vgauthorize(cmd *cobra.Command, args []string) {
+
+	action := "Authorized"
+	if vgAuthorizeRevoke {
+		action = "Removed the authorization for"
+	}
+
+	//	Authorize it in several projects, if we've been asked to
+	if runInSelectedProjects(func(client tfs.Client, collection, project string) (string, error) {
+		group, err := client.FindVariableGroup(collection, project, args[0])
+		if err != nil {
+			return "", err
+		}
+
+		permissions, err := authorizeVariableGroup(client, collection, project, group.ID, vgAuthorizeAllPipelines, vgAuthorizePipelines, !vgAuthorizeRevoke)
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("%s (all pipelines authorized: %v)", action, permissions.AllPipelinesAuthorized()), nil
+	}) {
+		return
+	}
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	//	Find the group.  Report any errors
+	group, err := client.FindVariableGroup(viper.GetString("collection"), viper.GetString("project"), args[0])
+	if err != nil {
+		fatalln("[ERROR] ", err)
+	}
+
+	permissions, err := authorizeVariableGroup(client, viper.GetString("collection"), viper.GetString("project"), group.ID, vgAuthorizeAllPipelines, vgAuthorizePipelines, !vgAuthorizeRevoke)
+	if err != nil {
+		fatalf("[ERROR] Authorizing pipelines for the group %s - \n %s", group.Name, err)
+	}
+
+	if vgAuthorizeAllPipelines {
+		fmt.Printf("\n%s all pipelines to use '%s'\n", action, group.Name)
+	}
+	if len(vgAuthorizePipelines) > 0 {
+		fmt.Printf("\n%s pipelines %s to use '%s'\n", action, formatIDs(vgAuthorizePipelines), group.Name)
+	}
+
+	fmt.Printf("All pipelines authorized: %v\n", permissions.AllPipelinesAuthorized())
+}
+
+func init() {
+	vgCmd.AddCommand(vgAuthorizeCmd)
+
+	vgAuthorizeCmd.Flags().BoolVar(&vgAuthorizeAllPipelines, "all-pipelines", false, "Authorize every pipeline in the project")
+	vgAuthorizeCmd.Flags().IntSliceVar(&vgAuthorizePipelines, "pipeline", []int{}, "Id of a pipeline (build definition) to authorize")
+	vgAuthorizeCmd.Flags().BoolVar(&vgAuthorizeRevoke, "revoke", false, "Remove the authorization instead of adding it")
+	addProjectsFlags(vgAuthorizeCmd)
+}
@@ -5,13 +5,14 @@ import (
 	"fmt"
 	"log"
 
-	"github.com/rs/xid"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
 	"github.com/danesparza/tfsutil/tfs"
 )
 
+var vgCopyAuthorize bool
+
 // copyCmd represents the copy command
 var copyCmd = &cobra.Command{
 	Use:   "copy \"<name>\"",
@@ -20,8 +21,12 @@ var copyCmd = &cobra.Command{
 	
 NOTE: For variable group names that contain spaces, remember to surround the group name with quotes.  
 
+Use --authorize to authorize all pipelines to use the new group right away, and --projects
+to copy the group in several projects at once.
+
 Example: 
 tfsutil vg copy "Test group name"
+tfsutil vg copy "Test group name" --projects "Web*,Mobile" --authorize
 
 `,
 	Args: func(cmd *cobra.Command, args []string) error {
@@ -30,7 +35,8 @@ tfsutil vg copy "Test group name"
 		}
 		return nil
 	},
-	Run: vgcopy,
+	ValidArgsFunction: completeVariableGroupArg,
+	Run:               vgcopy,
 }
 
 func vgcopy(cmd *cobra.Command, args []string) {
@@ -39,50 +45,68 @@ func vgcopy(cmd *cobra.Command, args []string) {
 	groupName := args[0]
 	log.Printf("[DEBUG] Attempting to copy the group '%s'", groupName)
 
+	//	Copy it in several projects, if we've been asked to
+	if runInSelectedProjects(func(client tfs.Client, collection, project string) (string, error) {
+		group, err := client.FindVariableGroup(collection, project, groupName)
+		if err != nil {
+			return "", err
+		}
+
+		created, err := client.CreateVariableGroup(collection, project, group.Copy(tfs.CopyName(group.Name)))
+		if err != nil {
+			return "", err
+		}
+
+		if vgCopyAuthorize {
+			if _, err := authorizeVariableGroup(client, collection, project, created.ID, true, nil, true); err != nil {
+				return "", fmt.Errorf("Copied to '%s', but authorizing pipelines failed: %s", created.Name, err)
+			}
+		}
+
+		return fmt.Sprintf("Copied to '%s' (%v variables)", created.Name, len(created.Variables)), nil
+	}) {
+		return
+	}
+
 	//	Create a client with our base TFS url
 	client := tfs.Client{
 		TfsURL: viper.GetString("tfsurl"),
 	}
 
-	//	Get the list of Variable groups.  Report any errors
-	vgroups, err := client.GetListOfMatchingVariableGroups(viper.GetString("collection"), viper.GetString("project"), groupName)
+	//	Find the group.  Report any errors
+	group, err := client.FindVariableGroup(viper.GetString("collection"), viper.GetString("project"), groupName)
 	if err != nil {
-		log.Fatalln("[ERROR] Finding existing group \n", err)
-	}
-
-	//	Did we find it?
-	if vgroups.Count < 1 {
-		log.Fatalf("Sorry -- I couldn't find the group '%s'", groupName)
-	}
-
-	if vgroups.Count > 1 && vgroups.VariableGroups[0].Name != groupName {
-		log.Fatalf("Sorry -- Too many groups match '%s' -- please be more specific", groupName)
+		fatalln("[ERROR] ", err)
 	}
 
 	//	If we did, see if it has items:
-	log.Printf("[DEBUG] Copying '%s' (and %v variables)", vgroups.VariableGroups[0].Name, len(vgroups.VariableGroups[0].Variables))
-
-	//	If we can find it, compose a new request and attempt to add it:
-	variableGroupCopy := tfs.VariableGroup{}
-	variableGroupCopy.Description = vgroups.VariableGroups[0].Description
-	variableGroupCopy.Type = vgroups.VariableGroups[0].Type
-	variableGroupCopy.Variables = vgroups.VariableGroups[0].Variables
+	log.Printf("[DEBUG] Copying '%s' (and %v variables)", group.Name, len(group.Variables))
 
-	//	Make the name a bit unique
-	guid := xid.New()
-	variableGroupCopy.Name = fmt.Sprintf("Copy of %s (%s)", vgroups.VariableGroups[0].Name, guid.String())
+	//	If we can find it, compose a new request (with a name that's a bit unique) and attempt to add it:
+	variableGroupCopy := group.Copy(tfs.CopyName(group.Name))
 	log.Printf("[DEBUG] Creating a group with the name: %s", variableGroupCopy.Name)
 
 	//	Create a copy of the group.  Report any errors
-	err = client.CreateVariableGroup(viper.GetString("collection"), viper.GetString("project"), variableGroupCopy)
+	created, err := client.CreateVariableGroup(viper.GetString("collection"), viper.GetString("project"), variableGroupCopy)
 	if err != nil {
-		log.Fatalf("[ERROR] Copying the group %s - \n %s", groupName, err)
+		fatalf("[ERROR] Copying the group %s - \n %s", groupName, err)
 	}
 
 	fmt.Printf("\nCopied \n %s \nto \n %s \n (including %v variables)", groupName, variableGroupCopy.Name, len(variableGroupCopy.Variables))
 
+	//	Authorize pipelines to use the new group, if we've been asked to
+	if vgCopyAuthorize {
+		if _, err := authorizeVariableGroup(client, viper.GetString("collection"), viper.GetString("project"), created.ID, true, nil, true); err != nil {
+			fatalf("[ERROR] Authorizing pipelines for the group %s - \n %s", variableGroupCopy.Name, err)
+		}
+		fmt.Printf("\n\nAuthorized all pipelines to use %s", variableGroupCopy.Name)
+	}
+
 }
 
 func init() {
 	vgCmd.AddCommand(copyCmd)
+
+	copyCmd.Flags().BoolVar(&vgCopyAuthorize, "authorize", false, "Authorize all pipelines to use the new group")
+	addProjectsFlags(copyCmd)
 }
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+var vgExportFile string
+
+// vgExportCmd represents the vg export command
+var vgExportCmd = &cobra.Command{
+	Use:   "export \"<name>\"",
+	Short: "Export a variable group",
+	Long: `Exports a variable group as JSON, so it can be imported with 'tfsutil vg import'.
+TFS never returns secret values, so secret variables are exported without them.
+Key Vault linked groups are exported with their vault and service endpoint name.
+
+Example:
+tfsutil vg export "Special unicorn variables" --file unicorn.json
+
+`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("Requires a variable group name")
+		}
+		return nil
+	},
+	ValidArgsFunction: completeVariableGroupArg,
+	Run:               vgexport,
+}
+
+func vgexport(cmd *cobra.Command, args []string) {
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	//	Find the group.  Report any errors
+	group, err := client.FindVariableGroup(viper.GetString("collection"), viper.GetString("project"), args[0])
+	if err != nil {
+		fatalln("[ERROR] ", err)
+	}
+
+	export := tfs.VariableGroupExport{VariableGroup: group}
+	if group.ProviderData != nil && group.ProviderData.ServiceEndpointID != "" {
+		endpoint, err := findServiceEndpoint(client, viper.GetString("collection"), viper.GetString("project"), group.ProviderData.ServiceEndpointID)
+		if err != nil {
+			log.Printf("[WARN] Unable to find the service endpoint for the Key Vault -- it can only be imported into this project: %s", err)
+		} else {
+			export.ServiceEndpointName = endpoint.Name
+		}
+	}
+
+	formatted, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		fatalln("[ERROR] Formatting variable group \n", err)
+	}
+
+	//	If we don't have a file, just write it out
+	if vgExportFile == "" {
+		fmt.Println(string(formatted))
+		return
+	}
+
+	if err := ioutil.WriteFile(vgExportFile, formatted, 0644); err != nil {
+		fatalf("[ERROR] Writing %s - \n %s", vgExportFile, err)
+	}
+
+	fmt.Printf("\nExported '%s' (%v variables) to %s\n", group.Name, len(group.Variables), vgExportFile)
+}
+
+func init() {
+	vgCmd.AddCommand(vgExportCmd)
+
+	vgExportCmd.Flags().StringVarP(&vgExportFile, "file", "f", "", "File to export to (default is to write to the console)")
+}
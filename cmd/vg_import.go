@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+var (
+	vgImportName      string
+	vgImportAuthorize bool
+)
+
+// vgImportCmd represents the vg import command
+var vgImportCmd = &cobra.Command{
+	Use:   "import <file.json>",
+	Short: "Import a variable group exported with 'vg export'",
+	Long: `Creates a variable group from a file created by 'tfsutil vg export'.
+
+Key Vault linked groups are linked to the service endpoint with the same name in
+this project.  Secret values aren't exported, so set them again after importing
+a Vsts group that has secrets.  Use --projects to import it in several projects at once.
+
+Example:
+tfsutil vg import unicorn.json --project Website --authorize
+tfsutil vg import unicorn.json --projects "Web*"
+
+`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("Requires an exported variable group file")
+		}
+		return nil
+	},
+	Run: vgimport,
+}
+
+func vgimport(cmd *cobra.Command, args []string) {
+
+	data, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		fatalf("[ERROR] Reading %s - \n %s", args[0], err)
+	}
+
+	export := tfs.VariableGroupExport{}
+	if err := json.Unmarshal(data, &export); err != nil {
+		fatalf("[ERROR] Decoding %s - \n %s", args[0], err)
+	}
+
+	//	Import it in several projects, if we've been asked to
+	if runInSelectedProjects(func(client tfs.Client, collection, project string) (string, error) {
+		created, secrets, err := importVariableGroup(client, collection, project, export)
+		if err != nil {
+			return "", err
+		}
+
+		if vgImportAuthorize {
+			if _, err := authorizeVariableGroup(client, collection, project, created.ID, true, nil, true); err != nil {
+				return "", fmt.Errorf("Imported '%s', but authorizing pipelines failed: %s", created.Name, err)
+			}
+		}
+
+		result := fmt.Sprintf("Imported '%s' (id %v)", created.Name, created.ID)
+		if secrets > 0 {
+			result += fmt.Sprintf(" -- set %v secrets again", secrets)
+		}
+		return result, nil
+	}) {
+		return
+	}
+
+	collection := viper.GetString("collection")
+	project := viper.GetString("project")
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	created, secrets, err := importVariableGroup(client, collection, project, export)
+	if err != nil {
+		fatalln("[ERROR] ", err)
+	}
+
+	fmt.Printf("\nImported '%s' (id %v, %v variables, %s)\n", created.Name, created.ID, len(created.Variables), created.Provider())
+	if secrets > 0 {
+		fmt.Printf("NOTE: %v secret variables were imported without values -- please set them again\n", secrets)
+	}
+
+	//	Authorize pipelines to use the new group, if we've been asked to
+	if vgImportAuthorize {
+		if _, err := authorizeVariableGroup(client, collection, project, created.ID, true, nil, true); err != nil {
+			fatalf("[ERROR] Authorizing pipelines for the group %s - \n %s", created.Name, err)
+		}
+		fmt.Printf("Authorized all pipelines to use %s\n", created.Name)
+	}
+}
+
+func init() {
+	vgCmd.AddCommand(vgImportCmd)
+
+	vgImportCmd.Flags().StringVar(&vgImportName, "name", "", "Name for the new group (default is the exported name)")
+	vgImportCmd.Flags().BoolVar(&vgImportAuthorize, "authorize", false, "Authorize all pipelines to use the new group")
+	addProjectsFlags(vgImportCmd)
+}
+
+// importVariableGroup creates a variable group from an export.  It returns the new group, and how many secrets
+// were imported without their values
+func importVariableGroup(client tfs.Client, collection, project string, export tfs.VariableGroupExport) (tfs.VariableGroup, int, error) {
+
+	newGroup := tfs.VariableGroup{
+		Name:         export.Name,
+		Description:  export.Description,
+		Type:         export.Type,
+		ProviderData: export.ProviderData,
+		Variables:    export.Variables,
+	}
+	if vgImportName != "" {
+		newGroup.Name = vgImportName
+	}
+
+	//	Link a Key Vault group to the service endpoint in this project
+	if newGroup.ProviderData != nil && export.ServiceEndpointName != "" {
+		endpoint, err := findServiceEndpoint(client, collection, project, export.ServiceEndpointName)
+		if err != nil {
+			return tfs.VariableGroup{}, 0, fmt.Errorf("The Key Vault for this group uses the service endpoint '%s' -- please create it in this project first \n %s", export.ServiceEndpointName, err)
+		}
+
+		providerData := *newGroup.ProviderData
+		providerData.ServiceEndpointID = endpoint.ID
+		newGroup.ProviderData = &providerData
+	}
+
+	secrets := 0
+	for _, variable := range newGroup.Variables {
+		if variable.IsSecret && !newGroup.IsKeyVault() {
+			secrets++
+		}
+	}
+
+	created, err := client.CreateVariableGroup(collection, project, newGroup)
+	if err != nil {
+		return created, secrets, fmt.Errorf("Importing the group %s - \n %s", newGroup.Name, err)
+	}
+
+	return created, secrets, nil
+}
@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"log"
 	"sort"
 
 	"github.com/spf13/viper"
@@ -29,7 +28,7 @@ func vglist(cmd *cobra.Command, args []string) {
 	//	Get the list of Variable groups.  Report any errors
 	retval, err := client.GetListOfVariableGroups(viper.GetString("collection"), viper.GetString("project"))
 	if err != nil {
-		log.Fatalln("[ERROR] Variable group list \n", err)
+		fatalln("[ERROR] Variable group list \n", err)
 	}
 
 	// Closure(s) that orders the VariableGroup structure.
@@ -45,9 +44,9 @@ func vglist(cmd *cobra.Command, args []string) {
 	fmt.Printf("\nProject: %v\n", viper.GetString("project"))
 	fmt.Printf("\nVariable groups found: %v\n==========================\n", retval.Count)
 
-	//	List all the groups (and their variable counts):
+	//	List all the groups (and their variable counts and providers):
 	for _, group := range retval.VariableGroups {
-		fmt.Printf("%s (%v variables)\n", group.Name, len(group.Variables))
+		fmt.Printf("%s (%v variables, %s)\n", group.Name, len(group.Variables), group.Provider())
 	}
 
 }
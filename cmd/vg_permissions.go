@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// vgPermissionsCmd represents the vg permissions command
+var vgPermissionsCmd = &cobra.Command{
+	Use:   "permissions \"<name>\"",
+	Short: "Show who can use and manage a variable group",
+	Long: `Shows the pipelines that are authorized to use a variable group, and the security
+roles (Reader, User, Administrator) that users and groups have on it.
+
+Example:
+tfsutil vg permissions "Special unicorn variables"
+
+`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("Requires a variable group name")
+		}
+		return nil
+	},
+	ValidArgsFunction: completeVariableGroupArg,
+	Run:               vgpermissions,
+}
+
+func vgpermissions(cmd *cobra.Command, args []string) {
+
+	collection := viper.GetString("collection")
+	project := viper.GetString("project")
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	//	Find the group.  Report any errors
+	group, err := client.FindVariableGroup(collection, project, args[0])
+	if err != nil {
+		fatalln("[ERROR] ", err)
+	}
+
+	permissions, err := client.GetPipelinePermissions(collection, project, "variablegroup", strconv.Itoa(group.ID))
+	if err != nil {
+		fatalln("[ERROR] Getting the pipeline permissions \n", err)
+	}
+
+	//	The role assignments are keyed by project id and group id
+	projectID, err := findProjectID(client, collection, project)
+	if err != nil {
+		fatalln("[ERROR] ", err)
+	}
+
+	roles, err := client.GetListOfRoleAssignments(collection, "distributedtask.variablegroup", fmt.Sprintf("%s$%v", projectID, group.ID))
+	if err != nil {
+		fatalln("[ERROR] Getting the role assignments \n", err)
+	}
+
+	//	Get the pipeline names
+	names := map[int]string{}
+	defs, err := client.GetListOfBuildDefinitions(collection, project, "")
+	if err != nil {
+		log.Printf("[WARN] Unable to get the pipeline names: %s", err)
+	}
+	for _, def := range defs.Definitions {
+		names[def.ID] = def.Name
+	}
+
+	//	Begin the report:
+	fmt.Printf("\nCollection: %v", collection)
+	fmt.Printf("\nProject: %v\n", project)
+	fmt.Printf("\nVariable group: %s (id %v)\n", group.Name, group.ID)
+	fmt.Printf("All pipelines authorized: %v\n", permissions.AllPipelinesAuthorized())
+
+	ids := permissions.AuthorizedPipelineIDs()
+	fmt.Printf("\nAuthorized pipelines: %v\n========================\n", len(ids))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Id\tPipeline")
+	for _, id := range ids {
+		fmt.Fprintf(w, "%v\t%s\n", id, names[id])
+	}
+	w.Flush()
+
+	fmt.Printf("\nRole assignments: %v\n====================\n", roles.Count)
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Identity\tRole\tAccess")
+	for _, role := range roles.RoleAssignments {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", role.Identity.DisplayName, role.Role.DisplayName, role.AccessDisplayName)
+	}
+	w.Flush()
+}
+
+func init() {
+	vgCmd.AddCommand(vgPermissionsCmd)
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+var (
+	vgSearchValues     bool
+	vgSearchIgnoreCase bool
+	vgSearchOutput     string
+)
+
+// vgSearchCmd represents the vg search command
+var vgSearchCmd = &cobra.Command{
+	Use:   "search <pattern>",
+	Short: "Find variables by name (or value) in variable groups",
+	Long: `Searches the variables in every variable group for names that match a regular
+expression, and lists the group, project and variable of each match.  Use --values to
+search the values too (secret values are never returned by TFS, so they aren't searched).
+
+The project's groups are searched, unless --projects is used to search several projects
+(a comma separated list of names and globs, or 'all' for every project in the collection).
+
+Example:
+tfsutil vg search DB_CONNECTION
+tfsutil vg search "^db_" -i --projects all
+tfsutil vg search "sql\d+\.example\.com" --values --projects "Web*" -o csv
+
+`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return errors.New("Requires a pattern to search for")
+		}
+		return nil
+	},
+	Run: vgsearch,
+}
+
+func vgsearch(cmd *cobra.Command, args []string) {
+
+	expression := args[0]
+	if vgSearchIgnoreCase {
+		expression = "(?i)" + expression
+	}
+
+	pattern, err := regexp.Compile(expression)
+	if err != nil {
+		fatalf("[ERROR] Invalid pattern '%s' - \n %s", args[0], err)
+	}
+
+	collection := viper.GetString("collection")
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	//	Search the project, or the projects we were asked to
+	var mu sync.Mutex
+	matches := map[string][]tfs.VariableMatch{}
+	results := forEachSelectedProject(client, collection, func(project string) (string, error) {
+		groups, err := client.GetListOfVariableGroups(collection, project)
+		if err != nil {
+			return "", err
+		}
+
+		found := tfs.SearchVariableGroups(project, groups.VariableGroups, pattern, vgSearchValues)
+
+		mu.Lock()
+		matches[project] = found
+		mu.Unlock()
+
+		return fmt.Sprintf("%v matches", len(found)), nil
+	})
+
+	//	Report the projects that couldn't be searched, but keep going
+	failed := 0
+	projects := []string{}
+	for _, result := range results {
+		projects = append(projects, result.Project)
+		if result.Err != nil {
+			failed++
+			log.Printf("[WARN] Unable to search %s: %s", result.Project, result.Err)
+		}
+	}
+
+	//	Put the matches in project order
+	sort.Strings(projects)
+	rows := [][]string{}
+	for _, project := range projects {
+		for _, match := range matches[project] {
+			value := match.Value
+			if match.IsSecret {
+				value = "(secret)"
+			}
+			rows = append(rows, []string{match.Group, match.Project, match.Variable, value})
+		}
+	}
+
+	//	Begin the report:
+	if vgSearchOutput == "" || vgSearchOutput == "table" {
+		fmt.Printf("\nCollection: %v", collection)
+		fmt.Printf("\nProjects: %v\n", strings.Join(projects, ", "))
+		fmt.Printf("\nVariables found: %v\n==========================\n", len(rows))
+	}
+
+	if err := writeRows(vgSearchOutput, []string{"Group", "Project", "Variable", "Value"}, rows); err != nil {
+		fatalln("[ERROR] Writing the matches \n", err)
+	}
+
+	if failed > 0 {
+		exit(1)
+	}
+}
+
+func init() {
+	vgCmd.AddCommand(vgSearchCmd)
+
+	vgSearchCmd.Flags().BoolVar(&vgSearchValues, "values", false, "Search the (non-secret) values too")
+	vgSearchCmd.Flags().BoolVarP(&vgSearchIgnoreCase, "ignore-case", "i", false, "Ignore case when matching")
+	vgSearchCmd.Flags().StringVarP(&vgSearchOutput, "output", "o", "table", "Output format: table/json/csv/tsv")
+	addProjectsFlags(vgSearchCmd)
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+var (
+	vgSetSecret bool
+	vgSetRemove []string
+)
+
+// vgSetCmd represents the vg set command
+var vgSetCmd = &cobra.Command{
+	Use:   "set \"<name>\" [<variable>=<value>...]",
+	Short: "Set (or remove) variables in a variable group",
+	Long: `Adds or changes variables in a variable group, and removes the variables named with
+--remove.  Use --secret to make the variables secret.  The other variables (including
+the values of secrets) are left alone.
+
+Use --projects to make the same change to the group with that name in several projects
+at once.
+
+Example:
+tfsutil vg set "Unicorn variables" Horn=sparkly Mane=rainbow
+tfsutil vg set "Unicorn variables" DbPassword=hunter2 --secret --projects "Web*,Mobile"
+tfsutil vg set "Unicorn variables" --remove OldSetting --projects all
+
+`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("Requires a variable group name")
+		}
+		if len(args) == 1 && len(vgSetRemove) == 0 {
+			return errors.New("Requires at least one variable=value, or --remove")
+		}
+		for _, item := range args[1:] {
+			if parts := strings.SplitN(item, "=", 2); len(parts) != 2 || parts[0] == "" {
+				return fmt.Errorf("Invalid variable '%s' (expected name=value)", item)
+			}
+		}
+		return nil
+	},
+	ValidArgsFunction: completeVariableGroupArg,
+	Run:               vgset,
+}
+
+func vgset(cmd *cobra.Command, args []string) {
+
+	groupName := args[0]
+	variables := map[string]tfs.Variable{}
+	for _, item := range args[1:] {
+		parts := strings.SplitN(item, "=", 2)
+		variables[parts[0]] = tfs.Variable{Value: parts[1], IsSecret: vgSetSecret}
+	}
+
+	//	Change it in several projects, if we've been asked to
+	if runInSelectedProjects(func(client tfs.Client, collection, project string) (string, error) {
+		return setVariables(client, collection, project, groupName, variables, vgSetRemove)
+	}) {
+		return
+	}
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	result, err := setVariables(client, viper.GetString("collection"), viper.GetString("project"), groupName, variables, vgSetRemove)
+	if err != nil {
+		fatalf("[ERROR] Setting variables in the group %s - \n %s", groupName, err)
+	}
+
+	fmt.Printf("\n%s in '%s'\n", result, groupName)
+}
+
+// setVariables adds (or changes) and removes variables in a variable group, and describes what changed
+func setVariables(client tfs.Client, collection, project, groupName string, variables map[string]tfs.Variable, remove []string) (string, error) {
+
+	group, err := client.FindVariableGroup(collection, project, groupName)
+	if err != nil {
+		return "", err
+	}
+	if group.IsKeyVault() {
+		return "", fmt.Errorf("The variables in '%s' come from a Key Vault, and can't be set here", group.Name)
+	}
+
+	if group.Variables == nil {
+		group.Variables = map[string]tfs.Variable{}
+	}
+
+	added, changed, removed := 0, 0, 0
+	for name, variable := range variables {
+		if _, found := group.Variables[name]; found {
+			changed++
+		} else {
+			added++
+		}
+		group.Variables[name] = variable
+	}
+	for _, name := range remove {
+		if _, found := group.Variables[name]; found {
+			delete(group.Variables, name)
+			removed++
+		}
+	}
+
+	if _, err := client.UpdateVariableGroup(collection, project, group); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Added %v, changed %v and removed %v variables", added, changed, removed), nil
+}
+
+func init() {
+	vgCmd.AddCommand(vgSetCmd)
+
+	vgSetCmd.Flags().BoolVar(&vgSetSecret, "secret", false, "Make the variables secret")
+	vgSetCmd.Flags().StringArrayVar(&vgSetRemove, "remove", []string{}, "Name of a variable to remove.  Can be used more than once")
+	addProjectsFlags(vgSetCmd)
+}
@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+var vgUsageAllProjects bool
+
+// vgUsageCmd represents the vg usage command
+var vgUsageCmd = &cobra.Command{
+	Use:   "usage \"<name>\"",
+	Short: "Find the pipelines that use a variable group",
+	Long: `Scans the build definitions and release definitions in the project (or in every
+project in the collection, with --all-projects) and reports each pipeline that links
+the variable group, along with the variables from the group that the pipeline references.
+
+YAML builds link groups by name, so they're only matched by name in the group's own
+project (a group in another project with the same name is a different group).  The
+report lists the pipelines that were matched by name only.
+
+Example:
+tfsutil vg usage "Special unicorn variables" --all-projects
+
+`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("Requires a variable group name")
+		}
+		return nil
+	},
+	ValidArgsFunction: completeVariableGroupArg,
+	Run:               vgusage,
+}
+
+// vgReference is a single pipeline that links a variable group
+type vgReference struct {
+	Project   string
+	Kind      string
+	Name      string
+	Scope     string
+	Variables []string
+
+	// ByName is true if the pipeline only links the group by name (in its YAML)
+	ByName bool
+}
+
+func vgusage(cmd *cobra.Command, args []string) {
+
+	groupName := args[0]
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL:     viper.GetString("tfsurl"),
+		ReleaseURL: viper.GetString("releaseurl"),
+	}
+
+	//	Find the group.  Report any errors
+	group, err := client.FindVariableGroup(viper.GetString("collection"), viper.GetString("project"), groupName)
+	if err != nil {
+		fatalln("[ERROR] ", err)
+	}
+
+	names := []string{}
+	for name := range group.Variables {
+		names = append(names, name)
+	}
+
+	//	Figure out which projects to scan
+	projects := []string{viper.GetString("project")}
+	if vgUsageAllProjects {
+		retval, err := client.GetListOfProjects(viper.GetString("collection"))
+		if err != nil {
+			fatalln("[ERROR] Project list \n", err)
+		}
+
+		projects = []string{}
+		for _, project := range retval.Projects {
+			projects = append(projects, project.Name)
+		}
+	}
+
+	references := []vgReference{}
+	for _, project := range projects {
+		log.Printf("[DEBUG] Scanning project '%s' for references to group %v", project, group.ID)
+
+		//	Groups are only linked by name in their own project
+		byName := strings.EqualFold(project, viper.GetString("project"))
+		buildRefs, err := findBuildReferences(client, project, group, names, byName)
+		if err != nil {
+			log.Printf("[WARN] Unable to scan the build definitions in '%s': %s", project, err)
+		}
+		references = append(references, buildRefs...)
+
+		releaseRefs, err := findReleaseReferences(client, project, group, names)
+		if err != nil {
+			log.Printf("[WARN] Unable to scan the release definitions in '%s': %s", project, err)
+		}
+		references = append(references, releaseRefs...)
+	}
+
+	//	Begin the report:
+	fmt.Printf("\nCollection: %v", viper.GetString("collection"))
+	fmt.Printf("\nVariable group: %s (id %v, %v variables)\n", group.Name, group.ID, len(group.Variables))
+	fmt.Printf("\nPipelines found: %v\n===================\n", len(references))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Project\tType\tPipeline\tScope\tVariables referenced")
+	for _, ref := range references {
+		variables := strings.Join(ref.Variables, ", ")
+		if variables == "" {
+			variables = "(none)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", ref.Project, ref.Kind, ref.Name, ref.Scope, variables)
+	}
+	w.Flush()
+
+	//	Point out the pipelines we only matched by name
+	byName := []string{}
+	for _, ref := range references {
+		if ref.ByName {
+			byName = append(byName, fmt.Sprintf("%s/%s", ref.Project, ref.Name))
+		}
+	}
+	if len(byName) > 0 {
+		fmt.Printf("\nMatched by name only (in project '%s'): %s\n", viper.GetString("project"), strings.Join(byName, ", "))
+	}
+}
+
+// findBuildReferences finds the build definitions in a project that link the group (by id, or by name in their YAML
+// if byName is set)
+func findBuildReferences(client tfs.Client, project string, group tfs.VariableGroup, names []string, byName bool) ([]vgReference, error) {
+	retval := []vgReference{}
+
+	defs, err := client.GetListOfBuildDefinitions(viper.GetString("collection"), project, "")
+	if err != nil {
+		return retval, err
+	}
+
+	for _, ref := range defs.Definitions {
+		raw, err := client.ExportBuildDefinition(viper.GetString("collection"), project, ref.ID)
+		if err != nil {
+			log.Printf("[WARN] Unable to get the build definition '%s': %s", ref.Name, err)
+			continue
+		}
+
+		def := tfs.BuildDefinition{}
+		if err := json.Unmarshal(raw, &def); err != nil {
+			log.Printf("[WARN] Unable to decode the build definition '%s': %s", ref.Name, err)
+			continue
+		}
+
+		linked := false
+		for _, vg := range def.VariableGroups {
+			if vg.ID == group.ID {
+				linked = true
+			}
+		}
+		linkedByName := false
+
+		//	The definition itself is where designer builds reference variables
+		text := string(raw)
+		scope := "definition"
+
+		//	YAML builds reference groups (and their variables) in the YAML file
+		if def.IsYaml() && def.Repository.Type == "TfsGit" {
+			item, err := client.GetGitItem(viper.GetString("collection"), project, def.Repository.ID, def.Process.YamlFilename, def.Repository.DefaultBranch)
+			if err != nil {
+				log.Printf("[WARN] Unable to get the YAML for '%s': %s", def.Name, err)
+			} else {
+				text = item.Content
+				scope = def.Process.YamlFilename
+				if byName && tfs.ReferencesVariableGroupByName(item.Content, group.Name) {
+					linkedByName = !linked
+					linked = true
+				}
+			}
+		}
+
+		if linked {
+			retval = append(retval, vgReference{
+				Project:   project,
+				Kind:      "build",
+				Name:      def.Name,
+				Scope:     scope,
+				Variables: tfs.FindVariableReferences(text, names),
+				ByName:    linkedByName,
+			})
+		}
+	}
+
+	return retval, nil
+}
+
+// findReleaseReferences finds the release definitions (or their environments) in a project that link the group
+func findReleaseReferences(client tfs.Client, project string, group tfs.VariableGroup, names []string) ([]vgReference, error) {
+	retval := []vgReference{}
+
+	defs, err := client.GetListOfReleaseDefinitions(viper.GetString("collection"), project, "")
+	if err != nil {
+		return retval, err
+	}
+
+	for _, def := range defs.Definitions {
+		scopes := []string{}
+		if containsID(def.VariableGroups, group.ID) {
+			scopes = append(scopes, "release")
+		}
+		for _, env := range def.Environments {
+			if containsID(env.VariableGroups, group.ID) {
+				scopes = append(scopes, "environment "+env.Name)
+			}
+		}
+
+		if len(scopes) == 0 {
+			continue
+		}
+
+		//	The complete definition includes the task inputs that reference variables
+		variables := []string{}
+		raw, err := client.ExportReleaseDefinition(viper.GetString("collection"), project, def.ID)
+		if err != nil {
+			log.Printf("[WARN] Unable to get the release definition '%s': %s", def.Name, err)
+		} else {
+			variables = tfs.FindVariableReferences(string(raw), names)
+		}
+
+		retval = append(retval, vgReference{
+			Project:   project,
+			Kind:      "release",
+			Name:      def.Name,
+			Scope:     strings.Join(scopes, ", "),
+			Variables: variables,
+		})
+	}
+
+	return retval, nil
+}
+
+// containsID returns true if the list of ids contains the given id
+func containsID(ids []int, id int) bool {
+	for _, item := range ids {
+		if item == id {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	vgCmd.AddCommand(vgUsageCmd)
+
+	vgUsageCmd.Flags().BoolVar(&vgUsageAllProjects, "all-projects", false, "Scan every project in the collection")
+}
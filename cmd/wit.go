@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+var witOutput string
+
+// witCmd represents the work item base command
+var witCmd = &cobra.Command{
+	Use:   "wit",
+	Short: "Work item helpers",
+	Long:  `Operations to help with work items.  You can run WIQL and saved queries, and show work items`,
+	Run: func(cmd *cobra.Command, args []string) {
+		//	This command on it's own should just show help
+		cmd.Help()
+	},
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+
+		//	Verify that we have a tfsurl and a pat
+		if strings.TrimSpace(viper.GetString("tfsurl")) == "" {
+			fmt.Printf("\nThis tool requires a TFS base url to operate.   \n\nPlease specify one on the command line or in the config file 'tfsutil.yml' \nFor help creating a config file, see the command 'tfsutil config create'\n")
+			exit(1)
+		}
+
+		if strings.TrimSpace(viper.GetString("pat")) == "" {
+			fmt.Printf("\nThis tool requires a TFS Personal Access Token (pat) for authentication.  \n\nPlease specify a pat on the command line or in the config file 'tfsutil.yml' \nFor help creating a config file, see the command 'tfsutil config create'\n")
+			exit(1)
+		}
+
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(witCmd)
+
+	witCmd.PersistentFlags().StringVarP(&witOutput, "output", "o", "table", "Output format: table/json/csv/tsv")
+}
+
+// defaultWitFields are the fields shown when a query doesn't select any
+var defaultWitFields = []string{"System.Id", "System.WorkItemType", "System.Title", "System.State", "System.AssignedTo"}
+
+// reportQueryResults fetches the fields for the work items in a query result and writes them out
+func reportQueryResults(client tfs.Client, result tfs.WiqlResult, fields []string) {
+
+	//	Use the columns the query selected, unless we were given fields
+	if len(fields) == 0 {
+		fields = result.Fields()
+	}
+	if len(fields) == 0 {
+		fields = defaultWitFields
+	}
+
+	rows := [][]string{}
+	ids := result.IDs()
+	if len(ids) > 0 {
+		items, err := client.GetWorkItems(viper.GetString("collection"), viper.GetString("project"), ids, fields)
+		if err != nil {
+			fatalln("[ERROR] Getting work items \n", err)
+		}
+
+		for _, item := range items.WorkItems {
+			row := []string{}
+			for _, field := range fields {
+				row = append(row, item.Field(field))
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	//	Only decorate the table format -- the others are meant for scripts
+	headers := fields
+	if witOutput == "" || witOutput == "table" {
+		headers = []string{}
+		for _, field := range fields {
+			headers = append(headers, strings.TrimPrefix(field, "System."))
+		}
+		fmt.Printf("\nWork items found: %v\n====================\n", len(rows))
+	}
+
+	if err := writeRows(witOutput, headers, rows); err != nil {
+		fatalln("[ERROR] ", err)
+	}
+}
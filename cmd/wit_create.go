@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+var (
+	witCreateType        string
+	witCreateTitle       string
+	witCreateDescription string
+	witCreateAssignedTo  string
+	witCreateArea        string
+	witCreateIteration   string
+	witCreateParent      int
+	witCreateFields      []string
+	witDryRun            bool
+)
+
+// witFieldAliases maps friendly column (or flag) names to field reference names
+var witFieldAliases = map[string]string{
+	"title":               "System.Title",
+	"description":         "System.Description",
+	"state":               "System.State",
+	"reason":              "System.Reason",
+	"assigned to":         "System.AssignedTo",
+	"assignedto":          "System.AssignedTo",
+	"area":                "System.AreaPath",
+	"area path":           "System.AreaPath",
+	"iteration":           "System.IterationPath",
+	"iteration path":      "System.IterationPath",
+	"tags":                "System.Tags",
+	"priority":            "Microsoft.VSTS.Common.Priority",
+	"acceptance criteria": "Microsoft.VSTS.Common.AcceptanceCriteria",
+	"story points":        "Microsoft.VSTS.Scheduling.StoryPoints",
+	"effort":              "Microsoft.VSTS.Scheduling.Effort",
+	"original estimate":   "Microsoft.VSTS.Scheduling.OriginalEstimate",
+	"remaining work":      "Microsoft.VSTS.Scheduling.RemainingWork",
+	"completed work":      "Microsoft.VSTS.Scheduling.CompletedWork",
+	"activity":            "Microsoft.VSTS.Common.Activity",
+}
+
+// witCreateCmd represents the wit create command
+var witCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a work item",
+	Long: `Creates a single work item.  Area and iteration paths can be given relative to the project.
+Use --field to set any other field, by reference name or by its friendly name.
+
+Example:
+tfsutil wit create --type Task --title "Write login tests" --iteration "Sprint 12" --parent 1234 --field "Remaining Work=4"
+
+`,
+	Run: witcreate,
+}
+
+func witcreate(cmd *cobra.Command, args []string) {
+
+	if witCreateTitle == "" {
+		fatalln("[ERROR] Requires a title (use --title)")
+	}
+
+	//	Gather the fields from our flags
+	fields := map[string]string{
+		"System.Title": witCreateTitle,
+	}
+	if witCreateDescription != "" {
+		fields["System.Description"] = witCreateDescription
+	}
+	if witCreateAssignedTo != "" {
+		fields["System.AssignedTo"] = witCreateAssignedTo
+	}
+	if witCreateArea != "" {
+		fields["System.AreaPath"] = witCreateArea
+	}
+	if witCreateIteration != "" {
+		fields["System.IterationPath"] = witCreateIteration
+	}
+	for _, field := range witCreateFields {
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) != 2 {
+			fatalf("[ERROR] Expected --field name=value, but got '%s'", field)
+		}
+		fields[resolveWitField(parts[0], nil)] = parts[1]
+	}
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	patch, err := buildWorkItemPatch(client, fields, witCreateParent)
+	if err != nil {
+		fatalln("[ERROR] ", err)
+	}
+
+	if witDryRun {
+		fmt.Printf("\nWould create a %s with:\n", witCreateType)
+		printPatch(patch)
+		return
+	}
+
+	created, err := client.CreateWorkItem(viper.GetString("collection"), viper.GetString("project"), witCreateType, patch)
+	if err != nil {
+		fatalln("[ERROR] Creating work item \n", err)
+	}
+
+	fmt.Printf("\nCreated %s %v: %s\n", witCreateType, created.ID, created.Field("System.Title"))
+}
+
+// resolveWitField gets the reference name for a column or flag name, using the given mapping first and then the
+// known aliases.  Names that already look like reference names (like Custom.Team) are used as they are
+func resolveWitField(name string, mapping map[string]string) string {
+	name = strings.TrimSpace(name)
+
+	for column, field := range mapping {
+		if strings.EqualFold(column, name) {
+			return field
+		}
+	}
+
+	if field, ok := witFieldAliases[strings.ToLower(name)]; ok {
+		return field
+	}
+
+	return name
+}
+
+// buildWorkItemPatch creates the JSON Patch document for a set of fields and an (optional) parent work item.
+// Area and iteration paths that are relative to the project are made absolute
+func buildWorkItemPatch(client tfs.Client, fields map[string]string, parent int) ([]tfs.JSONPatchOperation, error) {
+	project := viper.GetString("project")
+
+	for _, field := range []string{"System.AreaPath", "System.IterationPath"} {
+		if value, ok := fields[field]; ok && value != "" {
+			fields[field] = absoluteClassificationPath(project, value)
+		}
+	}
+
+	patch := tfs.FieldPatch(fields)
+
+	if parent > 0 {
+		parentURL, err := client.GetWorkItemURL(viper.GetString("collection"), parent)
+		if err != nil {
+			return patch, fmt.Errorf("Unable to format the parent url: %s", err)
+		}
+		patch = append(patch, tfs.ParentLinkPatch(parentURL))
+	}
+
+	return patch, nil
+}
+
+// absoluteClassificationPath makes an area or iteration path absolute (starting with the project name)
+func absoluteClassificationPath(project, value string) string {
+	value = strings.Trim(value, "\\")
+	if project == "" || strings.EqualFold(value, project) || strings.HasPrefix(strings.ToLower(value), strings.ToLower(project)+"\\") {
+		return value
+	}
+	return project + "\\" + value
+}
+
+// printPatch prints a JSON Patch document for a dry run
+func printPatch(patch []tfs.JSONPatchOperation) {
+	for _, op := range patch {
+		value, _ := json.Marshal(op.Value)
+		fmt.Printf("  %s %s = %s\n", op.Op, op.Path, value)
+	}
+}
+
+func init() {
+	witCmd.AddCommand(witCreateCmd)
+
+	witCreateCmd.Flags().StringVar(&witCreateType, "type", "Task", "Work item type (like Task, Bug or 'User Story')")
+	witCreateCmd.Flags().StringVar(&witCreateTitle, "title", "", "Title")
+	witCreateCmd.Flags().StringVar(&witCreateDescription, "description", "", "Description")
+	witCreateCmd.Flags().StringVar(&witCreateAssignedTo, "assigned-to", "", "User to assign the work item to")
+	witCreateCmd.Flags().StringVar(&witCreateArea, "area", "", "Area path (relative to the project, or absolute)")
+	witCreateCmd.Flags().StringVar(&witCreateIteration, "iteration", "", "Iteration path (relative to the project, or absolute)")
+	witCreateCmd.Flags().IntVar(&witCreateParent, "parent", 0, "Id of the parent work item")
+	witCreateCmd.Flags().StringArrayVar(&witCreateFields, "field", []string{}, "Any other field, as name=value")
+	witCreateCmd.Flags().BoolVar(&witDryRun, "dry-run", false, "Show what would be created, without creating it")
+}
@@ -0,0 +1,293 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+var (
+	witImportType    string
+	witImportMapping []string
+)
+
+// witImportCmd represents the wit import command
+var witImportCmd = &cobra.Command{
+	Use:   "import <file.csv|file.yml>",
+	Short: "Create or update work items from a file",
+	Long: `Creates (or updates) a work item for each row in a CSV file, or each item in a YAML file.
+
+Columns are matched to fields by reference name (like System.Title) or by friendly name
+(like Title, Assigned To, Area, Iteration or Remaining Work).  Use --map to match other columns.
+A few columns are special:
+
+  ID      If set, the existing work item is updated instead of creating a new one
+  Type    The work item type (default is --type)
+  Parent  The parent work item id -- or #n to use the work item created from row n of the file
+
+A YAML file is a list of items (or a list under 'items:'):
+
+items:
+- Type: User Story
+  Title: Login page
+  Iteration: Sprint 12
+- Title: Write login tests
+  Parent: "#1"
+  Remaining Work: 4
+
+Example:
+tfsutil wit import sprint12.csv --map "Estimate=Microsoft.VSTS.Scheduling.RemainingWork" --dry-run
+
+`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("Requires a CSV or YAML file")
+		}
+		return nil
+	},
+	Run: witimport,
+}
+
+func witimport(cmd *cobra.Command, args []string) {
+
+	rows, err := readWorkItemRows(args[0])
+	if err != nil {
+		fatalf("[ERROR] Reading %s - \n %s", args[0], err)
+	}
+
+	//	Gather the column mapping from our flags
+	mapping := map[string]string{}
+	for _, item := range witImportMapping {
+		parts := strings.SplitN(item, "=", 2)
+		if len(parts) != 2 {
+			fatalf("[ERROR] Expected --map column=field, but got '%s'", item)
+		}
+		mapping[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	//	Keep track of the work items we create, so later rows can use them as parents
+	createdIDs := map[int]int{}
+	report := [][]string{}
+	failed := 0
+
+	for i, row := range rows {
+		rowNumber := i + 1
+		action, id, title, result := importWorkItemRow(client, rowNumber, row, mapping, createdIDs)
+		if strings.HasPrefix(result, "error") {
+			failed++
+		}
+
+		idText := ""
+		if id > 0 {
+			idText = strconv.Itoa(id)
+		}
+		report = append(report, []string{strconv.Itoa(rowNumber), action, idText, title, result})
+	}
+
+	if witOutput == "" || witOutput == "table" {
+		fmt.Printf("\nRows imported: %v (%v failed)\n=========================\n", len(rows), failed)
+	}
+
+	if err := writeRows(witOutput, []string{"row", "action", "id", "title", "result"}, report); err != nil {
+		fatalln("[ERROR] ", err)
+	}
+
+	if failed > 0 {
+		exit(1)
+	}
+}
+
+// importWorkItemRow creates or updates the work item for a single row.  It returns the action taken,
+// the work item id, the title and the result
+func importWorkItemRow(client tfs.Client, rowNumber int, row map[string]string, mapping map[string]string, createdIDs map[int]int) (string, int, string, string) {
+
+	fields := map[string]string{}
+	workItemType := witImportType
+	id, parent := 0, 0
+	parentText := ""
+
+	for column, value := range row {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		field := resolveWitField(column, mapping)
+		switch strings.ToLower(field) {
+		case "id", "system.id":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return "skip", 0, row["Title"], fmt.Sprintf("error: invalid id '%s'", value)
+			}
+			id = n
+		case "type", "work item type", "system.workitemtype":
+			workItemType = value
+		case "parent":
+			parentText = value
+		default:
+			fields[field] = value
+		}
+	}
+
+	title := fields["System.Title"]
+
+	//	Figure out the parent: either an existing work item id or a row earlier in this file
+	if parentText != "" {
+		if strings.HasPrefix(parentText, "#") {
+			parentRow, err := strconv.Atoi(strings.TrimPrefix(parentText, "#"))
+			if err != nil || parentRow >= rowNumber {
+				return "skip", id, title, fmt.Sprintf("error: parent '%s' must be an earlier row", parentText)
+			}
+
+			parent = createdIDs[parentRow]
+			if parent == 0 && !witDryRun {
+				return "skip", id, title, fmt.Sprintf("error: parent row %v wasn't created", parentRow)
+			}
+		} else {
+			n, err := strconv.Atoi(parentText)
+			if err != nil {
+				return "skip", id, title, fmt.Sprintf("error: invalid parent '%s'", parentText)
+			}
+			parent = n
+		}
+	}
+
+	action := "create"
+	if id > 0 {
+		action = "update"
+	}
+
+	if id == 0 && title == "" {
+		return "skip", 0, "", "error: a title is required to create a work item"
+	}
+
+	patch, err := buildWorkItemPatch(client, fields, parent)
+	if err != nil {
+		return "skip", id, title, "error: " + err.Error()
+	}
+
+	if witDryRun {
+		if witOutput == "" || witOutput == "table" {
+			fmt.Printf("\nRow %v: would %s %s %s\n", rowNumber, action, workItemType, title)
+			printPatch(patch)
+			if parentText != "" && parent == 0 {
+				fmt.Printf("  (linked to the work item created from row %s)\n", strings.TrimPrefix(parentText, "#"))
+			}
+		}
+		return action, id, title, "dry run"
+	}
+
+	var item tfs.WorkItem
+	if id > 0 {
+		item, err = client.UpdateWorkItem(viper.GetString("collection"), viper.GetString("project"), id, patch)
+	} else {
+		item, err = client.CreateWorkItem(viper.GetString("collection"), viper.GetString("project"), workItemType, patch)
+	}
+
+	if err != nil {
+		return action, id, title, "error: " + err.Error()
+	}
+
+	createdIDs[rowNumber] = item.ID
+	return action, item.ID, item.Field("System.Title"), "ok"
+}
+
+// readWorkItemRows reads the rows of a CSV or YAML file
+func readWorkItemRows(file string) ([]map[string]string, error) {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".csv":
+		return readCSVRows(file)
+	case ".yml", ".yaml":
+		return readYAMLRows(file)
+	}
+
+	return nil, fmt.Errorf("Expected a .csv, .yml or .yaml file")
+}
+
+// readCSVRows reads a CSV file with a header row
+func readCSVRows(file string) ([]map[string]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) < 1 {
+		return nil, errors.New("The file doesn't have a header row")
+	}
+
+	headers := records[0]
+	retval := []map[string]string{}
+	for _, record := range records[1:] {
+		row := map[string]string{}
+		for i, header := range headers {
+			if i < len(record) {
+				row[strings.TrimSpace(header)] = record[i]
+			}
+		}
+		retval = append(retval, row)
+	}
+
+	return retval, nil
+}
+
+// readYAMLRows reads a YAML file with a list of items (or a list of items under 'items:')
+func readYAMLRows(file string) ([]map[string]string, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &items); err != nil {
+		wrapper := struct {
+			Items []map[string]interface{} `yaml:"items"`
+		}{}
+		if err := yaml.Unmarshal(data, &wrapper); err != nil {
+			return nil, err
+		}
+		items = wrapper.Items
+	}
+
+	retval := []map[string]string{}
+	for _, item := range items {
+		row := map[string]string{}
+		for key, value := range item {
+			if value != nil {
+				row[key] = fmt.Sprint(value)
+			}
+		}
+		retval = append(retval, row)
+	}
+
+	return retval, nil
+}
+
+func init() {
+	witCmd.AddCommand(witImportCmd)
+
+	witImportCmd.Flags().StringVar(&witImportType, "type", "Task", "Work item type for rows without a Type column")
+	witImportCmd.Flags().StringArrayVar(&witImportMapping, "map", []string{}, "Map a column to a field, as column=field")
+	witImportCmd.Flags().BoolVar(&witDryRun, "dry-run", false, "Show what would be created or updated, without changing anything")
+}
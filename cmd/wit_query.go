@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+var (
+	witFields []string
+	witTop    int
+)
+
+// witQueryCmd represents the wit query command
+var witQueryCmd = &cobra.Command{
+	Use:   "query \"<WIQL>\"",
+	Short: "Run a WIQL query",
+	Long: `Runs a WIQL (work item query language) query and lists the work items it returns,
+with the fields the query selects (or the fields given with --fields).
+
+Use --output json, csv or tsv to use the results from scripts.
+
+Example:
+tfsutil wit query "SELECT [System.Id], [System.Title], [System.State] FROM WorkItems WHERE [System.IterationPath] = @CurrentIteration" -o csv
+
+`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("Requires a WIQL query")
+		}
+		return nil
+	},
+	Run: witquery,
+}
+
+func witquery(cmd *cobra.Command, args []string) {
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	//	Run the query.  Report any errors
+	result, err := client.QueryByWiql(viper.GetString("collection"), viper.GetString("project"), args[0], witTop)
+	if err != nil {
+		fatalln("[ERROR] Running query \n", err)
+	}
+
+	reportQueryResults(client, result, witFields)
+}
+
+func init() {
+	witCmd.AddCommand(witQueryCmd)
+
+	witQueryCmd.Flags().StringSliceVar(&witFields, "fields", []string{}, "Fields to show (default is the fields the query selects)")
+	witQueryCmd.Flags().IntVar(&witTop, "top", 0, "Maximum number of work items to get (default is no limit)")
+}
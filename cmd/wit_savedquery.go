@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"errors"
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// witSavedQueryCmd represents the wit saved-query command
+var witSavedQueryCmd = &cobra.Command{
+	Use:   "saved-query \"<path or id>\"",
+	Short: "Run a saved query",
+	Long: `Runs a shared (or personal) work item query and lists the work items it returns.
+
+Example:
+tfsutil wit saved-query "Shared Queries/Current sprint" -o json
+
+`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("Requires a query path or id")
+		}
+		return nil
+	},
+	Run: witsavedquery,
+}
+
+func witsavedquery(cmd *cobra.Command, args []string) {
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	//	Find the query
+	query, err := client.GetQuery(viper.GetString("collection"), viper.GetString("project"), args[0])
+	if err != nil {
+		fatalln("[ERROR] Finding query \n", err)
+	}
+
+	if query.IsFolder {
+		fatalf("Sorry -- '%s' is a query folder, not a query", query.Path)
+	}
+
+	log.Printf("[DEBUG] Running the query '%s': %s", query.Path, query.Wiql)
+
+	//	Run the query.  Report any errors
+	result, err := client.QueryByID(viper.GetString("collection"), viper.GetString("project"), query.ID)
+	if err != nil {
+		fatalln("[ERROR] Running query \n", err)
+	}
+
+	reportQueryResults(client, result, witFields)
+}
+
+func init() {
+	witCmd.AddCommand(witSavedQueryCmd)
+
+	witSavedQueryCmd.Flags().StringSliceVar(&witFields, "fields", []string{}, "Fields to show (default is the fields the query selects)")
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// witShowCmd represents the wit show command
+var witShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Show a work item",
+	Long:  `Shows a work item, with all of its fields and links`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("Requires a work item id")
+		}
+		if _, err := strconv.Atoi(args[0]); err != nil {
+			return fmt.Errorf("Expected a work item id, but got '%s'", args[0])
+		}
+		return nil
+	},
+	Run: witshow,
+}
+
+func witshow(cmd *cobra.Command, args []string) {
+
+	id, _ := strconv.Atoi(args[0])
+
+	//	Create a client with our base TFS url
+	client := tfs.Client{
+		TfsURL: viper.GetString("tfsurl"),
+	}
+
+	item, err := client.GetWorkItem(viper.GetString("collection"), viper.GetString("project"), id)
+	if err != nil {
+		fatalln("[ERROR] Getting work item \n", err)
+	}
+
+	fields := []string{}
+	for field := range item.Fields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	rows := [][]string{}
+	for _, field := range fields {
+		rows = append(rows, []string{field, item.Field(field)})
+	}
+
+	if witOutput == "" || witOutput == "table" {
+		fmt.Printf("\nWork item %v: %s (revision %v)\n===================\n", item.ID, item.Field("System.Title"), item.Rev)
+	}
+
+	if err := writeRows(witOutput, []string{"field", "value"}, rows); err != nil {
+		fatalln("[ERROR] ", err)
+	}
+
+	if (witOutput == "" || witOutput == "table") && len(item.Relations) > 0 {
+		fmt.Printf("\nLinks: %v\n===================\n", len(item.Relations))
+		for _, relation := range item.Relations {
+			fmt.Printf("%s %s\n", relation.Rel, relation.URL)
+		}
+	}
+}
+
+func init() {
+	witCmd.AddCommand(witShowCmd)
+}
@@ -0,0 +1,233 @@
+// Package mcp serves tfsutil operations over the Model Context Protocol: JSON-RPC 2.0 messages, one per
+// line, on stdin and stdout.  Editor assistants use it to query TFS.
+//
+// The read-only tools are always available.  Tools that change anything are only listed (and can only be
+// called) if they're in the server's allowlist
+package mcp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// ProtocolVersion is the Model Context Protocol version the server speaks
+const ProtocolVersion = "2024-11-05"
+
+// JSON-RPC error codes
+const (
+	ParseError     = -32700
+	InvalidRequest = -32600
+	MethodNotFound = -32601
+	InvalidParams  = -32602
+	InternalError  = -32603
+)
+
+// Server is a Model Context Protocol server
+type Server struct {
+	// Client is used to talk to TFS
+	Client tfs.Client
+
+	// Collection and Project are used when a tool call doesn't name them
+	Collection string
+	Project    string
+
+	// Allow is the list of mutating tools that can be used (like copy_variable_group)
+	Allow []string
+
+	// Version is reported to the client (default is 'dev')
+	Version string
+}
+
+// Request is a JSON-RPC request (or a notification, if it doesn't have an id)
+type Request struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+}
+
+// Response is a JSON-RPC response
+type Response struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id"`
+	Result  interface{}      `json:"result,omitempty"`
+	Error   *Error           `json:"error,omitempty"`
+}
+
+// Error is a JSON-RPC error
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Tool describes a tool the server offers
+type Tool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+
+	// Mutating tools change something in TFS, and have to be allowed
+	Mutating bool `json:"-"`
+
+	call func(s *Server, args toolArguments) (interface{}, error)
+}
+
+// ToolResult is the result of a tool call.  Tool failures are reported here (with IsError), not as
+// JSON-RPC errors, so the assistant can see them
+type ToolResult struct {
+	Content []Content `json:"content"`
+	IsError bool      `json:"isError,omitempty"`
+}
+
+// Content is a piece of a tool result
+type Content struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// callParams are the parameters of a tools/call request
+type callParams struct {
+	Name      string        `json:"name"`
+	Arguments toolArguments `json:"arguments"`
+}
+
+// Serve reads requests from in and writes responses to out, until in is closed
+func (s *Server) Serve(in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	encoder := json.NewEncoder(out)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if response := s.Handle([]byte(line)); response != nil {
+			if err := encoder.Encode(response); err != nil {
+				return err
+			}
+		}
+	}
+
+	return scanner.Err()
+}
+
+// Handle handles a single message, and returns the response to send (nil for notifications)
+func (s *Server) Handle(message []byte) *Response {
+	request := Request{}
+	if err := json.Unmarshal(message, &request); err != nil {
+		return errorResponse(nil, ParseError, fmt.Sprintf("Unable to parse the request: %s", err))
+	}
+
+	if request.JSONRPC != "2.0" || request.Method == "" {
+		return errorResponse(request.ID, InvalidRequest, "Requests need 'jsonrpc': '2.0' and a method")
+	}
+
+	//	Notifications (like notifications/initialized) don't get a response
+	if request.ID == nil {
+		return nil
+	}
+
+	switch request.Method {
+	case "initialize":
+		version := s.Version
+		if version == "" {
+			version = "dev"
+		}
+		return resultResponse(request.ID, map[string]interface{}{
+			"protocolVersion": ProtocolVersion,
+			"capabilities":    map[string]interface{}{"tools": map[string]interface{}{}},
+			"serverInfo":      map[string]string{"name": "tfsutil", "version": version},
+		})
+
+	case "ping":
+		return resultResponse(request.ID, map[string]interface{}{})
+
+	case "tools/list":
+		return resultResponse(request.ID, map[string]interface{}{"tools": s.Tools()})
+
+	case "tools/call":
+		params := callParams{}
+		if err := json.Unmarshal(request.Params, &params); err != nil {
+			return errorResponse(request.ID, InvalidParams, fmt.Sprintf("Invalid tool call: %s", err))
+		}
+
+		tool, found := s.findTool(params.Name)
+		if !found {
+			return errorResponse(request.ID, InvalidParams, fmt.Sprintf("Unknown tool '%s'", params.Name))
+		}
+
+		return resultResponse(request.ID, s.callTool(tool, params.Arguments))
+	}
+
+	return errorResponse(request.ID, MethodNotFound, fmt.Sprintf("Unknown method '%s'", request.Method))
+}
+
+// Tools lists the tools that can be used: every read-only tool, and the allowed mutating tools
+func (s *Server) Tools() []Tool {
+	retval := []Tool{}
+	for _, tool := range tools {
+		if !tool.Mutating || s.allowed(tool.Name) {
+			retval = append(retval, tool)
+		}
+	}
+
+	sort.Slice(retval, func(i, j int) bool { return retval[i].Name < retval[j].Name })
+	return retval
+}
+
+// findTool finds a tool that can be used by name
+func (s *Server) findTool(name string) (Tool, bool) {
+	for _, tool := range s.Tools() {
+		if tool.Name == name {
+			return tool, true
+		}
+	}
+	return Tool{}, false
+}
+
+// allowed returns true if a mutating tool is in the allowlist
+func (s *Server) allowed(name string) bool {
+	for _, allowed := range s.Allow {
+		if strings.EqualFold(strings.TrimSpace(allowed), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// callTool calls a tool, and formats its result (or error)
+func (s *Server) callTool(tool Tool, args toolArguments) ToolResult {
+	if args == nil {
+		args = toolArguments{}
+	}
+
+	result, err := tool.call(s, args)
+	if err != nil {
+		return ToolResult{Content: []Content{{Type: "text", Text: err.Error()}}, IsError: true}
+	}
+
+	formatted, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return ToolResult{Content: []Content{{Type: "text", Text: err.Error()}}, IsError: true}
+	}
+
+	return ToolResult{Content: []Content{{Type: "text", Text: string(formatted)}}}
+}
+
+// resultResponse creates a successful response
+func resultResponse(id *json.RawMessage, result interface{}) *Response {
+	return &Response{JSONRPC: "2.0", ID: id, Result: result}
+}
+
+// errorResponse creates an error response
+func errorResponse(id *json.RawMessage, code int, message string) *Response {
+	return &Response{JSONRPC: "2.0", ID: id, Error: &Error{Code: code, Message: message}}
+}
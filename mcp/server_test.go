@@ -0,0 +1,173 @@
+package mcp_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/danesparza/tfsutil/mcp"
+	"github.com/danesparza/tfsutil/tfs/tfstest"
+)
+
+// newTestMCP starts a fake TFS server with a project and a group, and creates an MCP server that uses it
+func newTestMCP(allow ...string) (*tfstest.Server, *mcp.Server) {
+	server := tfstest.NewServerWithGroup()
+
+	return server, &mcp.Server{Client: server.Client(), Collection: "DefaultCollection", Project: "Website", Allow: allow}
+}
+
+// serve pipes the given lines into the server, and returns the responses
+func serve(t *testing.T, server *mcp.Server, lines ...string) []mcp.Response {
+	out := bytes.Buffer{}
+	if err := server.Serve(strings.NewReader(strings.Join(lines, "\n")), &out); err != nil {
+		t.Fatalf("Serve failed: %s", err)
+	}
+
+	retval := []mcp.Response{}
+	decoder := json.NewDecoder(&out)
+	for decoder.More() {
+		response := mcp.Response{}
+		if err := decoder.Decode(&response); err != nil {
+			t.Fatalf("Unable to decode a response: %s", err)
+		}
+		retval = append(retval, response)
+	}
+	return retval
+}
+
+// toolText gets the text of a tool call result
+func toolText(t *testing.T, response mcp.Response) (string, bool) {
+	data, _ := json.Marshal(response.Result)
+	result := mcp.ToolResult{}
+	if err := json.Unmarshal(data, &result); err != nil || len(result.Content) != 1 {
+		t.Fatalf("Expected a tool result but got %s (error %+v)", data, response.Error)
+	}
+	return result.Content[0].Text, result.IsError
+}
+
+// Only the read-only tools should be offered when no others are allowed
+func TestServe_InitializeAndListTools_ReadOnlyToolsOnly(t *testing.T) {
+
+	//	Arrange
+	tfsServer, server := newTestMCP()
+	defer tfsServer.Close()
+
+	//	Act
+	responses := serve(t, server,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+	)
+
+	//	Assert
+	if len(responses) != 2 {
+		t.Fatalf("Expected 2 responses (none for the notification) but got %+v", responses)
+	}
+
+	data, _ := json.Marshal(responses[1].Result)
+	for _, name := range []string{"list_projects", "list_variable_groups", "show_variable_group", "diff_variable_groups"} {
+		if !strings.Contains(string(data), `"`+name+`"`) {
+			t.Errorf("Expected the %s tool but got %s", name, data)
+		}
+	}
+
+	if strings.Contains(string(data), "copy_variable_group") || strings.Contains(string(data), "set_variable") {
+		t.Errorf("Expected no mutating tools without an allowlist, but got %s", data)
+	}
+}
+
+// Showing a group should return its variables
+func TestServe_ShowVariableGroup_ReturnsVariables(t *testing.T) {
+
+	//	Arrange
+	tfsServer, server := newTestMCP()
+	defer tfsServer.Close()
+
+	//	Act
+	responses := serve(t, server, `{"jsonrpc":"2.0","id":"show","method":"tools/call","params":{"name":"show_variable_group","arguments":{"name":"Unicorn variables"}}}`)
+
+	//	Assert
+	text, isError := toolText(t, responses[0])
+	if isError || !strings.Contains(text, "sparkly") {
+		t.Errorf("Expected the group's variables but got %s", text)
+	}
+
+	if string(*responses[0].ID) != `"show"` {
+		t.Errorf("Expected the request id to be returned, but got %s", *responses[0].ID)
+	}
+}
+
+// A tool that changes something shouldn't run unless it's allowed
+func TestServe_MutatingToolNotAllowed_ReturnsError(t *testing.T) {
+
+	//	Arrange
+	tfsServer, server := newTestMCP()
+	defer tfsServer.Close()
+
+	//	Act
+	responses := serve(t, server, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"copy_variable_group","arguments":{"name":"Unicorn variables"}}}`)
+
+	//	Assert
+	if responses[0].Error == nil || responses[0].Error.Code != mcp.InvalidParams {
+		t.Errorf("Expected an error calling a tool that isn't allowed, but got %+v", responses[0])
+	}
+
+	if len(tfsServer.VariableGroups("DefaultCollection", "Website")) != 1 {
+		t.Errorf("Expected the group not to be copied")
+	}
+}
+
+// An allowed tool should change the group (and keep the rest of it)
+func TestServe_AllowedSetVariable_UpdatesGroup(t *testing.T) {
+
+	//	Arrange
+	tfsServer, server := newTestMCP("set_variable")
+	defer tfsServer.Close()
+
+	//	Act
+	responses := serve(t, server, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"set_variable","arguments":{"name":"Unicorn variables","variable":"Tail","value":"fluffy"}}}`)
+
+	//	Assert
+	text, isError := toolText(t, responses[0])
+	if isError {
+		t.Fatalf("Expected set_variable to work, but got %s", text)
+	}
+
+	groups := tfsServer.VariableGroups("DefaultCollection", "Website")
+	if groups[0].Variables["Tail"].Value != "fluffy" || groups[0].Variables["Horn"].Value != "sparkly" {
+		t.Errorf("Expected Tail to be added (and Horn kept), but got %+v", groups[0].Variables)
+	}
+}
+
+// Bad messages should get JSON-RPC errors (or tool errors), without stopping the server
+func TestServe_BadMessages_ReturnErrors(t *testing.T) {
+
+	//	Arrange
+	tfsServer, server := newTestMCP()
+	defer tfsServer.Close()
+
+	//	Act
+	responses := serve(t, server,
+		`this isn't json`,
+		`{"jsonrpc":"2.0","id":2,"method":"resources/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"show_variable_group","arguments":{}}}`,
+	)
+
+	//	Assert
+	if len(responses) != 3 {
+		t.Fatalf("Expected 3 responses but got %+v", responses)
+	}
+
+	if responses[0].Error == nil || responses[0].Error.Code != mcp.ParseError {
+		t.Errorf("Expected a parse error but got %+v", responses[0])
+	}
+
+	if responses[1].Error == nil || responses[1].Error.Code != mcp.MethodNotFound {
+		t.Errorf("Expected method not found but got %+v", responses[1])
+	}
+
+	if text, isError := toolText(t, responses[2]); !isError || !strings.Contains(text, "'name'") {
+		t.Errorf("Expected a tool error about the missing name, but got %s", text)
+	}
+}
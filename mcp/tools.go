@@ -0,0 +1,270 @@
+package mcp
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// toolArguments are the arguments of a tool call
+type toolArguments map[string]interface{}
+
+// str gets a string argument (or an empty string)
+func (args toolArguments) str(name string) string {
+	value, _ := args[name].(string)
+	return value
+}
+
+// boolean gets a boolean argument (or false)
+func (args toolArguments) boolean(name string) bool {
+	value, _ := args[name].(bool)
+	return value
+}
+
+// collection gets the collection argument, or the server's default
+func (s *Server) collection(args toolArguments) string {
+	if collection := args.str("collection"); collection != "" {
+		return collection
+	}
+	return s.Collection
+}
+
+// project gets the project argument, or the server's default
+func (s *Server) project(args toolArguments) (string, error) {
+	if project := args.str("project"); project != "" {
+		return project, nil
+	}
+	if s.Project == "" {
+		return "", fmt.Errorf("A project is required")
+	}
+	return s.Project, nil
+}
+
+// required gets a string argument that has to be given
+func required(args toolArguments, name string) (string, error) {
+	value := args.str(name)
+	if value == "" {
+		return "", fmt.Errorf("The '%s' argument is required", name)
+	}
+	return value, nil
+}
+
+// schema creates an input schema for an object with string (or boolean) properties
+func schema(required []string, properties map[string]string) map[string]interface{} {
+	props := map[string]interface{}{}
+	for name, description := range properties {
+		kind := "string"
+		if name == "secret" {
+			kind = "boolean"
+		}
+		props[name] = map[string]string{"type": kind, "description": description}
+	}
+
+	retval := map[string]interface{}{"type": "object", "properties": props}
+	if len(required) > 0 {
+		retval["required"] = required
+	}
+	return retval
+}
+
+// Descriptions of the common tool arguments
+const (
+	collectionArgument = "TFS collection (default is the configured collection)"
+	projectArgument    = "TFS project (default is the configured project)"
+	groupArgument      = "Variable group name"
+)
+
+// groupSummary is a variable group in a list
+type groupSummary struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Provider    string `json:"provider"`
+	Variables   int    `json:"variables"`
+}
+
+// tools are all the tools the server knows about
+var tools = []Tool{
+	{
+		Name:        "list_projects",
+		Description: "List the projects in a TFS collection",
+		InputSchema: schema(nil, map[string]string{"collection": collectionArgument}),
+		call: func(s *Server, args toolArguments) (interface{}, error) {
+			projects, err := s.Client.GetListOfProjects(s.collection(args))
+			if err != nil {
+				return nil, err
+			}
+
+			names := []string{}
+			for _, project := range projects.Projects {
+				names = append(names, project.Name)
+			}
+			sort.Strings(names)
+			return names, nil
+		},
+	},
+	{
+		Name:        "list_variable_groups",
+		Description: "List the variable groups in a TFS project, with how many variables each has",
+		InputSchema: schema(nil, map[string]string{"collection": collectionArgument, "project": projectArgument}),
+		call: func(s *Server, args toolArguments) (interface{}, error) {
+			project, err := s.project(args)
+			if err != nil {
+				return nil, err
+			}
+
+			groups, err := s.Client.GetListOfVariableGroups(s.collection(args), project)
+			if err != nil {
+				return nil, err
+			}
+
+			retval := []groupSummary{}
+			for _, group := range groups.VariableGroups {
+				retval = append(retval, groupSummary{ID: group.ID, Name: group.Name, Description: group.Description, Provider: group.Provider(), Variables: len(group.Variables)})
+			}
+			sort.Slice(retval, func(i, j int) bool { return retval[i].Name < retval[j].Name })
+			return retval, nil
+		},
+	},
+	{
+		Name:        "show_variable_group",
+		Description: "Show a variable group and its variables.  Secret values are never returned by TFS",
+		InputSchema: schema([]string{"name"}, map[string]string{"collection": collectionArgument, "project": projectArgument, "name": groupArgument}),
+		call: func(s *Server, args toolArguments) (interface{}, error) {
+			project, err := s.project(args)
+			if err != nil {
+				return nil, err
+			}
+			name, err := required(args, "name")
+			if err != nil {
+				return nil, err
+			}
+
+			return s.Client.FindVariableGroup(s.collection(args), project, name)
+		},
+	},
+	{
+		Name:        "diff_variable_groups",
+		Description: "Compare two variable groups (or the groups with the same name in two projects), and list the variables that were added, removed or changed",
+		InputSchema: schema([]string{"name"}, map[string]string{
+			"collection":   collectionArgument,
+			"project":      projectArgument,
+			"name":         groupArgument,
+			"with":         "The other variable group (default is the same name)",
+			"otherProject": "The other group's project (default is the same project)",
+		}),
+		call: func(s *Server, args toolArguments) (interface{}, error) {
+			project, err := s.project(args)
+			if err != nil {
+				return nil, err
+			}
+			name, err := required(args, "name")
+			if err != nil {
+				return nil, err
+			}
+
+			otherName, otherProject := args.str("with"), args.str("otherProject")
+			if otherName == "" && otherProject == "" {
+				return nil, fmt.Errorf("Either 'with' (another group) or 'otherProject' is required")
+			}
+			if otherName == "" {
+				otherName = name
+			}
+			if otherProject == "" {
+				otherProject = project
+			}
+
+			from, err := s.Client.FindVariableGroup(s.collection(args), project, name)
+			if err != nil {
+				return nil, err
+			}
+			to, err := s.Client.FindVariableGroup(s.collection(args), otherProject, otherName)
+			if err != nil {
+				return nil, err
+			}
+
+			return tfs.DiffVariableGroups(from, to), nil
+		},
+	},
+	{
+		Name:        "copy_variable_group",
+		Description: "Copy a variable group (in the same project, or to another project)",
+		Mutating:    true,
+		InputSchema: schema([]string{"name"}, map[string]string{
+			"collection":    collectionArgument,
+			"project":       projectArgument,
+			"name":          groupArgument,
+			"newName":       "Name of the new group (default is 'Copy of <name> (<unique id>)')",
+			"targetProject": "Project to copy the group to (default is the same project)",
+		}),
+		call: func(s *Server, args toolArguments) (interface{}, error) {
+			project, err := s.project(args)
+			if err != nil {
+				return nil, err
+			}
+			name, err := required(args, "name")
+			if err != nil {
+				return nil, err
+			}
+
+			group, err := s.Client.FindVariableGroup(s.collection(args), project, name)
+			if err != nil {
+				return nil, err
+			}
+
+			newName := args.str("newName")
+			if newName == "" {
+				newName = tfs.CopyName(group.Name)
+			}
+			targetProject := args.str("targetProject")
+			if targetProject == "" {
+				targetProject = project
+			}
+
+			return s.Client.CreateVariableGroup(s.collection(args), targetProject, group.Copy(newName))
+		},
+	},
+	{
+		Name:        "set_variable",
+		Description: "Add or change a variable in a variable group",
+		Mutating:    true,
+		InputSchema: schema([]string{"name", "variable", "value"}, map[string]string{
+			"collection": collectionArgument,
+			"project":    projectArgument,
+			"name":       groupArgument,
+			"variable":   "Variable name",
+			"value":      "Variable value",
+			"secret":     "Make the variable secret",
+		}),
+		call: func(s *Server, args toolArguments) (interface{}, error) {
+			project, err := s.project(args)
+			if err != nil {
+				return nil, err
+			}
+			name, err := required(args, "name")
+			if err != nil {
+				return nil, err
+			}
+			variable, err := required(args, "variable")
+			if err != nil {
+				return nil, err
+			}
+
+			group, err := s.Client.FindVariableGroup(s.collection(args), project, name)
+			if err != nil {
+				return nil, err
+			}
+			if group.IsKeyVault() {
+				return nil, fmt.Errorf("The variables in '%s' come from a Key Vault, and can't be set here", group.Name)
+			}
+
+			if group.Variables == nil {
+				group.Variables = map[string]tfs.Variable{}
+			}
+			group.Variables[variable] = tfs.Variable{Value: args.str("value"), IsSecret: args.boolean("secret")}
+
+			return s.Client.UpdateVariableGroup(s.collection(args), project, group)
+		},
+	},
+}
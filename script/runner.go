@@ -0,0 +1,290 @@
+package script
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// Runner runs scripts
+type Runner struct {
+	// Client is used to talk to TFS
+	Client tfs.Client
+
+	// Collection and Project are used when neither the script nor the step name them
+	Collection string
+	Project    string
+
+	// OnError overrides the script's onError, if it's set
+	OnError string
+
+	// NoRollback leaves the steps that worked alone when a step fails
+	NoRollback bool
+
+	// DryRun reports what each step would do, without changing anything
+	DryRun bool
+
+	// OperationInterval and OperationTimeout control how long to wait for projects to be
+	// created or deleted (the defaults are 2 seconds and 5 minutes)
+	OperationInterval time.Duration
+	OperationTimeout  time.Duration
+
+	// Progress (if it's set) is called after each step
+	Progress func(result StepResult)
+}
+
+// StepResult is what happened to a single step
+type StepResult struct {
+	// Step is the step number (starting at 1)
+	Step   int
+	Action string
+	Target string
+	Result string
+	Err    error
+}
+
+// Report is what happened when a script was run
+type Report struct {
+	Steps []StepResult
+
+	// Rollback is what happened undoing the steps that worked, after a step failed
+	Rollback []StepResult
+
+	// Failed is the number of steps that failed
+	Failed int
+}
+
+// undo undoes a step that created something
+type undo struct {
+	result StepResult
+	run    func() error
+}
+
+// Run runs each step of a script, in order
+func (r *Runner) Run(s Script) Report {
+	report := Report{}
+	undos := []undo{}
+
+	onError := strings.ToLower(s.OnError)
+	if r.OnError != "" {
+		onError = strings.ToLower(r.OnError)
+	}
+
+	stopped := false
+	for i, step := range s.Steps {
+		result := StepResult{Step: i + 1, Action: step.Action, Target: step.Target()}
+
+		switch {
+		case stopped:
+			result.Result = "skipped"
+		case r.DryRun:
+			result.Result = "would " + strings.SplitN(step.Action, "-", 2)[0]
+		default:
+			collection, project := r.resolve(s, step)
+			summary, undoStep, err := r.runStep(collection, project, step)
+			result.Result, result.Err = summary, err
+			if err != nil {
+				result.Result = "error: " + err.Error()
+				report.Failed++
+				stopped = onError != Continue
+			} else if undoStep != nil {
+				undos = append(undos, undo{result: StepResult{Step: i + 1, Action: step.Action, Target: result.Target}, run: undoStep})
+			}
+		}
+
+		report.Steps = append(report.Steps, result)
+		if r.Progress != nil {
+			r.Progress(result)
+		}
+	}
+
+	//	Undo the steps that created something, newest first
+	if stopped && !r.NoRollback {
+		for i := len(undos) - 1; i >= 0; i-- {
+			result := undos[i].result
+			result.Result = "rolled back"
+			if err := undos[i].run(); err != nil {
+				result.Result, result.Err = "rollback failed: "+err.Error(), err
+			}
+			report.Rollback = append(report.Rollback, result)
+		}
+	}
+
+	return report
+}
+
+// resolve works out the collection and project for a step
+func (r *Runner) resolve(s Script, step Step) (string, string) {
+	collection := firstOf(step.Collection, s.Collection, r.Collection)
+	project := firstOf(s.Project, r.Project)
+	if step.Action != CreateProject {
+		project = firstOf(step.Project, project)
+	}
+	return collection, project
+}
+
+// runStep runs a single step.  It returns a summary of what was done and (for steps that created
+// something) a function that undoes it
+func (r *Runner) runStep(collection, project string, step Step) (string, func() error, error) {
+	switch step.Action {
+	case CreateProject:
+		return r.createProject(collection, step)
+
+	case CreateGroup:
+		group := tfs.VariableGroup{Name: step.Group, Description: step.Description, Type: "Vsts", Variables: variables(step)}
+		created, err := r.Client.CreateVariableGroup(collection, project, group)
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("created (%v variables)", len(created.Variables)), r.deleteGroup(collection, project, created.ID), nil
+
+	case CopyGroup:
+		group, err := r.Client.FindVariableGroup(collection, project, step.Group)
+		if err != nil {
+			return "", nil, err
+		}
+
+		name := step.To
+		if name == "" {
+			name = tfs.CopyName(group.Name)
+		}
+		toProject := firstOf(step.ToProject, project)
+
+		created, err := r.Client.CreateVariableGroup(collection, toProject, group.Copy(name))
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("copied to '%s' (%v variables)", created.Name, len(created.Variables)), r.deleteGroup(collection, toProject, created.ID), nil
+
+	case SetVariables:
+		group, err := r.Client.FindVariableGroup(collection, project, step.Group)
+		if err != nil {
+			return "", nil, err
+		}
+		if group.IsKeyVault() {
+			return "", nil, fmt.Errorf("The variables in '%s' come from a Key Vault, and can't be set here", group.Name)
+		}
+
+		if group.Variables == nil {
+			group.Variables = map[string]tfs.Variable{}
+		}
+		for name, variable := range variables(step) {
+			group.Variables[name] = variable
+		}
+		for _, name := range step.Remove {
+			delete(group.Variables, name)
+		}
+
+		if _, err := r.Client.UpdateVariableGroup(collection, project, group); err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("set %v, removed %v", len(step.Variables)+len(step.Secrets), len(step.Remove)), nil, nil
+
+	case DeleteGroup:
+		group, err := r.Client.FindVariableGroup(collection, project, step.Group)
+		if err != nil {
+			return "", nil, err
+		}
+		if err := r.Client.DeleteVariableGroup(collection, project, group.ID); err != nil {
+			return "", nil, err
+		}
+		return "deleted", nil, nil
+	}
+
+	return "", nil, fmt.Errorf("Unknown action '%s'", step.Action)
+}
+
+// createProject creates a project, and waits for it to be ready
+func (r *Runner) createProject(collection string, step Step) (string, func() error, error) {
+	processes, err := r.Client.GetListOfProcesses(collection)
+	if err != nil {
+		return "", nil, err
+	}
+
+	processID := ""
+	for _, process := range processes.Processes {
+		if (step.Process == "" && process.IsDefault) || strings.EqualFold(process.Name, step.Process) {
+			processID = process.ID
+		}
+	}
+	if processID == "" {
+		return "", nil, fmt.Errorf("There isn't a '%s' process", step.Process)
+	}
+
+	operation, err := r.Client.CreateProject(collection, tfs.NewGitProject(step.Project, step.Description, processID))
+	if err != nil {
+		return "", nil, err
+	}
+	if _, err := r.Client.WaitForOperation(collection, operation, r.interval(), r.timeout()); err != nil {
+		return "", nil, err
+	}
+
+	//	Find the new project's id, so it can be deleted if we need to roll back
+	projects, err := r.Client.GetListOfProjects(collection)
+	if err != nil {
+		return "", nil, err
+	}
+
+	for _, project := range projects.Projects {
+		if strings.EqualFold(project.Name, step.Project) {
+			projectID := project.ID
+			return "created", func() error {
+				operation, err := r.Client.DeleteProject(collection, projectID)
+				if err != nil {
+					return err
+				}
+				_, err = r.Client.WaitForOperation(collection, operation, r.interval(), r.timeout())
+				return err
+			}, nil
+		}
+	}
+
+	return "created (but it can't be found to roll it back)", nil, nil
+}
+
+// deleteGroup undoes creating a group
+func (r *Runner) deleteGroup(collection, project string, id int) func() error {
+	return func() error {
+		return r.Client.DeleteVariableGroup(collection, project, id)
+	}
+}
+
+// interval is how often to check on a long running operation
+func (r *Runner) interval() time.Duration {
+	if r.OperationInterval > 0 {
+		return r.OperationInterval
+	}
+	return 2 * time.Second
+}
+
+// timeout is how long to wait for a long running operation
+func (r *Runner) timeout() time.Duration {
+	if r.OperationTimeout > 0 {
+		return r.OperationTimeout
+	}
+	return 5 * time.Minute
+}
+
+// variables gets a step's variables and secrets
+func variables(step Step) map[string]tfs.Variable {
+	retval := map[string]tfs.Variable{}
+	for name, value := range step.Variables {
+		retval[name] = tfs.Variable{Value: value}
+	}
+	for name, value := range step.Secrets {
+		retval[name] = tfs.Variable{Value: value, IsSecret: true}
+	}
+	return retval
+}
+
+// firstOf returns the first value that isn't blank
+func firstOf(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
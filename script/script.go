@@ -0,0 +1,215 @@
+// Package script runs a list of tfsutil operations (creating projects, creating, copying and
+// deleting variable groups, and setting variables) from a YAML file, in order:
+//
+//	vars:
+//	  env: qa
+//	project: Website
+//	steps:
+//	- action: copy-group
+//	  group: Unicorn variables
+//	  to: Unicorn variables ({{.env}})
+//	- action: set-variables
+//	  group: Unicorn variables ({{.env}})
+//	  variables:
+//	    Environment: "{{.env}}"
+//	  secrets:
+//	    Password: '{{env "UNICORN_PASSWORD"}}'
+//
+// The file is a Go template.  It can use its vars (which can be overridden when it's run) and
+// environment variables (with env).  If a step fails, the steps that created something are undone
+package script
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Actions a step can take
+const (
+	CreateProject = "create-project"
+	CreateGroup   = "create-group"
+	CopyGroup     = "copy-group"
+	SetVariables  = "set-variables"
+	DeleteGroup   = "delete-group"
+)
+
+// What to do when a step fails
+const (
+	// Stop skips the rest of the steps (and rolls back the steps that created something)
+	Stop = "stop"
+
+	// Continue runs the rest of the steps anyway
+	Continue = "continue"
+)
+
+// Script is a list of steps
+type Script struct {
+	// Vars can be used in the rest of the file, like {{.env}}
+	Vars map[string]string `yaml:"vars"`
+
+	// Collection and Project are used by steps that don't name them
+	Collection string `yaml:"collection"`
+	Project    string `yaml:"project"`
+
+	// OnError is 'stop' (the default) or 'continue'
+	OnError string `yaml:"onError"`
+
+	Steps []Step `yaml:"steps"`
+}
+
+// Step is a single operation
+type Step struct {
+	Action string `yaml:"action"`
+
+	// Collection and Project override the script's.  For create-project, Project is the new project
+	Collection string `yaml:"collection"`
+	Project    string `yaml:"project"`
+
+	// Group is the variable group the step works on (or creates)
+	Group string `yaml:"group"`
+
+	// To and ToProject are the name and project of a copy (the defaults are 'Copy of <group> (<unique id>)'
+	// and the same project)
+	To        string `yaml:"to"`
+	ToProject string `yaml:"toProject"`
+
+	// Description is used for new projects and groups
+	Description string `yaml:"description"`
+
+	// Process is the process (like Agile or Scrum) a new project uses (default is the collection's default)
+	Process string `yaml:"process"`
+
+	// Variables and Secrets are added to (or changed in) the group.  Remove lists variables to remove
+	Variables map[string]string `yaml:"variables"`
+	Secrets   map[string]string `yaml:"secrets"`
+	Remove    []string          `yaml:"remove"`
+}
+
+// Load reads a script, filling in its template with its vars and the given vars (which win)
+func Load(data []byte, vars map[string]string) (Script, error) {
+	retval := Script{}
+
+	//	Read the file once (without the template) to find its vars.  The template
+	//	can't be parsed as YAML yet, so only the vars section is read
+	raw := struct {
+		Vars map[string]string `yaml:"vars"`
+	}{}
+	if err := yaml.Unmarshal(varsSection(data), &raw); err != nil {
+		return retval, fmt.Errorf("Unable to read the vars: %s", err)
+	}
+
+	merged := map[string]string{}
+	for name, value := range raw.Vars {
+		merged[name] = value
+	}
+	for name, value := range vars {
+		merged[name] = value
+	}
+
+	//	Fill in the template, and read the result
+	parsed, err := template.New("script").Option("missingkey=error").Funcs(template.FuncMap{"env": os.Getenv}).Parse(string(data))
+	if err != nil {
+		return retval, err
+	}
+
+	rendered := new(bytes.Buffer)
+	if err := parsed.Execute(rendered, merged); err != nil {
+		return retval, err
+	}
+
+	if err := yaml.Unmarshal(rendered.Bytes(), &retval); err != nil {
+		return retval, err
+	}
+	retval.Vars = merged
+
+	return retval, retval.Validate()
+}
+
+// varsSection gets the top level 'vars:' section of a script (and nothing else)
+func varsSection(data []byte) []byte {
+	retval := []string{}
+	inVars := false
+
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimRight(line, " \t\r")
+		topLevel := trimmed != "" && !strings.HasPrefix(trimmed, " ") && !strings.HasPrefix(trimmed, "\t") && !strings.HasPrefix(trimmed, "#")
+
+		if topLevel {
+			inVars = strings.HasPrefix(trimmed, "vars:")
+		}
+		if inVars {
+			retval = append(retval, line)
+		}
+	}
+
+	return []byte(strings.Join(retval, "\n"))
+}
+
+// Validate checks every step before anything is run, so a mistake in the last step doesn't leave
+// the first steps half done
+func (s Script) Validate() error {
+	switch strings.ToLower(s.OnError) {
+	case "", Stop, Continue:
+	default:
+		return fmt.Errorf("onError should be '%s' or '%s', not '%s'", Stop, Continue, s.OnError)
+	}
+
+	if len(s.Steps) == 0 {
+		return fmt.Errorf("The script doesn't have any steps")
+	}
+
+	for i, step := range s.Steps {
+		if err := step.validate(); err != nil {
+			return fmt.Errorf("Step %v (%s): %s", i+1, step.Action, err)
+		}
+	}
+
+	return nil
+}
+
+// validate checks that a step has what its action needs
+func (step Step) validate() error {
+	switch step.Action {
+	case CreateProject:
+		if step.Project == "" {
+			return fmt.Errorf("The new project's name ('project') is required")
+		}
+	case CreateGroup, CopyGroup, SetVariables, DeleteGroup:
+		if step.Group == "" {
+			return fmt.Errorf("A variable group ('group') is required")
+		}
+	case "":
+		return fmt.Errorf("An action is required")
+	default:
+		return fmt.Errorf("Unknown action (expected %s)", strings.Join([]string{CreateProject, CreateGroup, CopyGroup, SetVariables, DeleteGroup}, ", "))
+	}
+
+	if step.Action == SetVariables && len(step.Variables)+len(step.Secrets)+len(step.Remove) == 0 {
+		return fmt.Errorf("There are no variables to set or remove")
+	}
+
+	return nil
+}
+
+// Target describes what a step works on, for reports
+func (step Step) Target() string {
+	switch step.Action {
+	case CreateProject:
+		return step.Project
+	case CopyGroup:
+		to := step.To
+		if to == "" {
+			to = "a copy"
+		}
+		if step.ToProject != "" {
+			to = step.ToProject + "/" + to
+		}
+		return step.Group + " -> " + to
+	}
+	return step.Group
+}
@@ -0,0 +1,207 @@
+package script_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danesparza/tfsutil/script"
+	"github.com/danesparza/tfsutil/tfs/tfstest"
+)
+
+// newTestRunner starts a fake TFS server with a project and a group, and creates a runner that uses it
+func newTestRunner() (*tfstest.Server, *script.Runner) {
+	server := tfstest.NewServerWithGroup()
+
+	return server, &script.Runner{Client: server.Client(), Collection: "DefaultCollection", OperationInterval: time.Millisecond}
+}
+
+// load loads a script, and fails the test if it can't
+func load(t *testing.T, text string, vars map[string]string) script.Script {
+	s, err := script.Load([]byte(text), vars)
+	if err != nil {
+		t.Fatalf("Unable to load the script: %s", err)
+	}
+	return s
+}
+
+// The template should be filled in with the script's vars, the vars it's run with (which win) and environment variables
+func TestLoad_VarsAndEnvironment_FillsInTemplate(t *testing.T) {
+
+	//	Arrange
+	os.Setenv("SCRIPT_TEST_PASSWORD", "hunter2")
+	defer os.Unsetenv("SCRIPT_TEST_PASSWORD")
+
+	text := `
+vars:
+  env: qa
+  region: east
+project: Website
+steps:
+- action: set-variables
+  group: Unicorn variables ({{.env}})
+  variables:
+    Region: "{{.region}}"
+  secrets:
+    Password: '{{env "SCRIPT_TEST_PASSWORD"}}'
+`
+
+	//	Act
+	s := load(t, text, map[string]string{"env": "prod"})
+
+	//	Assert
+	step := s.Steps[0]
+	if step.Group != "Unicorn variables (prod)" || step.Variables["Region"] != "east" || step.Secrets["Password"] != "hunter2" {
+		t.Errorf("Expected the template to be filled in (with the given env winning), but got %+v", step)
+	}
+}
+
+// A script with a mistake anywhere in it (a bad step, a missing var or a bad error mode) shouldn't load
+func TestLoad_BadSteps_ReturnsErrorBeforeRunning(t *testing.T) {
+
+	//	Arrange
+	scripts := map[string]string{
+		"unknown action":  "steps:\n- action: create-unicorn\n  group: Sparkly",
+		"missing group":   "steps:\n- action: copy-group",
+		"nothing to set":  "steps:\n- action: set-variables\n  group: Sparkly",
+		"missing var":     "steps:\n- action: delete-group\n  group: '{{.nothing}}'",
+		"bad error mode":  "onError: panic\nsteps:\n- action: delete-group\n  group: Sparkly",
+		"no steps at all": "project: Website",
+	}
+
+	for name, text := range scripts {
+		//	Act
+		_, err := script.Load([]byte(text), nil)
+
+		//	Assert
+		if err == nil {
+			t.Errorf("Expected an error loading a script with %s", name)
+		}
+	}
+}
+
+// Every step should be run, in order
+func TestRun_AllStepsWork_AppliesEverything(t *testing.T) {
+
+	//	Arrange
+	server, runner := newTestRunner()
+	defer server.Close()
+
+	s := load(t, `
+project: Website
+steps:
+- action: create-project
+  project: Mobile
+  process: Scrum
+- action: copy-group
+  group: Unicorn variables
+  to: Unicorn variables (mobile)
+  toProject: Mobile
+- action: set-variables
+  project: Mobile
+  group: Unicorn variables (mobile)
+  variables:
+    Tail: fluffy
+  remove: [Horn, Mane]
+- action: create-group
+  group: Dragon variables
+  variables:
+    Wings: leathery
+`, nil)
+
+	//	Act
+	report := runner.Run(s)
+
+	//	Assert
+	if report.Failed != 0 || len(report.Steps) != 4 || len(report.Rollback) != 0 {
+		t.Fatalf("Expected every step to work, but got %+v", report)
+	}
+
+	mobile := server.VariableGroups("DefaultCollection", "Mobile")
+	if len(mobile) != 1 || mobile[0].Name != "Unicorn variables (mobile)" || mobile[0].Variables["Tail"].Value != "fluffy" || len(mobile[0].Variables) != 1 {
+		t.Errorf("Expected the copied group (with Tail, and without Horn and Mane) in the new project, but got %+v", mobile)
+	}
+
+	if groups := tfstest.SortedGroupNames(server.VariableGroups("DefaultCollection", "Website")); len(groups) != 2 {
+		t.Errorf("Expected the new group in Website, but got %v", groups)
+	}
+}
+
+// When a step fails, the rest should be skipped and the steps that created something undone (newest first)
+func TestRun_StepFails_StopsAndRollsBack(t *testing.T) {
+
+	//	Arrange
+	server, runner := newTestRunner()
+	defer server.Close()
+
+	s := load(t, `
+project: Website
+steps:
+- action: create-project
+  project: Mobile
+- action: copy-group
+  group: Unicorn variables
+  to: Unicorn copy
+- action: set-variables
+  group: Missing group
+  variables:
+    Mane: rainbow
+- action: create-group
+  group: Dragon variables
+`, nil)
+
+	//	Act
+	report := runner.Run(s)
+
+	//	Assert
+	results := []string{}
+	for _, result := range report.Steps {
+		results = append(results, strings.SplitN(result.Result, " ", 2)[0])
+	}
+	if strings.Join(results, ",") != "created,copied,error:,skipped" || report.Failed != 1 {
+		t.Errorf("Expected the third step to fail and the fourth to be skipped, but got %v", results)
+	}
+
+	if len(report.Rollback) != 2 || report.Rollback[0].Step != 2 || report.Rollback[1].Step != 1 || report.Rollback[1].Result != "rolled back" {
+		t.Errorf("Expected the copy and then the project to be rolled back, but got %+v", report.Rollback)
+	}
+
+	if projects := server.Projects("DefaultCollection"); len(projects) != 1 {
+		t.Errorf("Expected the new project to be deleted, but got %+v", projects)
+	}
+
+	if groups := tfstest.SortedGroupNames(server.VariableGroups("DefaultCollection", "Website")); len(groups) != 1 {
+		t.Errorf("Expected the copy to be deleted, but got %v", groups)
+	}
+}
+
+// In continue mode, the steps after a failure should still run, and nothing should be undone
+func TestRun_ContinueMode_RunsEveryStepWithoutRollback(t *testing.T) {
+
+	//	Arrange
+	server, runner := newTestRunner()
+	defer server.Close()
+	runner.OnError = script.Continue
+
+	s := load(t, `
+project: Website
+steps:
+- action: delete-group
+  group: Missing group
+- action: create-group
+  group: Dragon variables
+`, nil)
+
+	//	Act
+	report := runner.Run(s)
+
+	//	Assert
+	if report.Failed != 1 || report.Steps[1].Result != "created (0 variables)" || len(report.Rollback) != 0 {
+		t.Errorf("Expected the second step to run after the first failed, but got %+v", report)
+	}
+
+	if groups := tfstest.SortedGroupNames(server.VariableGroups("DefaultCollection", "Website")); len(groups) != 2 {
+		t.Errorf("Expected the new group to be kept, but got %v", groups)
+	}
+}
@@ -0,0 +1,82 @@
+package tfs
+
+import (
+	"strings"
+	"time"
+)
+
+// AgentPoolsResponse defines the response recieved when querying agent pools
+type AgentPoolsResponse struct {
+	Count      int         `json:"count"`
+	AgentPools []AgentPool `json:"value"`
+}
+
+// AgentPool is a single agent pool
+type AgentPool struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Size     int    `json:"size"`
+	IsHosted bool   `json:"isHosted"`
+	PoolType string `json:"poolType"`
+}
+
+// AgentsResponse defines the response recieved when querying the agents in a pool
+type AgentsResponse struct {
+	Count  int     `json:"count"`
+	Agents []Agent `json:"value"`
+}
+
+// Agent is a single build/release agent
+type Agent struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	Version       string `json:"version"`
+	OSDescription string `json:"osDescription"`
+	Enabled       bool   `json:"enabled"`
+
+	// Status is the connection status: online or offline
+	Status string `json:"status"`
+
+	CreatedOn time.Time `json:"createdOn"`
+
+	// SystemCapabilities are the capabilities the agent discovered for itself
+	SystemCapabilities map[string]string `json:"systemCapabilities"`
+
+	// UserCapabilities are the capabilities added by hand
+	UserCapabilities map[string]string `json:"userCapabilities"`
+
+	// AssignedRequest is the job the agent is currently running (if any)
+	AssignedRequest *AgentJobRequest `json:"assignedRequest"`
+}
+
+// AgentJobRequest is a single job request assigned to an agent
+type AgentJobRequest struct {
+	RequestID  int       `json:"requestId"`
+	QueueTime  time.Time `json:"queueTime"`
+	AssignTime time.Time `json:"assignTime"`
+	PlanType   string    `json:"planType"`
+	JobID      string    `json:"jobId"`
+
+	Definition struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"definition"`
+
+	Owner struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"owner"`
+}
+
+// HasCapability returns true if the agent has the given system or user capability.  If value is blank,
+// the capability only needs to exist.  Keys and values are compared without regard to case
+func (a Agent) HasCapability(key, value string) bool {
+	for _, capabilities := range []map[string]string{a.UserCapabilities, a.SystemCapabilities} {
+		for k, v := range capabilities {
+			if strings.EqualFold(k, key) && (value == "" || strings.EqualFold(v, value)) {
+				return true
+			}
+		}
+	}
+	return false
+}
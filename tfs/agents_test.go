@@ -0,0 +1,39 @@
+package tfs_test
+
+import (
+	"testing"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// Capabilities should be found in either the system or user capabilities, with or without a value
+func TestAgent_Capabilities_HasCapability_ReturnsMatch(t *testing.T) {
+
+	//	Arrange
+	agent := tfs.Agent{
+		SystemCapabilities: map[string]string{"Agent.OS": "Windows_NT", "java": "C:\\java"},
+		UserCapabilities:   map[string]string{"gpu": "true"},
+	}
+
+	tests := []struct {
+		key      string
+		value    string
+		expected bool
+	}{
+		{"java", "", true},
+		{"agent.os", "windows_nt", true},
+		{"Agent.OS", "Linux", false},
+		{"gpu", "true", true},
+		{"docker", "", false},
+	}
+
+	//	Act
+	for _, tt := range tests {
+		actual := agent.HasCapability(tt.key, tt.value)
+
+		//	Assert
+		if actual != tt.expected {
+			t.Errorf("HasCapability('%s', '%s') expected %v but got %v", tt.key, tt.value, tt.expected, actual)
+		}
+	}
+}
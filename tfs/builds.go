@@ -0,0 +1,216 @@
+package tfs
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// BuildsResponse defines the response recieved when querying builds
+type BuildsResponse struct {
+	Count  int     `json:"count"`
+	Builds []Build `json:"value"`
+}
+
+// Build is a single build
+type Build struct {
+	ID          int    `json:"id"`
+	BuildNumber string `json:"buildNumber"`
+
+	// Status is the build status: notStarted, inProgress, completed, cancelling, postponed
+	Status string `json:"status"`
+
+	// Result is the build result: succeeded, partiallySucceeded, failed, canceled
+	Result string `json:"result"`
+
+	QueueTime  time.Time `json:"queueTime"`
+	StartTime  time.Time `json:"startTime"`
+	FinishTime time.Time `json:"finishTime"`
+
+	SourceBranch  string `json:"sourceBranch"`
+	SourceVersion string `json:"sourceVersion"`
+	Reason        string `json:"reason"`
+	URL           string `json:"url"`
+
+	Definition struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+		Path string `json:"path"`
+	} `json:"definition"`
+
+	RequestedFor struct {
+		DisplayName string `json:"displayName"`
+		UniqueName  string `json:"uniqueName"`
+		ID          string `json:"id"`
+	} `json:"requestedFor"`
+}
+
+// Duration is the time the build spent running.  It is zero if the build hasn't started or finished
+func (b Build) Duration() time.Duration {
+	if b.StartTime.IsZero() || b.FinishTime.IsZero() {
+		return 0
+	}
+	return b.FinishTime.Sub(b.StartTime)
+}
+
+// QueueDuration is the time the build spent waiting for an agent.  It is zero if the build hasn't started
+func (b Build) QueueDuration() time.Duration {
+	if b.QueueTime.IsZero() || b.StartTime.IsZero() {
+		return 0
+	}
+	return b.StartTime.Sub(b.QueueTime)
+}
+
+// BuildQuery defines the optional filters used when querying builds
+type BuildQuery struct {
+	// Definitions is a list of build definition ids to include
+	Definitions []int
+
+	// BranchName is the full source branch name (like refs/heads/master)
+	BranchName string
+
+	// RequestedFor is the display name or unique name of the requesting user
+	RequestedFor string
+
+	// StatusFilter is a build status (like 'completed')
+	StatusFilter string
+
+	// ResultFilter is a build result (like 'failed')
+	ResultFilter string
+
+	// MinTime and MaxTime limit the builds to the given finish time window
+	MinTime time.Time
+	MaxTime time.Time
+
+	// Top is the maximum number of builds to return.  Zero means every build
+	Top int
+}
+
+// BuildDefinitionsResponse defines the response recieved when querying build definitions
+type BuildDefinitionsResponse struct {
+	Count       int               `json:"count"`
+	Definitions []BuildDefinition `json:"value"`
+}
+
+// BuildDefinition is a single build definition.  The list call only returns the reference fields
+// (id, name, path, type, revision and url)
+type BuildDefinition struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Type     string `json:"type"`
+	Revision int    `json:"revision"`
+	URL      string `json:"url"`
+
+	// Variables is the map of definition-level variables
+	Variables map[string]Variable `json:"variables,omitempty"`
+
+	// VariableGroups is the list of variable groups linked to this definition
+	VariableGroups []struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"variableGroups,omitempty"`
+
+	// Process describes how the build runs.  Type 1 is a designer build, type 2 is a YAML build
+	Process struct {
+		Type         int    `json:"type"`
+		YamlFilename string `json:"yamlFilename"`
+	} `json:"process"`
+
+	Repository struct {
+		ID            string `json:"id"`
+		Name          string `json:"name"`
+		Type          string `json:"type"`
+		DefaultBranch string `json:"defaultBranch"`
+	} `json:"repository"`
+}
+
+// IsYaml returns true if the build definition is defined by a YAML file in its repository
+func (d BuildDefinition) IsYaml() bool {
+	return d.Process.Type == 2 && d.Process.YamlFilename != ""
+}
+
+// BuildStats holds the aggregated build information for a single definition
+type BuildStats struct {
+	Definition string
+	Total      int
+	Succeeded  int
+	Durations  []time.Duration
+}
+
+// PassRate is the percentage of completed builds that succeeded
+func (s BuildStats) PassRate() float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	return float64(s.Succeeded) / float64(s.Total) * 100
+}
+
+// Average is the mean build duration
+func (s BuildStats) Average() time.Duration {
+	if len(s.Durations) == 0 {
+		return 0
+	}
+
+	var total time.Duration
+	for _, d := range s.Durations {
+		total += d
+	}
+	return total / time.Duration(len(s.Durations))
+}
+
+// Percentile gets the given (nearest rank) percentile of the build durations
+func (s BuildStats) Percentile(p float64) time.Duration {
+	if len(s.Durations) == 0 {
+		return 0
+	}
+
+	sorted := make([]time.Duration, len(s.Durations))
+	copy(sorted, s.Durations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+// SummarizeBuilds aggregates completed builds by definition name
+func SummarizeBuilds(builds []Build) []BuildStats {
+	stats := map[string]*BuildStats{}
+	names := []string{}
+
+	for _, build := range builds {
+		//	Only completed builds count towards the summary
+		if build.Status != "completed" || build.Result == "" {
+			continue
+		}
+
+		s, ok := stats[build.Definition.Name]
+		if !ok {
+			s = &BuildStats{Definition: build.Definition.Name}
+			stats[build.Definition.Name] = s
+			names = append(names, build.Definition.Name)
+		}
+
+		s.Total++
+		if build.Result == "succeeded" {
+			s.Succeeded++
+		}
+		if d := build.Duration(); d > 0 {
+			s.Durations = append(s.Durations, d)
+		}
+	}
+
+	sort.Strings(names)
+	retval := []BuildStats{}
+	for _, name := range names {
+		retval = append(retval, *stats[name])
+	}
+
+	return retval
+}
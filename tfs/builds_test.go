@@ -0,0 +1,117 @@
+package tfs_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// A completed build should report its run time and its time in the queue
+func TestBuild_Completed_Duration_ReturnsElapsedTime(t *testing.T) {
+
+	//	Arrange
+	queued := time.Date(2018, 6, 1, 10, 0, 0, 0, time.UTC)
+	build := tfs.Build{
+		QueueTime:  queued,
+		StartTime:  queued.Add(90 * time.Second),
+		FinishTime: queued.Add(10 * time.Minute),
+	}
+
+	//	Act
+	duration := build.Duration()
+	queueDuration := build.QueueDuration()
+
+	//	Assert
+	if duration != 10*time.Minute-90*time.Second {
+		t.Errorf("Duration expected %s but got %s", 10*time.Minute-90*time.Second, duration)
+	}
+
+	if queueDuration != 90*time.Second {
+		t.Errorf("QueueDuration expected %s but got %s", 90*time.Second, queueDuration)
+	}
+}
+
+// A build that hasn't started yet shouldn't report a duration
+func TestBuild_NotStarted_Duration_ReturnsZero(t *testing.T) {
+
+	//	Arrange
+	build := tfs.Build{
+		QueueTime: time.Date(2018, 6, 1, 10, 0, 0, 0, time.UTC),
+	}
+
+	//	Act
+	duration := build.Duration()
+	queueDuration := build.QueueDuration()
+
+	//	Assert
+	if duration != 0 || queueDuration != 0 {
+		t.Errorf("Duration and QueueDuration should be zero for a build that hasn't started, but got %s and %s", duration, queueDuration)
+	}
+}
+
+// Percentiles should use the nearest rank -- the smallest duration that the given percent of builds are at or under
+func TestBuildStats_Percentile_ReturnsNearestRank(t *testing.T) {
+
+	//	Arrange
+	stats := tfs.BuildStats{Durations: []time.Duration{6 * time.Minute, 1 * time.Minute, 5 * time.Minute, 2 * time.Minute, 4 * time.Minute, 3 * time.Minute}}
+	expected := map[float64]time.Duration{
+		50:  3 * time.Minute,
+		90:  6 * time.Minute,
+		95:  6 * time.Minute,
+		100: 6 * time.Minute,
+		10:  1 * time.Minute,
+	}
+
+	for percentile, duration := range expected {
+		//	Act
+		actual := stats.Percentile(percentile)
+
+		//	Assert
+		if actual != duration {
+			t.Errorf("P%v expected %s but got %s", percentile, duration, actual)
+		}
+	}
+
+	if empty := (tfs.BuildStats{}).Percentile(90); empty != 0 {
+		t.Errorf("Expected no percentile without durations, but got %s", empty)
+	}
+}
+
+// Only completed builds should be summarized, grouped (and sorted) by definition
+func TestSummarizeBuilds_MixedBuilds_SummarizesCompletedBuildsByDefinition(t *testing.T) {
+
+	//	Arrange
+	started := time.Date(2018, 6, 1, 10, 0, 0, 0, time.UTC)
+	build := func(definition, status, result string, minutes int) tfs.Build {
+		b := tfs.Build{Status: status, Result: result, QueueTime: started, StartTime: started, FinishTime: started.Add(time.Duration(minutes) * time.Minute)}
+		b.Definition.Name = definition
+		return b
+	}
+
+	builds := []tfs.Build{
+		build("Website CI", "completed", "succeeded", 10),
+		build("Website CI", "completed", "failed", 20),
+		build("API CI", "completed", "succeeded", 5),
+		build("Website CI", "completed", "succeeded", 30),
+		build("Website CI", "inProgress", "", 0),
+		build("API CI", "completed", "partiallySucceeded", 7),
+	}
+
+	//	Act
+	summary := tfs.SummarizeBuilds(builds)
+
+	//	Assert
+	if len(summary) != 2 || summary[0].Definition != "API CI" || summary[1].Definition != "Website CI" {
+		t.Fatalf("Expected API CI and Website CI (in that order), but got %+v", summary)
+	}
+
+	website := summary[1]
+	if website.Total != 3 || website.Succeeded != 2 || website.Average() != 20*time.Minute {
+		t.Errorf("Expected 3 Website CI builds (2 passed, 20m average), but got %+v", website)
+	}
+
+	if rate := summary[0].PassRate(); rate != 50 {
+		t.Errorf("Expected a 50%% pass rate for API CI, but got %v", rate)
+	}
+}
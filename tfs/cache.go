@@ -0,0 +1,324 @@
+package tfs
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Cache is an http transport that keeps the responses to list requests (see CachedLists) on disk.  Fresh
+// responses (younger than the TTL) are served without contacting TFS.  Stale responses with an ETag are
+// revalidated with If-None-Match.  Successful POST, PUT, PATCH and DELETE requests invalidate the cached
+// responses for the resource they change.  Everything else (like a single group that's about to be changed,
+// or an operation that's being waited for) always comes from TFS
+type Cache struct {
+	// Dir is the directory the responses are kept in
+	Dir string
+
+	// Profile separates the responses cached for different credentials
+	Profile string
+
+	// TTL is how long a response is served without checking with TFS
+	TTL time.Duration
+
+	// SkipReads sends every read to TFS without using or keeping cached responses.  Changes still
+	// invalidate the cached lists
+	SkipReads bool
+
+	next http.RoundTripper
+	now  func() time.Time
+}
+
+// CachedResponse is a single cached response
+type CachedResponse struct {
+	URL        string      `json:"url"`
+	StatusCode int         `json:"statusCode"`
+	Status     string      `json:"status"`
+	Headers    http.Header `json:"headers"`
+	Body       []byte      `json:"body"`
+	StoredAt   time.Time   `json:"storedAt"`
+}
+
+// CachedLists are the resources (the area and resource after _apis) whose lists are cached
+var CachedLists = []string{"projects", "projectcollections", "distributedtask/variablegroups"}
+
+// NewCache creates a response cache in the given directory, passing requests it can't answer on to the
+// next transport
+func NewCache(dir, profile string, ttl time.Duration, next http.RoundTripper) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	return &Cache{Dir: dir, Profile: profile, TTL: ttl, next: next, now: time.Now}, nil
+}
+
+// CacheProfile creates a cache profile name from the given credentials, without keeping them
+func CacheProfile(credentials string) string {
+	sum := sha256.Sum256([]byte(credentials))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// RoundTrip serves the request from the cache if it can, and otherwise sends it on
+func (c *Cache) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	if req.Method != http.MethodGet {
+		resp, err := c.next.RoundTrip(req)
+		if err == nil && resp.StatusCode < 400 {
+			c.Invalidate(req.URL.String())
+		}
+		return resp, err
+	}
+
+	if c.SkipReads || !cacheable(req) {
+		return c.next.RoundTrip(req)
+	}
+
+	key := req.URL.String()
+	cached, found := c.load(key)
+
+	//	If the cached response is still fresh, use it
+	if found && c.now().Sub(cached.StoredAt) < c.TTL {
+		log.Printf("[DEBUG] Using the cached response for %s", key)
+		return cached.response(req), nil
+	}
+
+	//	Otherwise, ask TFS (letting it tell us if our copy is still good)
+	if found && cached.Headers.Get("ETag") != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", cached.Headers.Get("ETag"))
+	}
+
+	resp, err := c.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	if found && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		log.Printf("[DEBUG] The cached response for %s is still current", key)
+		cached.StoredAt = c.now()
+		c.store(key, cached)
+		return cached.response(req), nil
+	}
+
+	if resp.StatusCode != http.StatusOK || hasCacheDirective(resp.Header, "no-store") {
+		return resp, nil
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	c.store(key, CachedResponse{
+		URL:        key,
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Headers:    resp.Header,
+		Body:       body,
+		StoredAt:   c.now(),
+	})
+
+	return resp, nil
+}
+
+// Invalidate removes the cached responses for the resource at the given url: every cached url (for this
+// profile) under the same area and resource, like .../_apis/distributedtask/variablegroups.  A change to a
+// single item (like .../_apis/projects/{id}) removes the cached list it's in
+func (c *Cache) Invalidate(rawURL string) {
+	prefix := cacheResourcePrefix(rawURL)
+
+	files, _ := filepath.Glob(filepath.Join(c.Dir, c.Profile+"-*.json"))
+	for _, file := range files {
+		cached, ok := readCachedResponse(file)
+		if !ok || sameCacheResource(cacheResourcePrefix(cached.URL), prefix) {
+			log.Printf("[DEBUG] Removing the cached response for %s", cached.URL)
+			os.Remove(file)
+		}
+	}
+}
+
+// Clear removes every cached response in the directory (for every profile)
+func (c *Cache) Clear() (int, error) {
+	return ClearCache(c.Dir)
+}
+
+// ClearCache removes every cached response in the given directory, and returns how many were removed
+func ClearCache(dir string) (int, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*-*.json"))
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, file := range files {
+		if err := os.Remove(file); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+
+	return removed, nil
+}
+
+// load gets a cached response
+func (c *Cache) load(key string) (CachedResponse, bool) {
+	cached, ok := readCachedResponse(c.fileName(key))
+	if !ok || cached.URL != key {
+		return CachedResponse{}, false
+	}
+	return cached, true
+}
+
+// store saves a cached response.  Problems are logged, since the cache is only an optimization
+func (c *Cache) store(key string, cached CachedResponse) {
+	data, err := json.Marshal(cached)
+	if err != nil {
+		log.Printf("[WARN] Unable to cache the response for %s: %s", key, err)
+		return
+	}
+
+	//	Write to a temp file first, so a reader never sees half a response.  Each write gets its own
+	//	temp file, since requests can run concurrently
+	file := c.fileName(key)
+	temp, err := ioutil.TempFile(c.Dir, filepath.Base(file)+".*.tmp")
+	if err != nil {
+		log.Printf("[WARN] Unable to cache the response for %s: %s", key, err)
+		return
+	}
+
+	_, err = temp.Write(data)
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(temp.Name())
+		log.Printf("[WARN] Unable to cache the response for %s: %s", key, err)
+		return
+	}
+
+	if err := os.Rename(temp.Name(), file); err != nil {
+		os.Remove(temp.Name())
+		log.Printf("[WARN] Unable to cache the response for %s: %s", key, err)
+	}
+}
+
+// fileName is the name of the file a url is cached in
+func (c *Cache) fileName(key string) string {
+	sum := sha256.Sum256([]byte(c.Profile + "\n" + key))
+	return filepath.Join(c.Dir, c.Profile+"-"+hex.EncodeToString(sum[:])+".json")
+}
+
+// response creates an http response from a cached response
+func (cached CachedResponse) response(req *http.Request) *http.Response {
+	headers := http.Header{}
+	for key, values := range cached.Headers {
+		headers[key] = values
+	}
+
+	return &http.Response{
+		StatusCode:    cached.StatusCode,
+		Status:        cached.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        headers,
+		Body:          ioutil.NopCloser(bytes.NewReader(cached.Body)),
+		ContentLength: int64(len(cached.Body)),
+		Request:       req,
+	}
+}
+
+// readCachedResponse reads a cached response file
+func readCachedResponse(file string) (CachedResponse, bool) {
+	retval := CachedResponse{}
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return retval, false
+	}
+
+	if err := json.Unmarshal(data, &retval); err != nil {
+		return retval, false
+	}
+
+	return retval, true
+}
+
+// cacheable returns true if the request is for a whole list (not a single item, or a filtered list) that's
+// in CachedLists, and doesn't ask to skip the cache
+func cacheable(req *http.Request) bool {
+	if hasCacheDirective(req.Header, "no-cache") || hasCacheDirective(req.Header, "no-store") {
+		return false
+	}
+
+	path := strings.Trim(strings.ToLower(req.URL.Path), "/")
+	index := strings.Index(path, "_apis/")
+	if index < 0 {
+		return false
+	}
+	resource := path[index+len("_apis/"):]
+
+	for _, list := range CachedLists {
+		if resource != list {
+			continue
+		}
+
+		//	Variable groups are only cached when they're all listed, not when one is being found by name
+		groupName := req.URL.Query().Get("groupName")
+		return groupName == "" || groupName == "*"
+	}
+
+	return false
+}
+
+// hasCacheDirective returns true if the Cache-Control header has the given directive
+func hasCacheDirective(headers http.Header, directive string) bool {
+	for _, value := range headers.Values("Cache-Control") {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), directive) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sameCacheResource returns true if one resource prefix is the other, or is the path it's under
+func sameCacheResource(first, second string) bool {
+	return first == second || strings.HasPrefix(first, second+"/") || strings.HasPrefix(second, first+"/")
+}
+
+// cacheResourcePrefix gets the part of a url that identifies the resource it's about: the host and the
+// path up to the area and resource after _apis (like host/Collection/Project/_apis/distributedtask/variablegroups)
+func cacheResourcePrefix(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+
+	parts := strings.Split(strings.Trim(strings.ToLower(u.Path), "/"), "/")
+	for i, part := range parts {
+		if part == "_apis" {
+			end := i + 3
+			if end > len(parts) {
+				end = len(parts)
+			}
+			parts = parts[:end]
+			break
+		}
+	}
+
+	return strings.ToLower(u.Host) + "/" + strings.Join(parts, "/")
+}
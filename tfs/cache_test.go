@@ -0,0 +1,267 @@
+package tfs_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/danesparza/tfsutil/tfs"
+	"github.com/danesparza/tfsutil/tfs/tfstest"
+)
+
+// newTestCache creates a cache in a temp dir, and returns a func that removes it
+func newTestCache(t *testing.T, ttl time.Duration) (*tfs.Cache, func()) {
+	dir, err := ioutil.TempDir("", "cache")
+	if err != nil {
+		t.Fatalf("Unable to create a temp dir: %s", err)
+	}
+
+	cache, err := tfs.NewCache(dir, tfs.CacheProfile("test"), ttl, tfs.Transport)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("Unable to create the cache: %s", err)
+	}
+
+	return cache, func() { os.RemoveAll(dir) }
+}
+
+// A fresh cached list should be used without asking the server again
+func TestCache_FreshResponse_DoesNotContactServer(t *testing.T) {
+
+	//	Arrange
+	server := tfstest.NewServer()
+	defer server.Close()
+	server.AddProject("DefaultCollection", tfs.Project{Name: "Website"})
+	client := server.Client()
+
+	cache, cleanup := newTestCache(t, time.Hour)
+	defer cleanup()
+	restore := useTransport(cache)
+	defer restore()
+
+	//	Act
+	first, firstErr := client.GetListOfProjects("DefaultCollection")
+	second, secondErr := client.GetListOfProjects("DefaultCollection")
+
+	//	Assert
+	if firstErr != nil || secondErr != nil {
+		t.Fatalf("Expected no errors but got %v and %v", firstErr, secondErr)
+	}
+
+	if len(server.Requests()) != 1 {
+		t.Errorf("Expected only one request to the server but got %v", server.Requests())
+	}
+
+	if second.Count != first.Count || second.Projects[0].Name != "Website" {
+		t.Errorf("Expected the cached response to match, but got %+v", second)
+	}
+}
+
+// Creating a group should remove the cached group list, but not the cached project list
+func TestCache_CreateVariableGroup_InvalidatesGroupList(t *testing.T) {
+
+	//	Arrange
+	server := tfstest.NewServer()
+	defer server.Close()
+	server.AddProject("DefaultCollection", tfs.Project{Name: "Website"})
+	server.AddVariableGroup("DefaultCollection", "Website", tfs.VariableGroup{Name: "Unicorn variables"})
+	client := server.Client()
+
+	cache, cleanup := newTestCache(t, time.Hour)
+	defer cleanup()
+	restore := useTransport(cache)
+	defer restore()
+
+	//	Act
+	before, _ := client.GetListOfVariableGroups("DefaultCollection", "Website")
+	client.GetListOfProjects("DefaultCollection")
+	_, createErr := client.CreateVariableGroup("DefaultCollection", "Website", tfs.VariableGroup{Name: "Dragon variables"})
+	after, _ := client.GetListOfVariableGroups("DefaultCollection", "Website")
+	client.GetListOfProjects("DefaultCollection")
+
+	//	Assert
+	if createErr != nil {
+		t.Fatalf("CreateVariableGroup failed: %s", createErr)
+	}
+
+	if before.Count != 1 || after.Count != 2 {
+		t.Errorf("Expected 1 group before and 2 after, but got %v and %v", before.Count, after.Count)
+	}
+
+	//	The project list isn't affected, so it should only be asked for once
+	projectRequests := 0
+	for _, request := range server.Requests() {
+		if request == "GET /DefaultCollection/_apis/projects" {
+			projectRequests++
+		}
+	}
+	if projectRequests != 1 {
+		t.Errorf("Expected the project list to stay cached, but got %v", server.Requests())
+	}
+}
+
+// A stale cached list should be checked with the server (using its ETag), and used if it hasn't changed
+func TestCache_StaleResponse_RevalidatesWithETag(t *testing.T) {
+
+	//	Arrange
+	server := tfstest.NewServer()
+	defer server.Close()
+	server.AddProject("DefaultCollection", tfs.Project{Name: "Website"})
+
+	notModified := 0
+	server.AddHook(func(w http.ResponseWriter, r *http.Request) bool {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return true
+		}
+		w.Header().Set("ETag", `"v1"`)
+		return false
+	})
+	client := server.Client()
+
+	cache, cleanup := newTestCache(t, 0)
+	defer cleanup()
+	restore := useTransport(cache)
+	defer restore()
+
+	//	Act
+	first, firstErr := client.GetListOfProjects("DefaultCollection")
+	second, secondErr := client.GetListOfProjects("DefaultCollection")
+
+	//	Assert
+	if firstErr != nil || secondErr != nil {
+		t.Fatalf("Expected no errors but got %v and %v", firstErr, secondErr)
+	}
+
+	if notModified != 1 || len(server.Requests()) != 2 {
+		t.Errorf("Expected the second request to be revalidated, but got %v revalidations and requests %v", notModified, server.Requests())
+	}
+
+	if second.Count != first.Count || second.Projects[0].Name != "Website" {
+		t.Errorf("Expected the revalidated response to match, but got %+v", second)
+	}
+}
+
+// Clearing the cache should remove every cached response
+func TestClearCache_CachedResponses_AreRemoved(t *testing.T) {
+
+	//	Arrange
+	server := tfstest.NewServer()
+	defer server.Close()
+	client := server.Client()
+
+	cache, cleanup := newTestCache(t, time.Hour)
+	defer cleanup()
+	restore := useTransport(cache)
+	defer restore()
+	client.GetListOfProjects("DefaultCollection")
+
+	//	Act
+	removed, err := tfs.ClearCache(cache.Dir)
+	client.GetListOfProjects("DefaultCollection")
+
+	//	Assert
+	if err != nil || removed != 1 {
+		t.Errorf("Expected 1 cached response to be removed, but got %v (%v)", removed, err)
+	}
+
+	if len(server.Requests()) != 2 {
+		t.Errorf("Expected the server to be asked again after clearing, but got %v", server.Requests())
+	}
+}
+
+// Only whole lists are cached.  A group that's found by name (before it's changed) and an operation
+// that's being waited for always come from the server
+func TestCache_GroupLookupsAndOperations_AreNotCached(t *testing.T) {
+
+	//	Arrange
+	server := tfstest.NewServer()
+	defer server.Close()
+	server.AddProject("DefaultCollection", tfs.Project{Name: "Website"})
+	server.AddVariableGroup("DefaultCollection", "Website", tfs.VariableGroup{Name: "Unicorn variables"})
+	client := server.Client()
+
+	cache, cleanup := newTestCache(t, time.Hour)
+	defer cleanup()
+	restore := useTransport(cache)
+	defer restore()
+
+	//	Act
+	for i := 0; i < 2; i++ {
+		client.FindVariableGroup("DefaultCollection", "Website", "Unicorn variables")
+		client.GetOperation("DefaultCollection", "00000000-0000-0000-0000-000000000000")
+	}
+
+	//	Assert
+	if len(server.Requests()) != 4 {
+		t.Errorf("Expected every request to reach the server, but got %v", server.Requests())
+	}
+}
+
+// A response marked no-store isn't kept
+func TestCache_NoStoreResponse_IsNotCached(t *testing.T) {
+
+	//	Arrange
+	server := tfstest.NewServer()
+	defer server.Close()
+	server.AddProject("DefaultCollection", tfs.Project{Name: "Website"})
+	server.AddHook(func(w http.ResponseWriter, r *http.Request) bool {
+		w.Header().Set("Cache-Control", "private, no-store")
+		return false
+	})
+	client := server.Client()
+
+	cache, cleanup := newTestCache(t, time.Hour)
+	defer cleanup()
+	restore := useTransport(cache)
+	defer restore()
+
+	//	Act
+	client.GetListOfProjects("DefaultCollection")
+	client.GetListOfProjects("DefaultCollection")
+
+	//	Assert
+	if len(server.Requests()) != 2 {
+		t.Errorf("Expected both requests to reach the server, but got %v", server.Requests())
+	}
+}
+
+// Deleting a single project or group should remove the cached list it was in
+func TestCache_DeleteItem_InvalidatesList(t *testing.T) {
+
+	//	Arrange
+	server := tfstest.NewServerWithGroup()
+	defer server.Close()
+	mobile := server.AddProject("DefaultCollection", tfs.Project{Name: "Mobile"})
+	client := server.Client()
+
+	cache, cleanup := newTestCache(t, time.Hour)
+	defer cleanup()
+	restore := useTransport(cache)
+	defer restore()
+
+	projectsBefore, _ := client.GetListOfProjects("DefaultCollection")
+	groupsBefore, _ := client.GetListOfVariableGroups("DefaultCollection", "Website")
+
+	//	Act
+	_, projectErr := client.DeleteProject("DefaultCollection", mobile.ID)
+	groupErr := client.DeleteVariableGroup("DefaultCollection", "Website", groupsBefore.VariableGroups[0].ID)
+	projectsAfter, _ := client.GetListOfProjects("DefaultCollection")
+	groupsAfter, _ := client.GetListOfVariableGroups("DefaultCollection", "Website")
+
+	//	Assert
+	if projectErr != nil || groupErr != nil {
+		t.Fatalf("Expected no errors but got %v and %v", projectErr, groupErr)
+	}
+
+	if projectsBefore.Count != 2 || projectsAfter.Count != 1 {
+		t.Errorf("Expected 2 projects before and 1 after, but got %v and %v", projectsBefore.Count, projectsAfter.Count)
+	}
+
+	if groupsBefore.Count != 1 || groupsAfter.Count != 0 {
+		t.Errorf("Expected 1 group before and none after, but got %v and %v", groupsBefore.Count, groupsAfter.Count)
+	}
+}
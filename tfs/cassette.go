@@ -0,0 +1,378 @@
+package tfs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Redacted replaces credentials and secret values in recorded requests and responses
+const Redacted = "REDACTED"
+
+// cassetteInfoFile is the name of the file that describes a cassette
+const cassetteInfoFile = "cassette.json"
+
+// CassetteInfo describes a recorded cassette
+type CassetteInfo struct {
+	// TfsURL is the TFS url the cassette was recorded against
+	TfsURL string `json:"tfsUrl"`
+}
+
+// Interaction is a single recorded request and its response
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a recorded request
+type RecordedRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers"`
+	Body    string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is a recorded response
+type RecordedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Status     string      `json:"status"`
+	Headers    http.Header `json:"headers"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Recorder is an http transport that records every request and response to a cassette directory,
+// with credentials and secret values redacted
+type Recorder struct {
+	dir   string
+	next  http.RoundTripper
+	mu    sync.Mutex
+	count int
+}
+
+// NewRecorder creates a recorder that saves interactions to the given directory, and passes each
+// request on to the next transport
+func NewRecorder(dir, tfsURL string, next http.RoundTripper) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	info, err := json.MarshalIndent(CassetteInfo{TfsURL: tfsURL}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, cassetteInfoFile), info, 0644); err != nil {
+		return nil, err
+	}
+
+	//	Continue numbering after any interactions already in the directory
+	existing, _ := filepath.Glob(filepath.Join(dir, "[0-9][0-9][0-9][0-9]-*.json"))
+
+	return &Recorder{dir: dir, next: next, count: len(existing)}, nil
+}
+
+// RoundTrip sends the request, and records it along with its response
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	//	Read the request body (and put it back, so it can still be sent)
+	requestBody := []byte{}
+	if req.Body != nil {
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		requestBody = body
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	//	Read the response body (and put it back for the caller)
+	responseBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(responseBody))
+
+	interaction := Interaction{
+		Request: RecordedRequest{
+			Method:  req.Method,
+			URL:     req.URL.String(),
+			Headers: redactHeaders(req.Header),
+			Body:    redactRequestBody(requestBody),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Headers:    redactHeaders(resp.Header),
+			Body:       RedactBody(responseBody),
+		},
+	}
+
+	if err := r.save(interaction); err != nil {
+		log.Printf("[WARN] Unable to record the request to %s: %s", req.URL, err)
+	}
+
+	return resp, nil
+}
+
+// save writes a single interaction to the cassette directory
+func (r *Recorder) save(interaction Interaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.count++
+	name := fmt.Sprintf("%04d-%s-%s.json", r.count, interaction.Request.Method, cassetteFileName(interaction.Request.URL))
+
+	formatted, err := json.MarshalIndent(interaction, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filepath.Join(r.dir, name), formatted, 0644)
+}
+
+// Replayer is an http transport that serves recorded responses from a cassette directory instead of
+// contacting TFS
+type Replayer struct {
+	// Info describes the cassette
+	Info CassetteInfo
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewReplayer loads the cassette in the given directory
+func NewReplayer(dir string) (*Replayer, error) {
+	retval := &Replayer{}
+
+	if data, err := ioutil.ReadFile(filepath.Join(dir, cassetteInfoFile)); err == nil {
+		if err := json.Unmarshal(data, &retval.Info); err != nil {
+			return nil, fmt.Errorf("Reading %s: %s", cassetteInfoFile, err)
+		}
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "[0-9][0-9][0-9][0-9]-*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("There aren't any recorded requests in %s", dir)
+	}
+
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		interaction := Interaction{}
+		if err := json.Unmarshal(data, &interaction); err != nil {
+			return nil, fmt.Errorf("Reading %s: %s", filepath.Base(file), err)
+		}
+
+		retval.interactions = append(retval.interactions, interaction)
+		retval.used = append(retval.used, false)
+	}
+
+	return retval, nil
+}
+
+// RoundTrip serves the recorded response for the request.  Requests are matched by method, path and
+// query (not host), in the order they were recorded.  Once every matching response has been served,
+// the last one is served again
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := requestKey(req.Method, req.URL.RequestURI())
+	last := -1
+	for i, interaction := range r.interactions {
+		recorded, err := http.NewRequest(interaction.Request.Method, interaction.Request.URL, nil)
+		if err != nil || requestKey(recorded.Method, recorded.URL.RequestURI()) != key {
+			continue
+		}
+
+		last = i
+		if !r.used[i] {
+			r.used[i] = true
+			return recordedResponse(req, interaction.Response), nil
+		}
+	}
+
+	if last >= 0 {
+		return recordedResponse(req, r.interactions[last].Response), nil
+	}
+
+	return nil, fmt.Errorf("There isn't a recorded response for %s %s", req.Method, req.URL.RequestURI())
+}
+
+// recordedResponse creates an http response from a recorded response
+func recordedResponse(req *http.Request, recorded RecordedResponse) *http.Response {
+	headers := http.Header{}
+	for key, values := range recorded.Headers {
+		headers[key] = values
+	}
+
+	return &http.Response{
+		StatusCode:    recorded.StatusCode,
+		Status:        recorded.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        headers,
+		Body:          ioutil.NopCloser(strings.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}
+}
+
+// requestKey is the key used to match a request to a recorded request
+func requestKey(method, requestURI string) string {
+	return strings.ToUpper(method) + " " + strings.ToLower(requestURI)
+}
+
+// sensitiveHeaders are the headers that are never recorded
+var sensitiveHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "X-Tfs-Session"}
+
+// redactHeaders copies headers, redacting credentials
+func redactHeaders(headers http.Header) http.Header {
+	retval := http.Header{}
+	for key, values := range headers {
+		retval[key] = append([]string{}, values...)
+	}
+
+	for _, key := range sensitiveHeaders {
+		if retval.Get(key) != "" {
+			retval.Set(key, Redacted)
+		}
+	}
+
+	return retval
+}
+
+// sensitiveFieldPattern matches JSON field names that hold credentials.  Every value under a service
+// endpoint's authorization parameters is redacted anyway, so this catches credentials kept anywhere else
+var sensitiveFieldPattern = regexp.MustCompile(`(?i)password|secret|token|apikey|accesskey|privatekey|serviceprincipalkey|certificate|credential`)
+
+// redactRequestBody redacts a request body like RedactBody, except a body that isn't JSON (like an uploaded secure
+// file) is replaced entirely, since there's no telling what's secret in it
+func redactRequestBody(body []byte) string {
+	if len(bytes.TrimSpace(body)) > 0 && !json.Valid(body) {
+		return fmt.Sprintf("%s (%d bytes)", Redacted, len(body))
+	}
+
+	return RedactBody(body)
+}
+
+// RedactBody redacts the secret values in a JSON body: the values of secret variables (marked isSecret), the
+// authorization parameters of service endpoints and any string field whose name looks like a credential.  Bodies that aren't JSON are returned as they are
+func RedactBody(body []byte) string {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return ""
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return string(body)
+	}
+
+	redacted, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return string(body)
+	}
+
+	return string(redacted)
+}
+
+// redactValue redacts secrets in a decoded JSON value
+func redactValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		secret, _ := typed["isSecret"].(bool)
+		for key, item := range typed {
+			//	Authorization parameters can be called anything (kubeconfig, nugetkey, ...), so they're all redacted
+			if authorization, ok := item.(map[string]interface{}); ok && strings.EqualFold(key, "authorization") {
+				if parameters, ok := authorization["parameters"].(map[string]interface{}); ok {
+					authorization["parameters"] = redactAll(parameters)
+				}
+			}
+			if _, ok := item.(string); ok && item != "" && (sensitiveFieldPattern.MatchString(key) || (secret && key == "value")) {
+				typed[key] = Redacted
+				continue
+			}
+			typed[key] = redactValue(item)
+		}
+		return typed
+	case []interface{}:
+		for i, item := range typed {
+			typed[i] = redactValue(item)
+		}
+		return typed
+	}
+
+	return value
+}
+
+// redactAll redacts every (non empty) string value in a decoded JSON object
+func redactAll(values map[string]interface{}) map[string]interface{} {
+	for key, item := range values {
+		switch typed := item.(type) {
+		case string:
+			if typed != "" {
+				values[key] = Redacted
+			}
+		case map[string]interface{}:
+			values[key] = redactAll(typed)
+		}
+	}
+	return values
+}
+
+// cassetteFileNamePattern matches the characters that can't be used in a cassette file name
+var cassetteFileNamePattern = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// cassetteFileName makes a short, readable file name from a request url (like distributedtask-variablegroups)
+func cassetteFileName(rawURL string) string {
+	name := rawURL
+	if i := strings.Index(name, "_apis/"); i >= 0 {
+		name = name[i+len("_apis/"):]
+	}
+	if i := strings.Index(name, "?"); i >= 0 {
+		name = name[:i]
+	}
+
+	name = strings.Trim(cassetteFileNamePattern.ReplaceAllString(name, "-"), "-")
+	if len(name) > 60 {
+		name = name[:60]
+	}
+	if name == "" {
+		name = "request"
+	}
+
+	return name
+}
@@ -0,0 +1,94 @@
+package tfs
+
+import (
+	"strings"
+	"time"
+)
+
+// These are the classification node structure groups
+const (
+	StructureGroupAreas      = "Areas"
+	StructureGroupIterations = "Iterations"
+)
+
+// ClassificationNode is a single area path or iteration path (and its children)
+type ClassificationNode struct {
+	ID            int    `json:"id,omitempty"`
+	Identifier    string `json:"identifier,omitempty"`
+	Name          string `json:"name"`
+	StructureType string `json:"structureType,omitempty"`
+	HasChildren   bool   `json:"hasChildren,omitempty"`
+
+	// Path is the complete path, like \Project\Area\Sub area (or \Project\Iteration\Sprint 1)
+	Path string `json:"path,omitempty"`
+
+	// Attributes are the start and finish dates of an iteration
+	Attributes *ClassificationNodeAttributes `json:"attributes,omitempty"`
+
+	Children []ClassificationNode `json:"children,omitempty"`
+}
+
+// ClassificationNodeAttributes are the start and finish dates of an iteration
+type ClassificationNodeAttributes struct {
+	StartDate  *time.Time `json:"startDate,omitempty"`
+	FinishDate *time.Time `json:"finishDate,omitempty"`
+}
+
+// Flatten returns the node and all of its descendants, parents first.  Each entry is paired with its depth
+// below this node
+func (n ClassificationNode) Flatten() []FlattenedClassificationNode {
+	retval := []FlattenedClassificationNode{}
+
+	var walk func(node ClassificationNode, depth int)
+	walk = func(node ClassificationNode, depth int) {
+		retval = append(retval, FlattenedClassificationNode{Node: node, Depth: depth})
+		for _, child := range node.Children {
+			walk(child, depth+1)
+		}
+	}
+	walk(n, 0)
+
+	return retval
+}
+
+// FlattenedClassificationNode is a node and its depth in the tree
+type FlattenedClassificationNode struct {
+	Node  ClassificationNode
+	Depth int
+}
+
+// Find finds a descendant node by its path relative to this node (like Frontend/Web).  Either slashes
+// or backslashes can separate the path.  The boolean is false if the node doesn't exist
+func (n ClassificationNode) Find(relativePath string) (ClassificationNode, bool) {
+	current := n
+
+	for _, name := range SplitClassificationPath(relativePath) {
+		found := false
+		for _, child := range current.Children {
+			if strings.EqualFold(child.Name, name) {
+				current = child
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return ClassificationNode{}, false
+		}
+	}
+
+	return current, true
+}
+
+// SplitClassificationPath splits an area or iteration path into the names of its nodes.  Either slashes
+// or backslashes can separate the path
+func SplitClassificationPath(relativePath string) []string {
+	retval := []string{}
+	for _, name := range strings.FieldsFunc(relativePath, func(r rune) bool { return r == '/' || r == '\\' }) {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			retval = append(retval, name)
+		}
+	}
+	return retval
+}
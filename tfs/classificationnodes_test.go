@@ -0,0 +1,69 @@
+package tfs_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+func sampleAreaTree() tfs.ClassificationNode {
+	return tfs.ClassificationNode{
+		Name: "Web",
+		Children: []tfs.ClassificationNode{
+			{Name: "Squad Unicorn", Children: []tfs.ClassificationNode{
+				{Name: "Frontend"},
+				{Name: "Backend"},
+			}},
+			{Name: "Squad Narwhal"},
+		},
+	}
+}
+
+// A tree of areas should be flattened with each parent before its children, and the depth of each
+func TestClassificationNodeFlatten_Tree_ReturnsParentsFirstWithDepth(t *testing.T) {
+
+	//	Arrange
+	root := sampleAreaTree()
+	expectedNames := []string{"Web", "Squad Unicorn", "Frontend", "Backend", "Squad Narwhal"}
+	expectedDepths := []int{0, 1, 2, 2, 1}
+
+	//	Act
+	flattened := root.Flatten()
+
+	//	Assert
+	names, depths := []string{}, []int{}
+	for _, item := range flattened {
+		names = append(names, item.Node.Name)
+		depths = append(depths, item.Depth)
+	}
+
+	if !reflect.DeepEqual(expectedNames, names) || !reflect.DeepEqual(expectedDepths, depths) {
+		t.Errorf("Flatten expected %v %v but got %v %v", expectedNames, expectedDepths, names, depths)
+	}
+}
+
+// Areas should be found by path with either separator (and any case), and missing ones shouldn't be
+func TestClassificationNodeFind_EitherSeparator_FindsNode(t *testing.T) {
+
+	//	Arrange
+	root := sampleAreaTree()
+
+	//	Act
+	slash, slashFound := root.Find("squad unicorn/Frontend")
+	backslash, backslashFound := root.Find("Squad Unicorn\\Backend")
+	_, missingFound := root.Find("Squad Unicorn/Mobile")
+
+	//	Assert
+	if !slashFound || slash.Name != "Frontend" {
+		t.Errorf("Expected to find Frontend, but got '%s' (%v)", slash.Name, slashFound)
+	}
+
+	if !backslashFound || backslash.Name != "Backend" {
+		t.Errorf("Expected to find Backend, but got '%s' (%v)", backslash.Name, backslashFound)
+	}
+
+	if missingFound {
+		t.Errorf("Expected not to find Mobile")
+	}
+}
@@ -6,65 +6,2590 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"log"
 	"net/http"
 	"net/url"
 	"path"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
+// continuationTokenHeader is the response header TFS uses when there is another page of results
+const continuationTokenHeader = "x-ms-continuationtoken"
+
+// Transport is the http transport used for every request to TFS.  It can be replaced to record or replay
+// requests (see NewRecorder and NewReplayer)
+var Transport http.RoundTripper = http.DefaultTransport
+
 // Client is a TFS client
 type Client struct {
 	TfsURL            string
 	DefaultCollection string
+
+	// ReleaseURL is the release management root url.  If it is blank, it is derived from TfsURL
+	ReleaseURL string
+}
+
+// GetFormattedURL gets the formatted TFS url to use
+func (client Client) GetFormattedURL(collection, project, area, resource, query string) (string, error) {
+	return client.formatURL(client.TfsURL, collection, project, area, resource, query)
+}
+
+// GetFormattedReleaseURL gets the formatted release management (vsrm) url to use
+func (client Client) GetFormattedReleaseURL(collection, project, area, resource, query string) (string, error) {
+
+	//	If we have an explicit release management url, use it
+	if client.ReleaseURL != "" {
+		return client.formatURL(client.ReleaseURL, collection, project, area, resource, query)
+	}
+
+	//	Otherwise, derive it from the TFS url
+	u, err := url.Parse(client.TfsURL)
+	if err != nil {
+		return "", err
+	}
+
+	//	VSTS hosts release management on a separate 'vsrm' host.
+	//	On-premises TFS hosts it on the same server
+	host := u.Hostname()
+	switch {
+	case strings.EqualFold(host, "dev.azure.com"):
+		host = "vsrm.dev.azure.com"
+	case strings.HasSuffix(strings.ToLower(host), ".visualstudio.com") && !strings.Contains(strings.ToLower(host), ".vsrm."):
+		account := strings.SplitN(host, ".", 2)
+		host = account[0] + ".vsrm." + account[1]
+	}
+
+	if u.Port() != "" {
+		host = host + ":" + u.Port()
+	}
+	u.Host = host
+
+	return client.formatURL(u.String(), collection, project, area, resource, query)
+}
+
+// formatURL formats a url using the given base url
+func (client Client) formatURL(baseURL, collection, project, area, resource, query string) (string, error) {
+
+	//	If the collection is not blank, use it.  Otherwise, use defaults
+	urlcol := client.DefaultCollection
+	if collection != "" {
+		urlcol = collection
+	}
+
+	//	If urlcol is blank, we have a problem.  Return an error:
+	if urlcol == "" {
+		return "", errors.New("TFS collection isn't specified, but is required")
+	}
+
+	//	Parse the base url
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "", err
+	}
+
+	//	Assemble the component parts
+	u.Path = path.Join(u.Path, urlcol, project, "_apis", area, resource)
+
+	//	Add the querystring
+	u.RawQuery = query
+
+	//	Return the full url
+	return u.String(), nil
+}
+
+// GetListOfProjects gets a list of projects for the given collection.  If TFS returns the list in pages,
+// every page is requested
+func (client Client) GetListOfProjects(collection string) (ProjectResponse, error) {
+
+	//	Our return value:
+	retval := ProjectResponse{}
+
+	continuationToken := ""
+	for {
+		//	Format the url
+		query := ""
+		if continuationToken != "" {
+			query = "continuationToken=" + url.QueryEscape(continuationToken)
+		}
+		fullurl, err := client.GetFormattedURL(collection, "", "", "projects", query)
+		if err != nil {
+			apperr := fmt.Errorf("Unable to format url: %s", err)
+			return retval, apperr
+		}
+
+		//	Make a GET reqeust to TFS
+		resp, err := getAPIResponse(fullurl)
+		if err != nil {
+			apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+			return retval, apperr
+		}
+
+		//	If the HTTP status code indicates an error, report it and get out
+		if resp.StatusCode >= 400 {
+			resp.Body.Close()
+			apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+			return retval, apperr
+		}
+
+		//	Decode the return object
+		page := ProjectResponse{}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+			return retval, apperr
+		}
+
+		retval.Projects = append(retval.Projects, page.Projects...)
+		retval.Count = len(retval.Projects)
+
+		//	If there's another page, go get it
+		continuationToken = resp.Header.Get(continuationTokenHeader)
+		if continuationToken == "" {
+			break
+		}
+	}
+
+	return retval, nil
+}
+
+// notFoundError is returned when something can't be found by name
+type notFoundError string
+
+func (e notFoundError) Error() string {
+	return string(e)
+}
+
+// IsNotFound returns true if the error is because something couldn't be found by name
+func IsNotFound(err error) bool {
+	_, ok := err.(notFoundError)
+	return ok
+}
+
+// FindVariableGroup finds a single variable group by name in the given collection and project.  If more than one
+// group matches the name, only an exact match is returned
+func (client Client) FindVariableGroup(collection, project, groupName string) (VariableGroup, error) {
+
+	//	Get the list of Variable groups.  Report any errors
+	vgroups, err := client.GetListOfMatchingVariableGroups(collection, project, groupName)
+	if err != nil {
+		return VariableGroup{}, fmt.Errorf("Finding existing group: %s", err)
+	}
+
+	//	Did we find it?
+	if vgroups.Count < 1 {
+		return VariableGroup{}, notFoundError(fmt.Sprintf("Sorry -- I couldn't find the group '%s'", groupName))
+	}
+
+	//	If we found more than one, look for an exact match
+	if vgroups.Count > 1 {
+		for _, group := range vgroups.VariableGroups {
+			if group.Name == groupName {
+				return group, nil
+			}
+		}
+		return VariableGroup{}, fmt.Errorf("Sorry -- Too many groups match '%s' -- please be more specific", groupName)
+	}
+
+	return vgroups.VariableGroups[0], nil
+}
+
+// GetListOfProcesses gets the list of processes (process templates) projects can be created from
+func (client Client) GetListOfProcesses(collection string) (ProcessesResponse, error) {
+
+	//	Our return value:
+	retval := ProcessesResponse{}
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, "", "process", "processes", "api-version=4.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Request the list of processes
+	resp, err := getAPIResponse(fullurl)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// CreateProject starts creating a project.  Creating a project takes a while -- use WaitForOperation to wait for it
+func (client Client) CreateProject(collection string, project NewProject) (OperationReference, error) {
+
+	//	Our return value:
+	retval := OperationReference{}
+
+	//	Prepare the request body
+	requestBytes := new(bytes.Buffer)
+	err := json.NewEncoder(requestBytes).Encode(&project)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem preparing to create the project: %s", err)
+		return retval, apperr
+	}
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, "", "", "projects", "api-version=4.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Send the request to the API:
+	resp, err := postAPIResponse(fullurl, requestBytes.String())
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error updating information in TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// GetOperation gets the status of a long running operation
+func (client Client) GetOperation(collection, operationID string) (OperationReference, error) {
+
+	//	Our return value:
+	retval := OperationReference{}
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, "", "", path.Join("operations", operationID), "api-version=4.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Request the operation
+	resp, err := getAPIResponse(fullurl)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// DeleteProject starts deleting a project (and everything in it).  Use WaitForOperation to wait for it
+func (client Client) DeleteProject(collection, projectID string) (OperationReference, error) {
+
+	//	Our return value:
+	retval := OperationReference{}
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, "", "", path.Join("projects", projectID), "api-version=4.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Send the request to the API:
+	resp, err := deleteAPIResponse(fullurl)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error updating information in TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// WaitForOperation checks on a long running operation every interval until it's done, or the timeout passes.
+// An operation that fails (or is cancelled) is returned with an error
+func (client Client) WaitForOperation(collection string, operation OperationReference, interval, timeout time.Duration) (OperationReference, error) {
+	deadline := time.Now().Add(timeout)
+
+	for !operation.Done() {
+		if time.Now().After(deadline) {
+			return operation, fmt.Errorf("The operation %s didn't finish within %s (it's %s)", operation.ID, timeout, operation.Status)
+		}
+		time.Sleep(interval)
+
+		current, err := client.GetOperation(collection, operation.ID)
+		if err != nil {
+			return operation, err
+		}
+		operation = current
+	}
+
+	if operation.Status != OperationSucceeded {
+		return operation, fmt.Errorf("The operation %s %s: %s", operation.ID, operation.Status, operation.ResultMessage)
+	}
+
+	return operation, nil
+}
+
+// GetListOfProjectCollections gets a list of the project collections on the server.  Only on-premises TFS
+// has more than one collection; VSTS doesn't support this call
+func (client Client) GetListOfProjectCollections() (ProjectCollectionResponse, error) {
+
+	//	Our return value:
+	retval := ProjectCollectionResponse{}
+
+	//	Format the url (collections aren't under a collection, so it's the server url)
+	u, err := url.Parse(client.TfsURL)
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+	u.Path = path.Join(u.Path, "_apis", "projectcollections")
+	fullurl := u.String()
+
+	//	Make a GET reqeust to TFS
+	resp, err := getAPIResponse(fullurl)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// GetListOfVariableGroups gets a list of variable groups for the given collection and project
+func (client Client) GetListOfVariableGroups(collection, project string) (VariableGroupsResponse, error) {
+	return client.GetListOfMatchingVariableGroups(collection, project, "*")
+}
+
+// GetListOfMatchingVariableGroups gets a list of variable groups for the given collection, project, and group name.
+// If TFS returns the list in pages, every page is requested
+func (client Client) GetListOfMatchingVariableGroups(collection, project, groupName string) (VariableGroupsResponse, error) {
+
+	//	Our return value:
+	retval := VariableGroupsResponse{}
+
+	continuationToken := ""
+	for {
+		//	Format the url
+		escapedGroup := url.QueryEscape(groupName)
+		formattedQuery := fmt.Sprintf("groupName=%s&actionFilter=use&top=50&api-version=4.1-preview.1", escapedGroup)
+		if continuationToken != "" {
+			formattedQuery += "&continuationToken=" + url.QueryEscape(continuationToken)
+		}
+		fullurl, err := client.GetFormattedURL(collection, project, "distributedtask", "variablegroups", formattedQuery)
+		if err != nil {
+			apperr := fmt.Errorf("Unable to format url: %s", err)
+			return retval, apperr
+		}
+
+		//	Request a list of variable groups
+		resp, err := getAPIResponse(fullurl)
+		if err != nil {
+			apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+			return retval, apperr
+		}
+
+		//	If the HTTP status code indicates an error, report it and get out
+		if resp.StatusCode >= 400 {
+			resp.Body.Close()
+			apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+			return retval, apperr
+		}
+
+		//	Decode the return object
+		page := VariableGroupsResponse{}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+			return retval, apperr
+		}
+
+		retval.VariableGroups = append(retval.VariableGroups, page.VariableGroups...)
+		retval.Count = len(retval.VariableGroups)
+
+		//	If there's another page, go get it
+		continuationToken = resp.Header.Get(continuationTokenHeader)
+		if continuationToken == "" {
+			break
+		}
+	}
+
+	return retval, nil
+}
+
+// CreateVariableGroup creates a variable group in the given collection and project, and returns the new group
+func (client Client) CreateVariableGroup(collection, project string, newGroup VariableGroup) (VariableGroup, error) {
+
+	//	Our return value:
+	retval := VariableGroup{}
+
+	//	Prepare the request body
+	requestBytes := new(bytes.Buffer)
+	err := json.NewEncoder(requestBytes).Encode(&newGroup)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem preparing to create the group: %s", err)
+		return retval, apperr
+	}
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, project, "distributedtask", "variablegroups", "api-version=4.1-preview.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Send the request to the API:
+	resp, err := postAPIResponse(fullurl, requestBytes.String())
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// UpdateVariableGroup replaces a variable group (and all of its variables), and returns the updated group
+func (client Client) UpdateVariableGroup(collection, project string, group VariableGroup) (VariableGroup, error) {
+
+	//	Our return value:
+	retval := VariableGroup{}
+
+	//	Prepare the request body
+	requestBytes := new(bytes.Buffer)
+	err := json.NewEncoder(requestBytes).Encode(&group)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem preparing to update the group: %s", err)
+		return retval, apperr
+	}
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, project, "distributedtask", path.Join("variablegroups", strconv.Itoa(group.ID)), "api-version=4.1-preview.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Send the request to the API:
+	resp, err := putAPIResponse(fullurl, requestBytes.String())
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error updating information in TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// DeleteVariableGroup deletes a variable group
+func (client Client) DeleteVariableGroup(collection, project string, groupID int) error {
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, project, "distributedtask", path.Join("variablegroups", strconv.Itoa(groupID)), "api-version=4.1-preview.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return apperr
+	}
+
+	//	Send the request to the API:
+	resp, err := deleteAPIResponse(fullurl)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error updating information in TFS: %s", resp.Status)
+		return apperr
+	}
+
+	return nil
+}
+
+// GetListOfServiceEndpoints gets a list of service endpoints for the given collection and project
+func (client Client) GetListOfServiceEndpoints(collection, project string) (ServiceEndpointsResponse, error) {
+
+	//	Our return value:
+	retval := ServiceEndpointsResponse{}
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, project, "distributedtask", "serviceendpoints", "api-version=4.1-preview.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Request a list of service endpoints
+	resp, err := getAPIResponse(fullurl)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// CreateServiceEndpoint creates a service endpoint in the given collection and project
+func (client Client) CreateServiceEndpoint(collection, project string, newEndpoint ServiceEndpoint) (ServiceEndpoint, error) {
+
+	//	Our return value:
+	retval := ServiceEndpoint{}
+
+	//	Prepare the request body
+	requestBytes := new(bytes.Buffer)
+	err := json.NewEncoder(requestBytes).Encode(&newEndpoint)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem preparing to create the service endpoint: %s", err)
+		return retval, apperr
+	}
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, project, "distributedtask", "serviceendpoints", "api-version=4.1-preview.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Send the request to the API:
+	resp, err := postAPIResponse(fullurl, requestBytes.String())
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// GetListOfAgentPools gets a list of agent pools for the given collection
+func (client Client) GetListOfAgentPools(collection string) (AgentPoolsResponse, error) {
+
+	//	Our return value:
+	retval := AgentPoolsResponse{}
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, "", "distributedtask", "pools", "api-version=4.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Request a list of agent pools
+	resp, err := getAPIResponse(fullurl)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// GetListOfAgents gets a list of agents (including their capabilities and current job) in the given agent pool
+func (client Client) GetListOfAgents(collection string, poolID int) (AgentsResponse, error) {
+
+	//	Our return value:
+	retval := AgentsResponse{}
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, "", "distributedtask", path.Join("pools", strconv.Itoa(poolID), "agents"), "includeCapabilities=true&includeAssignedRequest=true&api-version=4.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Request a list of agents
+	resp, err := getAPIResponse(fullurl)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// SetAgentEnabled enables or disables an agent.  A disabled agent finishes its current job but won't be given new ones
+func (client Client) SetAgentEnabled(collection string, poolID, agentID int, enabled bool) error {
+
+	//	Prepare the request body
+	requestBytes := new(bytes.Buffer)
+	err := json.NewEncoder(requestBytes).Encode(map[string]interface{}{
+		"id":      agentID,
+		"enabled": enabled,
+	})
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem preparing to update the agent: %s", err)
+		return apperr
+	}
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, "", "distributedtask", path.Join("pools", strconv.Itoa(poolID), "agents", strconv.Itoa(agentID)), "api-version=4.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return apperr
+	}
+
+	//	Send the request to the API:
+	resp, err := patchAPIResponse(fullurl, requestBytes.String())
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error updating information in TFS: %s", resp.Status)
+		return apperr
+	}
+
+	return nil
+}
+
+// GetListOfBuilds gets a list of builds for the given collection and project, using the given query filters.
+// If TFS returns the list in pages, pages are requested until query.Top builds are found (or every build, if
+// query.Top is zero)
+func (client Client) GetListOfBuilds(collection, project string, query BuildQuery) (BuildsResponse, error) {
+
+	//	Our return value:
+	retval := BuildsResponse{}
+
+	//	Build the querystring from the filters we were given
+	params := url.Values{}
+	if len(query.Definitions) > 0 {
+		ids := []string{}
+		for _, id := range query.Definitions {
+			ids = append(ids, strconv.Itoa(id))
+		}
+		params.Set("definitions", strings.Join(ids, ","))
+	}
+	if query.BranchName != "" {
+		params.Set("branchName", query.BranchName)
+	}
+	if query.RequestedFor != "" {
+		params.Set("requestedFor", query.RequestedFor)
+	}
+	if query.StatusFilter != "" {
+		params.Set("statusFilter", query.StatusFilter)
+	}
+	if query.ResultFilter != "" {
+		params.Set("resultFilter", query.ResultFilter)
+	}
+	if !query.MinTime.IsZero() {
+		params.Set("minTime", query.MinTime.UTC().Format(time.RFC3339))
+	}
+	if !query.MaxTime.IsZero() {
+		params.Set("maxTime", query.MaxTime.UTC().Format(time.RFC3339))
+	}
+	params.Set("queryOrder", "finishTimeDescending")
+	params.Set("api-version", "4.1")
+
+	continuationToken := ""
+	for {
+		//	Only ask for as many builds as we still need
+		if query.Top > 0 {
+			params.Set("$top", strconv.Itoa(query.Top-len(retval.Builds)))
+		}
+		if continuationToken != "" {
+			params.Set("continuationToken", continuationToken)
+		}
+
+		//	Format the url
+		fullurl, err := client.GetFormattedURL(collection, project, "build", "builds", params.Encode())
+		if err != nil {
+			apperr := fmt.Errorf("Unable to format url: %s", err)
+			return retval, apperr
+		}
+
+		//	Request a list of builds
+		resp, err := getAPIResponse(fullurl)
+		if err != nil {
+			apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+			return retval, apperr
+		}
+
+		//	If the HTTP status code indicates an error, report it and get out
+		if resp.StatusCode >= 400 {
+			resp.Body.Close()
+			apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+			return retval, apperr
+		}
+
+		//	Decode the return object
+		page := BuildsResponse{}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+			return retval, apperr
+		}
+
+		retval.Builds = append(retval.Builds, page.Builds...)
+		retval.Count = len(retval.Builds)
+
+		//	If there's another page (and we need it), go get it
+		continuationToken = resp.Header.Get(continuationTokenHeader)
+		if continuationToken == "" || (query.Top > 0 && len(retval.Builds) >= query.Top) {
+			break
+		}
+	}
+
+	return retval, nil
+}
+
+// GetListOfBuildDefinitions gets a list of build definitions for the given collection and project.
+// If name is not blank, only definitions matching the name are returned
+func (client Client) GetListOfBuildDefinitions(collection, project, name string) (BuildDefinitionsResponse, error) {
+
+	//	Our return value:
+	retval := BuildDefinitionsResponse{}
+
+	//	Format the url
+	params := url.Values{}
+	if name != "" {
+		params.Set("name", name)
+	}
+	params.Set("api-version", "4.1")
+	fullurl, err := client.GetFormattedURL(collection, project, "build", "definitions", params.Encode())
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Request a list of build definitions
+	resp, err := getAPIResponse(fullurl)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// GetBuildDefinition gets a single build definition
+func (client Client) GetBuildDefinition(collection, project string, definitionID int) (BuildDefinition, error) {
+
+	//	Our return value:
+	retval := BuildDefinition{}
+
+	//	Get the raw definition
+	raw, err := client.ExportBuildDefinition(collection, project, definitionID)
+	if err != nil {
+		return retval, err
+	}
+
+	//	Decode the return object
+	err = json.Unmarshal(raw, &retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// ExportBuildDefinition gets the complete JSON for a single build definition, exactly as TFS returns it
+func (client Client) ExportBuildDefinition(collection, project string, definitionID int) ([]byte, error) {
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, project, "build", path.Join("definitions", strconv.Itoa(definitionID)), "api-version=4.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return nil, apperr
+	}
+
+	//	Request the build definition
+	resp, err := getAPIResponse(fullurl)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return nil, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+		return nil, apperr
+	}
+
+	//	Read the return object
+	retval, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem reading the response from TFS: %s", err)
+		return nil, apperr
+	}
+
+	return retval, nil
+}
+
+// GetListOfGitRepositories gets a list of git repositories for the given collection and project
+func (client Client) GetListOfGitRepositories(collection, project string) (GitRepositoriesResponse, error) {
+
+	//	Our return value:
+	retval := GitRepositoriesResponse{}
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, project, "git", "repositories", "api-version=4.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Request a list of repositories
+	resp, err := getAPIResponse(fullurl)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// GetGitRepository gets a single git repository by name or id
+func (client Client) GetGitRepository(collection, project, repository string) (GitRepository, error) {
+
+	//	Our return value:
+	retval := GitRepository{}
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, project, "git", path.Join("repositories", repository), "api-version=4.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Request the repository
+	resp, err := getAPIResponse(fullurl)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// GetListOfGitRefs gets a list of refs in a git repository.  The filter is a ref name prefix without 'refs/' (like 'heads/')
+func (client Client) GetListOfGitRefs(collection, project, repositoryID, filter string) (GitRefsResponse, error) {
+
+	//	Our return value:
+	retval := GitRefsResponse{}
+
+	//	Format the url
+	params := url.Values{}
+	if filter != "" {
+		params.Set("filter", filter)
+	}
+	params.Set("api-version", "4.1")
+	fullurl, err := client.GetFormattedURL(collection, project, "git", path.Join("repositories", repositoryID, "refs"), params.Encode())
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Request a list of refs
+	resp, err := getAPIResponse(fullurl)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// GetListOfGitBranchStats gets the ahead/behind counts and latest commit for every branch in a git repository,
+// compared to the given base branch
+func (client Client) GetListOfGitBranchStats(collection, project, repositoryID, baseBranch string) (GitBranchStatsResponse, error) {
+
+	//	Our return value:
+	retval := GitBranchStatsResponse{}
+
+	//	Format the url
+	params := url.Values{}
+	if baseBranch != "" {
+		params.Set("baseVersionDescriptor.version", strings.TrimPrefix(baseBranch, "refs/heads/"))
+		params.Set("baseVersionDescriptor.versionType", "branch")
+	}
+	params.Set("api-version", "4.1")
+	fullurl, err := client.GetFormattedURL(collection, project, "git", path.Join("repositories", repositoryID, "stats", "branches"), params.Encode())
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Request the branch statistics
+	resp, err := getAPIResponse(fullurl)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// GetGitItem gets a single file (including its content) from a git repository.  If branch is blank,
+// the default branch is used
+func (client Client) GetGitItem(collection, project, repositoryID, itemPath, branch string) (GitItem, error) {
+
+	//	Our return value:
+	retval := GitItem{}
+
+	//	Format the url
+	params := url.Values{}
+	params.Set("path", itemPath)
+	params.Set("includeContent", "true")
+	params.Set("$format", "json")
+	if branch != "" {
+		params.Set("versionDescriptor.version", strings.TrimPrefix(branch, "refs/heads/"))
+		params.Set("versionDescriptor.versionType", "branch")
+	}
+	params.Set("api-version", "4.1")
+	fullurl, err := client.GetFormattedURL(collection, project, "git", path.Join("repositories", repositoryID, "items"), params.Encode())
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Request the item
+	resp, err := getAPIResponse(fullurl)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// GetListOfReleaseDefinitions gets a list of release definitions (including environments and variables)
+// for the given collection and project.  If searchText is not blank, only definitions with names containing it are returned
+func (client Client) GetListOfReleaseDefinitions(collection, project, searchText string) (ReleaseDefinitionsResponse, error) {
+
+	//	Our return value:
+	retval := ReleaseDefinitionsResponse{}
+
+	//	Format the url
+	params := url.Values{}
+	if searchText != "" {
+		params.Set("searchText", searchText)
+	}
+	params.Set("$expand", "environments,variables")
+	params.Set("api-version", "4.1-preview.3")
+	fullurl, err := client.GetFormattedReleaseURL(collection, project, "release", "definitions", params.Encode())
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Request a list of release definitions
+	resp, err := getAPIResponse(fullurl)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// GetReleaseDefinition gets a single release definition
+func (client Client) GetReleaseDefinition(collection, project string, definitionID int) (ReleaseDefinition, error) {
+
+	//	Our return value:
+	retval := ReleaseDefinition{}
+
+	//	Get the raw definition
+	raw, err := client.ExportReleaseDefinition(collection, project, definitionID)
+	if err != nil {
+		return retval, err
+	}
+
+	//	Decode the return object
+	err = json.Unmarshal(raw, &retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// ExportReleaseDefinition gets the complete JSON for a single release definition, exactly as TFS returns it
+func (client Client) ExportReleaseDefinition(collection, project string, definitionID int) ([]byte, error) {
+
+	//	Format the url
+	fullurl, err := client.GetFormattedReleaseURL(collection, project, "release", path.Join("definitions", strconv.Itoa(definitionID)), "api-version=4.1-preview.3")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return nil, apperr
+	}
+
+	//	Request the release definition
+	resp, err := getAPIResponse(fullurl)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return nil, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+		return nil, apperr
+	}
+
+	//	Read the return object
+	retval, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem reading the response from TFS: %s", err)
+		return nil, apperr
+	}
+
+	return retval, nil
+}
+
+// SetReleaseDefinitionVariable adds or updates a single release variable.  If environment is blank,
+// the release-level variable is set.  Otherwise, the variable in the named environment is set
+func (client Client) SetReleaseDefinitionVariable(collection, project string, definitionID int, environment, name string, variable Variable) error {
+	return client.updateReleaseDefinitionVariables(collection, project, definitionID, environment, func(variables map[string]interface{}) error {
+		variables[name] = variable
+		return nil
+	})
+}
+
+// DeleteReleaseDefinitionVariable removes a single release variable.  If environment is blank,
+// the release-level variable is removed.  Otherwise, the variable in the named environment is removed
+func (client Client) DeleteReleaseDefinitionVariable(collection, project string, definitionID int, environment, name string) error {
+	return client.updateReleaseDefinitionVariables(collection, project, definitionID, environment, func(variables map[string]interface{}) error {
+		if _, ok := variables[name]; !ok {
+			return fmt.Errorf("The variable '%s' doesn't exist", name)
+		}
+		delete(variables, name)
+		return nil
+	})
+}
+
+// updateReleaseDefinitionVariables applies the given change to the release-level (or environment-level) variables
+// of a release definition.  Everything else in the definition (including secret variables, which TFS doesn't
+// return values for) is sent back untouched
+func (client Client) updateReleaseDefinitionVariables(collection, project string, definitionID int, environment string, change func(map[string]interface{}) error) error {
+
+	//	Get the complete definition
+	raw, err := client.ExportReleaseDefinition(collection, project, definitionID)
+	if err != nil {
+		return err
+	}
+
+	//	(UseNumber keeps ids and revisions from being turned into floats)
+	definition := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	err = decoder.Decode(&definition)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return apperr
+	}
+
+	//	Find the place the variables live
+	owner := definition
+	if environment != "" {
+		owner = nil
+		environments, _ := definition["environments"].([]interface{})
+		for _, item := range environments {
+			env, ok := item.(map[string]interface{})
+			if ok && strings.EqualFold(fmt.Sprint(env["name"]), environment) {
+				owner = env
+			}
+		}
+
+		if owner == nil {
+			return fmt.Errorf("The release definition doesn't have an environment named '%s'", environment)
+		}
+	}
+
+	variables, ok := owner["variables"].(map[string]interface{})
+	if !ok {
+		variables = map[string]interface{}{}
+		owner["variables"] = variables
+	}
+
+	//	Make the change
+	if err := change(variables); err != nil {
+		return err
+	}
+
+	//	Prepare the request body
+	requestBytes := new(bytes.Buffer)
+	err = json.NewEncoder(requestBytes).Encode(&definition)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem preparing to update the release definition: %s", err)
+		return apperr
+	}
+
+	//	Format the url
+	fullurl, err := client.GetFormattedReleaseURL(collection, project, "release", "definitions", "api-version=4.1-preview.3")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return apperr
+	}
+
+	//	Send the request to the API:
+	resp, err := putAPIResponse(fullurl, requestBytes.String())
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error updating information in TFS: %s", resp.Status)
+		return apperr
+	}
+
+	return nil
+}
+
+// GetListOfPullRequests gets a list of pull requests for the given collection and project, using the given query filters
+func (client Client) GetListOfPullRequests(collection, project string, query PullRequestQuery) (PullRequestsResponse, error) {
+
+	//	Our return value:
+	retval := PullRequestsResponse{}
+
+	//	Format the url
+	params := url.Values{}
+	if query.RepositoryID != "" {
+		params.Set("searchCriteria.repositoryId", query.RepositoryID)
+	}
+	if query.Status != "" {
+		params.Set("searchCriteria.status", query.Status)
+	}
+	if query.CreatorID != "" {
+		params.Set("searchCriteria.creatorId", query.CreatorID)
+	}
+	if query.ReviewerID != "" {
+		params.Set("searchCriteria.reviewerId", query.ReviewerID)
+	}
+	if query.Top > 0 {
+		params.Set("$top", strconv.Itoa(query.Top))
+	}
+	params.Set("api-version", "4.1")
+	fullurl, err := client.GetFormattedURL(collection, project, "git", "pullrequests", params.Encode())
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Request a list of pull requests
+	resp, err := getAPIResponse(fullurl)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// GetPullRequest gets a single pull request (including its reviewers and their votes)
+func (client Client) GetPullRequest(collection, project string, pullRequestID int) (PullRequest, error) {
+
+	//	Our return value:
+	retval := PullRequest{}
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, project, "git", path.Join("pullrequests", strconv.Itoa(pullRequestID)), "api-version=4.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Request the pull request
+	resp, err := getAPIResponse(fullurl)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// CreatePullRequest creates a pull request in the given git repository
+func (client Client) CreatePullRequest(collection, project, repositoryID string, newPullRequest PullRequest) (PullRequest, error) {
+
+	//	Our return value:
+	retval := PullRequest{}
+
+	//	Prepare the request body
+	requestBytes := new(bytes.Buffer)
+	err := json.NewEncoder(requestBytes).Encode(&newPullRequest)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem preparing to create the pull request: %s", err)
+		return retval, apperr
+	}
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, project, "git", path.Join("repositories", repositoryID, "pullrequests"), "api-version=4.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Send the request to the API:
+	resp, err := postAPIResponse(fullurl, requestBytes.String())
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error updating information in TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// SetPullRequestVote sets the vote of a reviewer on a pull request.  If the reviewer isn't on the pull request yet,
+// they are added
+func (client Client) SetPullRequestVote(collection, project, repositoryID string, pullRequestID int, reviewerID string, vote int) (IdentityRefWithVote, error) {
+
+	//	Our return value:
+	retval := IdentityRefWithVote{}
+
+	//	Prepare the request body
+	requestBytes := new(bytes.Buffer)
+	err := json.NewEncoder(requestBytes).Encode(map[string]interface{}{"vote": vote})
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem preparing to vote on the pull request: %s", err)
+		return retval, apperr
+	}
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, project, "git", path.Join("repositories", repositoryID, "pullrequests", strconv.Itoa(pullRequestID), "reviewers", reviewerID), "api-version=4.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Send the request to the API:
+	resp, err := putAPIResponse(fullurl, requestBytes.String())
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error updating information in TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// GetListOfIdentities searches for users (or groups) by display name, account name or email address
+func (client Client) GetListOfIdentities(collection, filterValue string) (IdentitiesResponse, error) {
+
+	//	Our return value:
+	retval := IdentitiesResponse{}
+
+	//	Format the url
+	params := url.Values{}
+	params.Set("searchFilter", "General")
+	params.Set("filterValue", filterValue)
+	params.Set("api-version", "4.1")
+	fullurl, err := client.GetFormattedURL(collection, "", "", "identities", params.Encode())
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Request a list of identities
+	resp, err := getAPIResponse(fullurl)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// GetConnectionData gets information about the connection to TFS, including the authenticated user
+func (client Client) GetConnectionData(collection string) (ConnectionData, error) {
+
+	//	Our return value:
+	retval := ConnectionData{}
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, "", "", "connectionData", "")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Request the connection data
+	resp, err := getAPIResponse(fullurl)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// GetListOfPolicyConfigurations gets a list of policy configurations (branch policies) for the given collection and project
+func (client Client) GetListOfPolicyConfigurations(collection, project string) (PolicyConfigurationsResponse, error) {
+
+	//	Our return value:
+	retval := PolicyConfigurationsResponse{}
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, project, "policy", "configurations", "api-version=4.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Request a list of policy configurations
+	resp, err := getAPIResponse(fullurl)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// CreatePolicyConfiguration creates a policy configuration (branch policy) in the given collection and project
+func (client Client) CreatePolicyConfiguration(collection, project string, newPolicy PolicyConfiguration) (PolicyConfiguration, error) {
+
+	//	Our return value:
+	retval := PolicyConfiguration{}
+
+	//	Prepare the request body
+	requestBytes := new(bytes.Buffer)
+	err := json.NewEncoder(requestBytes).Encode(&newPolicy)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem preparing to create the policy: %s", err)
+		return retval, apperr
+	}
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, project, "policy", "configurations", "api-version=4.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Send the request to the API:
+	resp, err := postAPIResponse(fullurl, requestBytes.String())
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// QueryByWiql runs a WIQL query in the given collection and project.  If top is more than zero,
+// only that many work items are returned
+func (client Client) QueryByWiql(collection, project, wiql string, top int) (WiqlResult, error) {
+
+	//	Our return value:
+	retval := WiqlResult{}
+
+	//	Prepare the request body
+	requestBytes := new(bytes.Buffer)
+	err := json.NewEncoder(requestBytes).Encode(map[string]string{"query": wiql})
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem preparing to run the query: %s", err)
+		return retval, apperr
+	}
+
+	//	Format the url
+	params := url.Values{}
+	if top > 0 {
+		params.Set("$top", strconv.Itoa(top))
+	}
+	params.Set("api-version", "4.1")
+	fullurl, err := client.GetFormattedURL(collection, project, "wit", "wiql", params.Encode())
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Send the request to the API:
+	resp, err := postAPIResponse(fullurl, requestBytes.String())
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// QueryByID runs a saved work item query in the given collection and project
+func (client Client) QueryByID(collection, project, queryID string) (WiqlResult, error) {
+
+	//	Our return value:
+	retval := WiqlResult{}
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, project, "wit", path.Join("wiql", queryID), "api-version=4.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Request the query results
+	resp, err := getAPIResponse(fullurl)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// GetQuery gets a saved work item query (or query folder) by id or path (like 'Shared Queries/Current sprint')
+func (client Client) GetQuery(collection, project, queryPath string) (QueryHierarchyItem, error) {
+
+	//	Our return value:
+	retval := QueryHierarchyItem{}
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, project, "wit", path.Join("queries", queryPath), "$expand=wiql&api-version=4.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Request the query
+	resp, err := getAPIResponse(fullurl)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// GetWorkItem gets a single work item, including all of its fields and relations
+func (client Client) GetWorkItem(collection, project string, id int) (WorkItem, error) {
+
+	//	Our return value:
+	retval := WorkItem{}
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, project, "wit", path.Join("workitems", strconv.Itoa(id)), "$expand=relations&api-version=4.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Request the work item
+	resp, err := getAPIResponse(fullurl)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// GetWorkItems gets a list of work items (in the order of the ids given).  If fields is empty, all fields are returned.
+// The work items are fetched in batches of 200, the most TFS allows in one request
+func (client Client) GetWorkItems(collection, project string, ids []int, fields []string) (WorkItemsResponse, error) {
+
+	//	Our return value:
+	retval := WorkItemsResponse{}
+
+	for start := 0; start < len(ids); start += 200 {
+		end := start + 200
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		batch := []string{}
+		for _, id := range ids[start:end] {
+			batch = append(batch, strconv.Itoa(id))
+		}
+
+		//	Format the url
+		params := url.Values{}
+		params.Set("ids", strings.Join(batch, ","))
+		if len(fields) > 0 {
+			params.Set("fields", strings.Join(fields, ","))
+		}
+		params.Set("errorPolicy", "omit")
+		params.Set("api-version", "4.1")
+		fullurl, err := client.GetFormattedURL(collection, project, "wit", "workitems", params.Encode())
+		if err != nil {
+			apperr := fmt.Errorf("Unable to format url: %s", err)
+			return retval, apperr
+		}
+
+		//	Request the batch of work items
+		resp, err := getAPIResponse(fullurl)
+		if err != nil {
+			apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+			return retval, apperr
+		}
+
+		//	If the HTTP status code indicates an error, report it and get out
+		if resp.StatusCode >= 400 {
+			resp.Body.Close()
+			apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+			return retval, apperr
+		}
+
+		//	Decode the return object
+		page := WorkItemsResponse{}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+			return retval, apperr
+		}
+
+		//	(With errorPolicy=omit, missing work items come back as nulls -- skip them)
+		for _, item := range page.WorkItems {
+			if item.ID != 0 {
+				retval.WorkItems = append(retval.WorkItems, item)
+			}
+		}
+	}
+
+	retval.Count = len(retval.WorkItems)
+	return retval, nil
+}
+
+// CreateWorkItem creates a work item of the given type (like Task or User Story) from a list of JSON Patch operations
+func (client Client) CreateWorkItem(collection, project, workItemType string, patch []JSONPatchOperation) (WorkItem, error) {
+
+	//	Our return value:
+	retval := WorkItem{}
+
+	//	Prepare the request body
+	requestBytes := new(bytes.Buffer)
+	err := json.NewEncoder(requestBytes).Encode(patch)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem preparing to create the work item: %s", err)
+		return retval, apperr
+	}
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, project, "wit", path.Join("workitems", "$"+workItemType), "api-version=4.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Send the request to the API:
+	resp, err := postJSONPatchAPIResponse(fullurl, requestBytes.String())
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// UpdateWorkItem updates a work item from a list of JSON Patch operations
+func (client Client) UpdateWorkItem(collection, project string, id int, patch []JSONPatchOperation) (WorkItem, error) {
+
+	//	Our return value:
+	retval := WorkItem{}
+
+	//	Prepare the request body
+	requestBytes := new(bytes.Buffer)
+	err := json.NewEncoder(requestBytes).Encode(patch)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem preparing to update the work item: %s", err)
+		return retval, apperr
+	}
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, project, "wit", path.Join("workitems", strconv.Itoa(id)), "api-version=4.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Send the request to the API:
+	resp, err := patchJSONPatchAPIResponse(fullurl, requestBytes.String())
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error updating information in TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// GetWorkItemURL gets the API url for a work item.  This is the url used when linking work items
+func (client Client) GetWorkItemURL(collection string, id int) (string, error) {
+	return client.GetFormattedURL(collection, "", "wit", path.Join("workItems", strconv.Itoa(id)), "")
+}
+
+// GetListOfTaskGroups gets a list of task groups (the latest version of each) for the given collection and project
+func (client Client) GetListOfTaskGroups(collection, project string) (TaskGroupsResponse, error) {
+
+	//	Our return value:
+	retval := TaskGroupsResponse{}
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, project, "distributedtask", "taskgroups", "api-version=4.1-preview.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Request a list of task groups
+	resp, err := getAPIResponse(fullurl)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// GetTaskGroupVersions gets every version of a single task group
+func (client Client) GetTaskGroupVersions(collection, project, taskGroupID string) (TaskGroupsResponse, error) {
+
+	//	Our return value:
+	retval := TaskGroupsResponse{}
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, project, "distributedtask", path.Join("taskgroups", taskGroupID), "api-version=4.1-preview.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Request the task group versions
+	resp, err := getAPIResponse(fullurl)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// CreateTaskGroup creates a task group in the given collection and project
+func (client Client) CreateTaskGroup(collection, project string, newTaskGroup TaskGroup) (TaskGroup, error) {
+
+	//	Our return value:
+	retval := TaskGroup{}
+
+	//	Prepare the request body
+	requestBytes := new(bytes.Buffer)
+	err := json.NewEncoder(requestBytes).Encode(&newTaskGroup)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem preparing to create the task group: %s", err)
+		return retval, apperr
+	}
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, project, "distributedtask", "taskgroups", "api-version=4.1-preview.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Send the request to the API:
+	resp, err := postAPIResponse(fullurl, requestBytes.String())
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// UpdateTaskGroup updates a task group (or adds a new version of it) in the given collection and project
+func (client Client) UpdateTaskGroup(collection, project string, taskGroup TaskGroup) (TaskGroup, error) {
+
+	//	Our return value:
+	retval := TaskGroup{}
+
+	//	Prepare the request body
+	requestBytes := new(bytes.Buffer)
+	err := json.NewEncoder(requestBytes).Encode(&taskGroup)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem preparing to update the task group: %s", err)
+		return retval, apperr
+	}
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, project, "distributedtask", path.Join("taskgroups", taskGroup.ID), "api-version=4.1-preview.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Send the request to the API:
+	resp, err := putAPIResponse(fullurl, requestBytes.String())
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error updating information in TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// GetListOfSecureFiles gets a list of secure files for the given collection and project
+func (client Client) GetListOfSecureFiles(collection, project string) (SecureFilesResponse, error) {
+
+	//	Our return value:
+	retval := SecureFilesResponse{}
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, project, "distributedtask", "securefiles", "api-version=4.1-preview.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Request a list of secure files
+	resp, err := getAPIResponse(fullurl)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// UploadSecureFile uploads a new secure file with the given name and contents
+func (client Client) UploadSecureFile(collection, project, name string, content io.Reader) (SecureFile, error) {
+
+	//	Our return value:
+	retval := SecureFile{}
+
+	//	Format the url
+	params := url.Values{}
+	params.Set("name", name)
+	params.Set("api-version", "4.1-preview.1")
+	fullurl, err := client.GetFormattedURL(collection, project, "distributedtask", "securefiles", params.Encode())
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Send the request to the API:
+	resp, err := uploadAPIResponse(fullurl, content)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error updating information in TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// UpdateSecureFile updates the name and properties of a secure file
+func (client Client) UpdateSecureFile(collection, project string, secureFile SecureFile) (SecureFile, error) {
+
+	//	Our return value:
+	retval := SecureFile{}
+
+	//	Prepare the request body
+	requestBytes := new(bytes.Buffer)
+	err := json.NewEncoder(requestBytes).Encode(&secureFile)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem preparing to update the secure file: %s", err)
+		return retval, apperr
+	}
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, project, "distributedtask", path.Join("securefiles", secureFile.ID), "api-version=4.1-preview.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Send the request to the API:
+	resp, err := patchAPIResponse(fullurl, requestBytes.String())
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error updating information in TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// DeleteSecureFile deletes a secure file
+func (client Client) DeleteSecureFile(collection, project, secureFileID string) error {
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, project, "distributedtask", path.Join("securefiles", secureFileID), "api-version=4.1-preview.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return apperr
+	}
+
+	//	Send the request to the API:
+	resp, err := deleteAPIResponse(fullurl)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error updating information in TFS: %s", resp.Status)
+		return apperr
+	}
+
+	return nil
+}
+
+// GetPipelinePermissions gets the pipelines authorized to use a protected resource.  The resource type is
+// something like securefile, variablegroup, endpoint or queue
+func (client Client) GetPipelinePermissions(collection, project, resourceType, resourceID string) (ResourcePipelinePermissions, error) {
+
+	//	Our return value:
+	retval := ResourcePipelinePermissions{}
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, project, "pipelines", path.Join("pipelinePermissions", resourceType, resourceID), "api-version=5.1-preview.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Request the pipeline permissions
+	resp, err := getAPIResponse(fullurl)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// SetPipelinePermissions authorizes (or unauthorizes) pipelines to use a protected resource.  Only the pipelines
+// in the given permissions are changed
+func (client Client) SetPipelinePermissions(collection, project, resourceType, resourceID string, permissions ResourcePipelinePermissions) (ResourcePipelinePermissions, error) {
+
+	//	Our return value:
+	retval := ResourcePipelinePermissions{}
+
+	//	Prepare the request body
+	requestBytes := new(bytes.Buffer)
+	err := json.NewEncoder(requestBytes).Encode(&permissions)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem preparing to update the pipeline permissions: %s", err)
+		return retval, apperr
+	}
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, project, "pipelines", path.Join("pipelinePermissions", resourceType, resourceID), "api-version=5.1-preview.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Send the request to the API:
+	resp, err := patchAPIResponse(fullurl, requestBytes.String())
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error updating information in TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// GetListOfRoleAssignments gets the security role assignments for a resource.  The scope is something like
+// distributedtask.variablegroup, and the resource id is specific to the scope
+func (client Client) GetListOfRoleAssignments(collection, scopeID, resourceID string) (RoleAssignmentsResponse, error) {
+
+	//	Our return value:
+	retval := RoleAssignmentsResponse{}
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, "", "securityroles", path.Join("scopes", scopeID, "roleassignments", "resources", resourceID), "api-version=5.0-preview.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Request the role assignments
+	resp, err := getAPIResponse(fullurl)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// GetListOfTeams gets a list of teams for the given collection and project
+func (client Client) GetListOfTeams(collection, project string) (TeamsResponse, error) {
+
+	//	Our return value:
+	retval := TeamsResponse{}
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, "", "projects", path.Join(project, "teams"), "api-version=4.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
+	}
+
+	//	Request a list of teams
+	resp, err := getAPIResponse(fullurl)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
 }
 
-// GetFormattedURL gets the formatted TFS url to use
-func (client Client) GetFormattedURL(collection, project, area, resource, query string) (string, error) {
+// CreateTeam creates a team in the given collection and project
+func (client Client) CreateTeam(collection, project string, newTeam Team) (Team, error) {
 
-	//	If the collection is not blank, use it.  Otherwise, use defaults
-	urlcol := client.DefaultCollection
-	if collection != "" {
-		urlcol = collection
+	//	Our return value:
+	retval := Team{}
+
+	//	Prepare the request body
+	requestBytes := new(bytes.Buffer)
+	err := json.NewEncoder(requestBytes).Encode(&newTeam)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem preparing to create the team: %s", err)
+		return retval, apperr
 	}
 
-	//	If urlcol is blank, we have a problem.  Return an error:
-	if urlcol == "" {
-		return "", errors.New("TFS collection isn't specified, but is required")
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, "", "projects", path.Join(project, "teams"), "api-version=4.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
 	}
 
-	//	Parse the base url
-	u, err := url.Parse(client.TfsURL)
+	//	Send the request to the API:
+	resp, err := postAPIResponse(fullurl, requestBytes.String())
 	if err != nil {
-		return "", err
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
 	}
+	defer resp.Body.Close()
 
-	//	Assemble the component parts
-	u.Path = path.Join(u.Path, urlcol, project, "_apis", area, resource)
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error updating information in TFS: %s", resp.Status)
+		return retval, apperr
+	}
 
-	//	Add the querystring
-	u.RawQuery = query
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
 
-	//	Return the full url
-	return u.String(), nil
+	return retval, nil
 }
 
-// GetListOfProjects gets a list of projects for the given collection
-func (client Client) GetListOfProjects(collection string) (ProjectResponse, error) {
+// GetListOfTeamMembers gets a list of the members of a team
+func (client Client) GetListOfTeamMembers(collection, project, team string) (TeamMembersResponse, error) {
 
 	//	Our return value:
-	retval := ProjectResponse{}
+	retval := TeamMembersResponse{}
 
 	//	Format the url
-	fullurl, err := client.GetFormattedURL(collection, "", "", "projects", "")
+	fullurl, err := client.GetFormattedURL(collection, "", "projects", path.Join(project, "teams", team, "members"), "api-version=4.1")
 	if err != nil {
 		apperr := fmt.Errorf("Unable to format url: %s", err)
 		return retval, apperr
 	}
 
-	//	Make a GET reqeust to TFS
+	//	Request a list of team members
 	resp, err := getAPIResponse(fullurl)
 	if err != nil {
 		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
@@ -88,20 +2613,22 @@ func (client Client) GetListOfProjects(collection string) (ProjectResponse, erro
 	return retval, nil
 }
 
-// GetListOfVariableGroups gets a list of variable groups for the given collection and project
-func (client Client) GetListOfVariableGroups(collection, project string) (VariableGroupsResponse, error) {
+// GetClassificationNode gets an area or iteration node (and its children, down to the given depth).  The structure group
+// is either StructureGroupAreas or StructureGroupIterations, and the node path is relative to the root (blank for the root)
+func (client Client) GetClassificationNode(collection, project, structureGroup, nodePath string, depth int) (ClassificationNode, error) {
 
 	//	Our return value:
-	retval := VariableGroupsResponse{}
+	retval := ClassificationNode{}
 
 	//	Format the url
-	fullurl, err := client.GetFormattedURL(collection, project, "distributedtask", "variablegroups", "groupName=*&actionFilter=use&top=50&api-version=4.1-preview.1")
+	resource := path.Join(append([]string{"classificationnodes", structureGroup}, SplitClassificationPath(nodePath)...)...)
+	fullurl, err := client.GetFormattedURL(collection, project, "wit", resource, fmt.Sprintf("$depth=%v&api-version=4.1", depth))
 	if err != nil {
 		apperr := fmt.Errorf("Unable to format url: %s", err)
 		return retval, apperr
 	}
 
-	//	Request a list of variable groups
+	//	Request the classification node
 	resp, err := getAPIResponse(fullurl)
 	if err != nil {
 		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
@@ -125,23 +2652,30 @@ func (client Client) GetListOfVariableGroups(collection, project string) (Variab
 	return retval, nil
 }
 
-// GetListOfMatchingVariableGroups gets a list of variable groups for the given collection, project, and group name
-func (client Client) GetListOfMatchingVariableGroups(collection, project, groupName string) (VariableGroupsResponse, error) {
+// CreateClassificationNode creates an area or iteration node under the given parent path (blank for the root)
+func (client Client) CreateClassificationNode(collection, project, structureGroup, parentPath string, newNode ClassificationNode) (ClassificationNode, error) {
 
 	//	Our return value:
-	retval := VariableGroupsResponse{}
+	retval := ClassificationNode{}
+
+	//	Prepare the request body
+	requestBytes := new(bytes.Buffer)
+	err := json.NewEncoder(requestBytes).Encode(&newNode)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem preparing to create the classification node: %s", err)
+		return retval, apperr
+	}
 
 	//	Format the url
-	escapedGroup := url.QueryEscape(groupName)
-	formattedQuery := fmt.Sprintf("groupName=%s&actionFilter=use&top=50&api-version=4.1-preview.1", escapedGroup)
-	fullurl, err := client.GetFormattedURL(collection, project, "distributedtask", "variablegroups", formattedQuery)
+	resource := path.Join(append([]string{"classificationnodes", structureGroup}, SplitClassificationPath(parentPath)...)...)
+	fullurl, err := client.GetFormattedURL(collection, project, "wit", resource, "api-version=4.1")
 	if err != nil {
 		apperr := fmt.Errorf("Unable to format url: %s", err)
 		return retval, apperr
 	}
 
-	//	Request a list of variable groups
-	resp, err := getAPIResponse(fullurl)
+	//	Send the request to the API:
+	resp, err := postAPIResponse(fullurl, requestBytes.String())
 	if err != nil {
 		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
 		return retval, apperr
@@ -150,7 +2684,7 @@ func (client Client) GetListOfMatchingVariableGroups(collection, project, groupN
 
 	//	If the HTTP status code indicates an error, report it and get out
 	if resp.StatusCode >= 400 {
-		apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
+		apperr := fmt.Errorf("There was an error updating information in TFS: %s", resp.Status)
 		return retval, apperr
 	}
 
@@ -164,39 +2698,94 @@ func (client Client) GetListOfMatchingVariableGroups(collection, project, groupN
 	return retval, nil
 }
 
-// CreateVariableGroup creates a variable group in the given collection and project
-func (client Client) CreateVariableGroup(collection, project string, newGroup VariableGroup) error {
+// SetTeamFieldValues sets the area paths owned by a team
+func (client Client) SetTeamFieldValues(collection, project, team string, values TeamFieldValues) (TeamFieldValues, error) {
+
+	//	Our return value:
+	retval := TeamFieldValues{}
 
 	//	Prepare the request body
 	requestBytes := new(bytes.Buffer)
-	err := json.NewEncoder(requestBytes).Encode(&newGroup)
+	err := json.NewEncoder(requestBytes).Encode(&values)
 	if err != nil {
-		apperr := fmt.Errorf("There was a problem preparing to create the group: %s", err)
-		return apperr
+		apperr := fmt.Errorf("There was a problem preparing to set the team area paths: %s", err)
+		return retval, apperr
 	}
 
 	//	Format the url
-	fullurl, err := client.GetFormattedURL(collection, project, "distributedtask", "variablegroups", "api-version=4.1-preview.1")
+	fullurl, err := client.GetFormattedURL(collection, path.Join(project, team), "work", "teamsettings/teamfieldvalues", "api-version=4.1")
 	if err != nil {
 		apperr := fmt.Errorf("Unable to format url: %s", err)
-		return apperr
+		return retval, apperr
+	}
+
+	//	Send the request to the API:
+	resp, err := patchAPIResponse(fullurl, requestBytes.String())
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
+		return retval, apperr
+	}
+	defer resp.Body.Close()
+
+	//	If the HTTP status code indicates an error, report it and get out
+	if resp.StatusCode >= 400 {
+		apperr := fmt.Errorf("There was an error updating information in TFS: %s", resp.Status)
+		return retval, apperr
+	}
+
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
+}
+
+// AddTeamIteration selects an iteration (by its identifier) for a team
+func (client Client) AddTeamIteration(collection, project, team, iterationID string) (TeamSettingsIteration, error) {
+
+	//	Our return value:
+	retval := TeamSettingsIteration{}
+
+	//	Prepare the request body
+	requestBytes := new(bytes.Buffer)
+	err := json.NewEncoder(requestBytes).Encode(&TeamSettingsIteration{ID: iterationID})
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem preparing to add the team iteration: %s", err)
+		return retval, apperr
+	}
+
+	//	Format the url
+	fullurl, err := client.GetFormattedURL(collection, path.Join(project, team), "work", "teamsettings/iterations", "api-version=4.1")
+	if err != nil {
+		apperr := fmt.Errorf("Unable to format url: %s", err)
+		return retval, apperr
 	}
 
 	//	Send the request to the API:
 	resp, err := postAPIResponse(fullurl, requestBytes.String())
 	if err != nil {
 		apperr := fmt.Errorf("There was a problem calling TFS: %s", err)
-		return apperr
+		return retval, apperr
 	}
 	defer resp.Body.Close()
 
 	//	If the HTTP status code indicates an error, report it and get out
 	if resp.StatusCode >= 400 {
-		apperr := fmt.Errorf("There was an error getting information from TFS: %s", resp.Status)
-		return apperr
+		apperr := fmt.Errorf("There was an error updating information in TFS: %s", resp.Status)
+		return retval, apperr
 	}
 
-	return nil
+	//	Decode the return object
+	err = json.NewDecoder(resp.Body).Decode(&retval)
+	if err != nil {
+		apperr := fmt.Errorf("There was a problem decoding the response from TFS: %s", err)
+		return retval, apperr
+	}
+
+	return retval, nil
 }
 
 // GetAPIResponse gets an API response for the given url request
@@ -205,6 +2794,7 @@ func getAPIResponse(url string) (*http.Response, error) {
 
 	//	Create our http client
 	client := &http.Client{
+		Transport:     Transport,
 		CheckRedirect: redirectPolicyFunc,
 	}
 
@@ -215,7 +2805,7 @@ func getAPIResponse(url string) (*http.Response, error) {
 	}
 
 	//	Set our basic auth field:
-	log.Println("[DEBUG] Using PAT ", viper.GetString("pat"))
+	log.Printf("[DEBUG] Using PAT that starts with: '%s'", patPrefix(viper.GetString("pat")))
 	req.Header.Add("Authorization", "Basic "+basicAuth("", viper.GetString("pat")))
 
 	//	Execute our request:
@@ -224,10 +2814,11 @@ func getAPIResponse(url string) (*http.Response, error) {
 
 // PostAPIResponse POSTs to the API and then gets an API response for the given url request and JSON body
 func postAPIResponse(url, jsonBody string) (*http.Response, error) {
-	log.Printf("[DEBUG] Creating a POST request for %s\n with post body:\n%s\n", url, jsonBody)
+	log.Printf("[DEBUG] Creating a POST request for %s\n with post body:\n%s\n", url, RedactBody([]byte(jsonBody)))
 
 	//	Create our http client
 	client := &http.Client{
+		Transport:     Transport,
 		CheckRedirect: redirectPolicyFunc,
 	}
 
@@ -241,7 +2832,149 @@ func postAPIResponse(url, jsonBody string) (*http.Response, error) {
 	req.Header.Add("Content-Type", "application/json")
 
 	//	Set our basic auth field:
-	log.Println("[DEBUG] Using PAT ", viper.GetString("pat"))
+	log.Printf("[DEBUG] Using PAT that starts with: '%s'", patPrefix(viper.GetString("pat")))
+	req.Header.Add("Authorization", "Basic "+basicAuth("", viper.GetString("pat")))
+
+	//	Execute our request:
+	return client.Do(req)
+}
+
+// PutAPIResponse PUTs to the API and then gets an API response for the given url request and JSON body
+func putAPIResponse(url, jsonBody string) (*http.Response, error) {
+	log.Printf("[DEBUG] Creating a PUT request for %s\n with put body:\n%s\n", url, RedactBody([]byte(jsonBody)))
+
+	//	Create our http client
+	client := &http.Client{
+		Transport:     Transport,
+		CheckRedirect: redirectPolicyFunc,
+	}
+
+	//	Create our request:
+	req, err := http.NewRequest("PUT", url, strings.NewReader(jsonBody))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	//	Set the request content type:
+	req.Header.Add("Content-Type", "application/json")
+
+	//	Set our basic auth field:
+	log.Printf("[DEBUG] Using PAT that starts with: '%s'", patPrefix(viper.GetString("pat")))
+	req.Header.Add("Authorization", "Basic "+basicAuth("", viper.GetString("pat")))
+
+	//	Execute our request:
+	return client.Do(req)
+}
+
+// PatchAPIResponse PATCHes the API and then gets an API response for the given url request and JSON body
+func patchAPIResponse(url, jsonBody string) (*http.Response, error) {
+	log.Printf("[DEBUG] Creating a PATCH request for %s\n with patch body:\n%s\n", url, RedactBody([]byte(jsonBody)))
+
+	//	Create our http client
+	client := &http.Client{
+		Transport:     Transport,
+		CheckRedirect: redirectPolicyFunc,
+	}
+
+	//	Create our request:
+	req, err := http.NewRequest("PATCH", url, strings.NewReader(jsonBody))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	//	Set the request content type:
+	req.Header.Add("Content-Type", "application/json")
+
+	//	Set our basic auth field:
+	log.Printf("[DEBUG] Using PAT that starts with: '%s'", patPrefix(viper.GetString("pat")))
+	req.Header.Add("Authorization", "Basic "+basicAuth("", viper.GetString("pat")))
+
+	//	Execute our request:
+	return client.Do(req)
+}
+
+// PostJSONPatchAPIResponse POSTs a JSON Patch document to the API and then gets an API response
+func postJSONPatchAPIResponse(url, jsonBody string) (*http.Response, error) {
+	return jsonPatchAPIResponse("POST", url, jsonBody)
+}
+
+// PatchJSONPatchAPIResponse PATCHes the API with a JSON Patch document and then gets an API response
+func patchJSONPatchAPIResponse(url, jsonBody string) (*http.Response, error) {
+	return jsonPatchAPIResponse("PATCH", url, jsonBody)
+}
+
+// jsonPatchAPIResponse sends a JSON Patch document to the API using the given method
+func jsonPatchAPIResponse(method, url, jsonBody string) (*http.Response, error) {
+	log.Printf("[DEBUG] Creating a %s request for %s\n with JSON Patch body:\n%s\n", method, url, RedactBody([]byte(jsonBody)))
+
+	//	Create our http client
+	client := &http.Client{
+		Transport:     Transport,
+		CheckRedirect: redirectPolicyFunc,
+	}
+
+	//	Create our request:
+	req, err := http.NewRequest(method, url, strings.NewReader(jsonBody))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	//	Set the request content type:
+	req.Header.Add("Content-Type", "application/json-patch+json")
+
+	//	Set our basic auth field:
+	log.Printf("[DEBUG] Using PAT that starts with: '%s'", patPrefix(viper.GetString("pat")))
+	req.Header.Add("Authorization", "Basic "+basicAuth("", viper.GetString("pat")))
+
+	//	Execute our request:
+	return client.Do(req)
+}
+
+// DeleteAPIResponse DELETEs the given url and then gets an API response
+func deleteAPIResponse(url string) (*http.Response, error) {
+	log.Println("[DEBUG] Creating a DELETE request for ", url)
+
+	//	Create our http client
+	client := &http.Client{
+		Transport:     Transport,
+		CheckRedirect: redirectPolicyFunc,
+	}
+
+	//	Create our request:
+	req, err := http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	//	Set our basic auth field:
+	log.Printf("[DEBUG] Using PAT that starts with: '%s'", patPrefix(viper.GetString("pat")))
+	req.Header.Add("Authorization", "Basic "+basicAuth("", viper.GetString("pat")))
+
+	//	Execute our request:
+	return client.Do(req)
+}
+
+// UploadAPIResponse POSTs the given content (as a binary stream) to the API and then gets an API response
+func uploadAPIResponse(url string, content io.Reader) (*http.Response, error) {
+	log.Printf("[DEBUG] Creating an upload request for %s\n", url)
+
+	//	Create our http client
+	client := &http.Client{
+		Transport:     Transport,
+		CheckRedirect: redirectPolicyFunc,
+	}
+
+	//	Create our request:
+	req, err := http.NewRequest("POST", url, content)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	//	Set the request content type:
+	req.Header.Add("Content-Type", "application/octet-stream")
+
+	//	Set our basic auth field:
+	log.Printf("[DEBUG] Using PAT that starts with: '%s'", patPrefix(viper.GetString("pat")))
 	req.Header.Add("Authorization", "Basic "+basicAuth("", viper.GetString("pat")))
 
 	//	Execute our request:
@@ -254,6 +2987,14 @@ func redirectPolicyFunc(req *http.Request, via []*http.Request) error {
 	return nil
 }
 
+//	Get the start of a PAT, so it can be logged without giving it away
+func patPrefix(pat string) string {
+	if len(pat) <= 4 {
+		return ""
+	}
+	return pat[:4]
+}
+
 //	Format our basic auth header
 func basicAuth(username, password string) string {
 	auth := username + ":" + password
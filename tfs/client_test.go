@@ -121,3 +121,40 @@ func TestClient_BlankDefaultsValidArgs_GetFormattedURL_ReturnsFormattedUrl(t *te
 	}
 
 }
+
+// The release management url should be derived from the TFS url, unless it's set explicitly
+func TestClient_ValidDefaults_GetFormattedReleaseURL_ReturnsFormattedUrl(t *testing.T) {
+
+	//	Arrange
+	tests := []struct {
+		baseurl    string
+		releaseurl string
+		expected   string
+	}{
+		{"http://tfsrepository.mydomain.com:8080/tfs", "", "http://tfsrepository.mydomain.com:8080/tfs/colone/projone/_apis/release/definitions?api-version=4.1-preview.3"},
+		{"https://dev.azure.com/myorg", "", "https://vsrm.dev.azure.com/myorg/colone/projone/_apis/release/definitions?api-version=4.1-preview.3"},
+		{"https://myaccount.visualstudio.com", "", "https://myaccount.vsrm.visualstudio.com/colone/projone/_apis/release/definitions?api-version=4.1-preview.3"},
+		{"https://myaccount.visualstudio.com", "https://release.mydomain.com/tfs", "https://release.mydomain.com/tfs/colone/projone/_apis/release/definitions?api-version=4.1-preview.3"},
+	}
+
+	//	Act
+	for _, tt := range tests {
+
+		client := tfs.Client{
+			TfsURL:     tt.baseurl,
+			ReleaseURL: tt.releaseurl,
+		}
+
+		//	Call the method with the test parameters
+		actual, err := client.GetFormattedReleaseURL("colone", "projone", "release", "definitions", "api-version=4.1-preview.3")
+		if err != nil {
+			t.Errorf("GetFormattedReleaseURL with base url: %s expected: %s but got error %s", tt.baseurl, tt.expected, err)
+		}
+
+		//	Compare expected with actual and report an error if they don't match
+		if tt.expected != actual {
+			t.Errorf("GetFormattedReleaseURL with base url: %s expected: %s but got %s", tt.baseurl, tt.expected, actual)
+		}
+	}
+
+}
@@ -0,0 +1,104 @@
+package tfs
+
+import (
+	"time"
+)
+
+// GitRepositoriesResponse defines the response recieved when querying git repositories
+type GitRepositoriesResponse struct {
+	Count        int             `json:"count"`
+	Repositories []GitRepository `json:"value"`
+}
+
+// GitRepository is a single git repository
+type GitRepository struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	URL           string `json:"url"`
+	DefaultBranch string `json:"defaultBranch"`
+
+	// Size is the size of the repository in bytes
+	Size int64 `json:"size"`
+
+	// RemoteURL is the https clone url
+	RemoteURL string `json:"remoteUrl"`
+
+	// SSHURL is the ssh clone url
+	SSHURL string `json:"sshUrl"`
+
+	WebURL string `json:"webUrl"`
+
+	Project struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"project"`
+}
+
+// GitRefsResponse defines the response recieved when querying git refs
+type GitRefsResponse struct {
+	Count int      `json:"count"`
+	Refs  []GitRef `json:"value"`
+}
+
+// GitRef is a single git ref (like refs/heads/master)
+type GitRef struct {
+	Name     string `json:"name"`
+	ObjectID string `json:"objectId"`
+	URL      string `json:"url"`
+
+	Creator struct {
+		DisplayName string `json:"displayName"`
+		UniqueName  string `json:"uniqueName"`
+		ID          string `json:"id"`
+	} `json:"creator"`
+}
+
+// GitBranchStatsResponse defines the response recieved when querying git branch statistics
+type GitBranchStatsResponse struct {
+	Count    int              `json:"count"`
+	Branches []GitBranchStats `json:"value"`
+}
+
+// GitBranchStats is the ahead/behind information and latest commit for a single branch
+type GitBranchStats struct {
+	// Name is the short branch name (like master)
+	Name string `json:"name"`
+
+	// AheadCount is the number of commits on this branch that aren't on the base branch
+	AheadCount int `json:"aheadCount"`
+
+	// BehindCount is the number of commits on the base branch that aren't on this branch
+	BehindCount int `json:"behindCount"`
+
+	IsBaseVersion bool `json:"isBaseVersion"`
+
+	// Commit is the latest commit on the branch
+	Commit GitCommitRef `json:"commit"`
+}
+
+// GitCommitRef is a single git commit
+type GitCommitRef struct {
+	CommitID  string      `json:"commitId"`
+	Comment   string      `json:"comment"`
+	Author    GitUserDate `json:"author"`
+	Committer GitUserDate `json:"committer"`
+	URL       string      `json:"url"`
+}
+
+// GitUserDate is the user and date information for a git commit
+type GitUserDate struct {
+	Name  string    `json:"name"`
+	Email string    `json:"email"`
+	Date  time.Time `json:"date"`
+}
+
+// GitItem is a single file (or folder) in a git repository
+type GitItem struct {
+	ObjectID      string `json:"objectId"`
+	CommitID      string `json:"commitId"`
+	Path          string `json:"path"`
+	IsFolder      bool   `json:"isFolder"`
+	Content       string `json:"content"`
+	URL           string `json:"url"`
+	GitObjectType string `json:"gitObjectType"`
+}
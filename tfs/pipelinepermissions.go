@@ -0,0 +1,39 @@
+package tfs
+
+// ResourcePipelinePermissions is the set of pipelines authorized to use a protected resource (like a
+// secure file, variable group or service endpoint)
+type ResourcePipelinePermissions struct {
+	Resource struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+	} `json:"resource"`
+
+	// AllPipelines is set when every pipeline in the project is authorized
+	AllPipelines *PipelinePermission `json:"allPipelines,omitempty"`
+
+	// Pipelines is the list of individually authorized pipelines
+	Pipelines []PipelinePermission `json:"pipelines,omitempty"`
+}
+
+// PipelinePermission is the authorization of a single pipeline (or of all pipelines)
+type PipelinePermission struct {
+	ID           int         `json:"id,omitempty"`
+	Authorized   bool        `json:"authorized"`
+	AuthorizedBy IdentityRef `json:"authorizedBy,omitempty"`
+}
+
+// AllPipelinesAuthorized returns true if every pipeline in the project is authorized to use the resource
+func (p ResourcePipelinePermissions) AllPipelinesAuthorized() bool {
+	return p.AllPipelines != nil && p.AllPipelines.Authorized
+}
+
+// AuthorizedPipelineIDs gets the ids of the individually authorized pipelines
+func (p ResourcePipelinePermissions) AuthorizedPipelineIDs() []int {
+	retval := []int{}
+	for _, pipeline := range p.Pipelines {
+		if pipeline.Authorized {
+			retval = append(retval, pipeline.ID)
+		}
+	}
+	return retval
+}
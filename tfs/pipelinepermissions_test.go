@@ -0,0 +1,50 @@
+package tfs_test
+
+import (
+	"testing"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// Authorizing some pipelines shouldn't count as authorizing all of them
+func TestAllPipelinesAuthorized_NoAllPipelines_ReturnsFalse(t *testing.T) {
+
+	//	Arrange
+	permissions := tfs.ResourcePipelinePermissions{
+		Pipelines: []tfs.PipelinePermission{{ID: 12, Authorized: true}},
+	}
+
+	//	Act
+	authorized := permissions.AllPipelinesAuthorized()
+
+	//	Assert
+	if authorized {
+		t.Errorf("Expected all pipelines not to be authorized")
+	}
+}
+
+// Only the ids of the pipelines that are authorized should be returned
+func TestAuthorizedPipelineIDs_MixedPipelines_ReturnsAuthorizedOnly(t *testing.T) {
+
+	//	Arrange
+	permissions := tfs.ResourcePipelinePermissions{
+		AllPipelines: &tfs.PipelinePermission{Authorized: true},
+		Pipelines: []tfs.PipelinePermission{
+			{ID: 12, Authorized: true},
+			{ID: 13, Authorized: false},
+			{ID: 14, Authorized: true},
+		},
+	}
+
+	//	Act
+	ids := permissions.AuthorizedPipelineIDs()
+
+	//	Assert
+	if !permissions.AllPipelinesAuthorized() {
+		t.Errorf("Expected all pipelines to be authorized")
+	}
+
+	if len(ids) != 2 || ids[0] != 12 || ids[1] != 14 {
+		t.Errorf("Expected pipelines 12 and 14 but got %v", ids)
+	}
+}
@@ -0,0 +1,136 @@
+package tfs
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// PolicyConfigurationsResponse defines the response recieved when querying policy configurations
+type PolicyConfigurationsResponse struct {
+	Count                int                   `json:"count"`
+	PolicyConfigurations []PolicyConfiguration `json:"value"`
+}
+
+// PolicyConfiguration is a single policy (like minimum reviewers or build validation) and its settings
+type PolicyConfiguration struct {
+	ID         int  `json:"id,omitempty"`
+	Revision   int  `json:"revision,omitempty"`
+	IsEnabled  bool `json:"isEnabled"`
+	IsBlocking bool `json:"isBlocking"`
+	IsDeleted  bool `json:"isDeleted,omitempty"`
+
+	Type struct {
+		ID          string `json:"id"`
+		DisplayName string `json:"displayName,omitempty"`
+	} `json:"type"`
+
+	// Settings are the type-specific settings for the policy, including its scope
+	Settings map[string]interface{} `json:"settings"`
+
+	URL string `json:"url,omitempty"`
+}
+
+// PolicyScope is a single repository and branch that a policy applies to
+type PolicyScope struct {
+	// RepositoryID is blank if the policy applies to every repository in the project
+	RepositoryID string `json:"repositoryId,omitempty"`
+
+	// RefName is the branch (like refs/heads/master), or the branch prefix if MatchKind is 'prefix'
+	RefName string `json:"refName,omitempty"`
+
+	// MatchKind is either 'exact' or 'prefix'
+	MatchKind string `json:"matchKind,omitempty"`
+}
+
+// Scopes gets the list of repositories and branches the policy applies to
+func (p PolicyConfiguration) Scopes() []PolicyScope {
+	retval := []PolicyScope{}
+
+	items, _ := p.Settings["scope"].([]interface{})
+	for _, item := range items {
+		values, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		scope := PolicyScope{}
+		if v, ok := values["repositoryId"].(string); ok {
+			scope.RepositoryID = v
+		}
+		if v, ok := values["refName"].(string); ok {
+			scope.RefName = v
+		}
+		if v, ok := values["matchKind"].(string); ok {
+			scope.MatchKind = v
+		}
+		retval = append(retval, scope)
+	}
+
+	return retval
+}
+
+// SetScopes replaces the list of repositories and branches the policy applies to
+func (p *PolicyConfiguration) SetScopes(scopes []PolicyScope) {
+	if p.Settings == nil {
+		p.Settings = map[string]interface{}{}
+	}
+
+	items := []interface{}{}
+	for _, scope := range scopes {
+		item := map[string]interface{}{}
+		if scope.RepositoryID != "" {
+			item["repositoryId"] = scope.RepositoryID
+		} else {
+			item["repositoryId"] = nil
+		}
+		if scope.RefName != "" {
+			item["refName"] = scope.RefName
+		}
+		if scope.MatchKind != "" {
+			item["matchKind"] = scope.MatchKind
+		}
+		items = append(items, item)
+	}
+
+	p.Settings["scope"] = items
+}
+
+// AppliesTo returns true if the policy applies to the given repository and branch.
+// If branch is blank, any branch matches
+func (s PolicyScope) AppliesTo(repositoryID, branch string) bool {
+	if s.RepositoryID != "" && !strings.EqualFold(s.RepositoryID, repositoryID) {
+		return false
+	}
+
+	if branch == "" || s.RefName == "" {
+		return true
+	}
+
+	if strings.EqualFold(s.MatchKind, "prefix") {
+		return strings.HasPrefix(branch, s.RefName)
+	}
+
+	return branch == s.RefName
+}
+
+// BuildDefinitionID gets the build definition id used by a build validation policy (or 0 if there isn't one)
+func (p PolicyConfiguration) BuildDefinitionID() int {
+	switch v := p.Settings["buildDefinitionId"].(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	case json.Number:
+		id, _ := v.Int64()
+		return int(id)
+	}
+	return 0
+}
+
+// SetBuildDefinitionID sets the build definition id used by a build validation policy
+func (p *PolicyConfiguration) SetBuildDefinitionID(id int) {
+	if p.Settings == nil {
+		p.Settings = map[string]interface{}{}
+	}
+	p.Settings["buildDefinitionId"] = id
+}
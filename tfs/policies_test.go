@@ -0,0 +1,63 @@
+package tfs_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// A policy scope should match its repository, and either the exact branch or the branch prefix
+func TestPolicyScope_Branches_AppliesTo_ReturnsMatch(t *testing.T) {
+
+	//	Arrange
+	tests := []struct {
+		scope      tfs.PolicyScope
+		repository string
+		branch     string
+		expected   bool
+	}{
+		{tfs.PolicyScope{RepositoryID: "repo1", RefName: "refs/heads/master", MatchKind: "exact"}, "repo1", "refs/heads/master", true},
+		{tfs.PolicyScope{RepositoryID: "repo1", RefName: "refs/heads/master", MatchKind: "exact"}, "repo1", "refs/heads/develop", false},
+		{tfs.PolicyScope{RepositoryID: "repo1", RefName: "refs/heads/master", MatchKind: "exact"}, "repo2", "refs/heads/master", false},
+		{tfs.PolicyScope{RepositoryID: "repo1", RefName: "refs/heads/release/", MatchKind: "prefix"}, "repo1", "refs/heads/release/1.0", true},
+		{tfs.PolicyScope{RefName: "refs/heads/master", MatchKind: "exact"}, "repo2", "refs/heads/master", true},
+		{tfs.PolicyScope{RepositoryID: "repo1", RefName: "refs/heads/master", MatchKind: "exact"}, "repo1", "", true},
+	}
+
+	//	Act
+	for _, tt := range tests {
+		actual := tt.scope.AppliesTo(tt.repository, tt.branch)
+
+		//	Assert
+		if actual != tt.expected {
+			t.Errorf("AppliesTo('%s', '%s') with scope %+v expected %v but got %v", tt.repository, tt.branch, tt.scope, tt.expected, actual)
+		}
+	}
+}
+
+// Scopes and build definition ids should survive decoding and being replaced
+func TestPolicyConfiguration_DecodedSettings_ScopesAndBuildDefinition_RoundTrip(t *testing.T) {
+
+	//	Arrange
+	raw := `{"id": 12, "type": {"id": "0609b952-1397-4640-95ec-e00a01b2c241"}, "settings": {"buildDefinitionId": 42, "scope": [{"repositoryId": "repo1", "refName": "refs/heads/master", "matchKind": "exact"}]}}`
+	policy := tfs.PolicyConfiguration{}
+	if err := json.Unmarshal([]byte(raw), &policy); err != nil {
+		t.Fatalf("Unable to decode the test policy: %s", err)
+	}
+
+	//	Act
+	scopes := policy.Scopes()
+	scopes[0].RepositoryID = "repo2"
+	policy.SetScopes(scopes)
+	policy.SetBuildDefinitionID(99)
+
+	//	Assert
+	if actual := policy.Scopes(); len(actual) != 1 || actual[0].RepositoryID != "repo2" || actual[0].RefName != "refs/heads/master" {
+		t.Errorf("Scopes expected the master branch of repo2 but got %+v", actual)
+	}
+
+	if actual := policy.BuildDefinitionID(); actual != 99 {
+		t.Errorf("BuildDefinitionID expected 99 but got %v", actual)
+	}
+}
@@ -0,0 +1,190 @@
+package tfs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AllProjects is the project selector for every project in a collection
+const AllProjects = "all"
+
+// DefaultParallelism is how many projects ForEachProject works on at once, unless it's told otherwise
+const DefaultParallelism = 4
+
+// ProjectResult is the result of an operation in a single project
+type ProjectResult struct {
+	Project  string
+	Result   string
+	Err      error
+	Duration time.Duration
+}
+
+// PoolOptions control how ForEachProject runs an operation
+type PoolOptions struct {
+	// Parallelism is the most projects worked on at once (default is DefaultParallelism)
+	Parallelism int
+
+	// Progress (if it's set) is called as each project finishes.  It's never called concurrently
+	Progress func(done, total int, result ProjectResult)
+}
+
+// SelectProjects gets the names of the projects a selector picks.  The selector is 'all', or a comma
+// separated list of project names and globs (like 'Web*').  Names are matched without regard to case
+func (client Client) SelectProjects(collection, selector string) ([]string, error) {
+	retval := []string{}
+
+	if strings.TrimSpace(selector) == "" {
+		return retval, fmt.Errorf("No projects were selected")
+	}
+
+	projects, err := client.GetListOfProjects(collection)
+	if err != nil {
+		return retval, err
+	}
+
+	selected := map[string]bool{}
+	for _, item := range strings.Split(selector, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		matched := false
+		for _, project := range projects.Projects {
+			var matches bool
+			switch {
+			case strings.EqualFold(item, AllProjects):
+				matches = true
+			case strings.ContainsAny(item, "*?["):
+				matches, err = path.Match(strings.ToLower(item), strings.ToLower(project.Name))
+				if err != nil {
+					return retval, fmt.Errorf("Invalid project pattern '%s': %s", item, err)
+				}
+			default:
+				matches = strings.EqualFold(item, project.Name)
+			}
+
+			if !matches {
+				continue
+			}
+			matched = true
+
+			if !selected[strings.ToLower(project.Name)] {
+				selected[strings.ToLower(project.Name)] = true
+				retval = append(retval, project.Name)
+			}
+		}
+
+		if !matched {
+			return retval, fmt.Errorf("There aren't any projects matching '%s'", item)
+		}
+	}
+
+	return retval, nil
+}
+
+// ForEachProject runs an operation in each project, with at most options.Parallelism running at once.
+// The operation returns a summary of what it did.  The results are in the same order as the projects
+func (client Client) ForEachProject(projects []string, options PoolOptions, operation func(project string) (string, error)) []ProjectResult {
+	retval := make([]ProjectResult, len(projects))
+
+	parallelism := options.Parallelism
+	if parallelism < 1 {
+		parallelism = DefaultParallelism
+	}
+
+	//	Hand out the projects to the workers
+	work := make(chan int)
+	go func() {
+		for i := range projects {
+			work <- i
+		}
+		close(work)
+	}()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	done := 0
+
+	for worker := 0; worker < parallelism && worker < len(projects); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range work {
+				started := time.Now()
+				result, err := operation(projects[i])
+
+				mu.Lock()
+				retval[i] = ProjectResult{Project: projects[i], Result: result, Err: err, Duration: time.Since(started)}
+				done++
+				if options.Progress != nil {
+					options.Progress(done, len(projects), retval[i])
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+	return retval
+}
+
+// RateLimiter is an http transport that spaces out requests, so there are at most a given number per second
+type RateLimiter struct {
+	next     http.RoundTripper
+	interval time.Duration
+
+	mu       sync.Mutex
+	nextSlot time.Time
+}
+
+// NewRateLimiter creates a transport that sends at most requestsPerSecond requests a second to the next
+// transport.  Zero (or less) means there's no limit
+func NewRateLimiter(requestsPerSecond float64, next http.RoundTripper) *RateLimiter {
+	retval := &RateLimiter{next: next}
+	if requestsPerSecond > 0 {
+		retval.interval = time.Duration(float64(time.Second) / requestsPerSecond)
+	}
+	return retval
+}
+
+// RoundTrip waits for the request's turn, then sends it
+func (l *RateLimiter) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := l.wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return l.next.RoundTrip(req)
+}
+
+// wait waits until the next request can be sent (or the context is done)
+func (l *RateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	slot := l.nextSlot
+	if slot.Before(now) {
+		slot = now
+	}
+	l.nextSlot = slot.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := slot.Sub(now)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,131 @@
+package tfs_test
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/danesparza/tfsutil/tfs"
+	"github.com/danesparza/tfsutil/tfs/tfstest"
+)
+
+// Projects should be selected by name and glob (in any case, without duplicates), or all of them
+func TestClient_SelectProjects_ReturnsMatchingProjects(t *testing.T) {
+
+	//	Arrange
+	server := tfstest.NewServer()
+	defer server.Close()
+	for _, name := range []string{"Website", "Web API", "Mobile", "Billing"} {
+		server.AddProject("DefaultCollection", tfs.Project{Name: name})
+	}
+	client := server.Client()
+
+	selectors := map[string][]string{
+		"all":             {"Website", "Web API", "Mobile", "Billing"},
+		"web*":            {"Website", "Web API"},
+		"Billing, mobile": {"Billing", "Mobile"},
+		"Web*,Website":    {"Website", "Web API"},
+	}
+
+	for selector, expected := range selectors {
+		//	Act
+		projects, err := client.SelectProjects("DefaultCollection", selector)
+
+		//	Assert
+		if err != nil || !reflect.DeepEqual(projects, expected) {
+			t.Errorf("Expected '%s' to select %v but got %v (error %v)", selector, expected, projects, err)
+		}
+	}
+
+	if _, err := client.SelectProjects("DefaultCollection", "Website,Nothing*"); err == nil {
+		t.Errorf("Expected an error for a pattern that doesn't match any projects")
+	}
+}
+
+// No more than the given number of projects should be worked on at once, and the results should be in project order
+func TestClient_ForEachProject_LimitsParallelism(t *testing.T) {
+
+	//	Arrange
+	client := tfs.Client{}
+	projects := []string{}
+	for i := 0; i < 12; i++ {
+		projects = append(projects, fmt.Sprintf("Project %v", i))
+	}
+
+	var mu sync.Mutex
+	running, most, progress := 0, 0, 0
+
+	//	Act
+	results := client.ForEachProject(projects, tfs.PoolOptions{
+		Parallelism: 3,
+		Progress:    func(done, total int, result tfs.ProjectResult) { progress = done },
+	}, func(project string) (string, error) {
+		mu.Lock()
+		running++
+		if running > most {
+			most = running
+		}
+		mu.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+
+		if project == "Project 5" {
+			return "", fmt.Errorf("Unicorns escaped")
+		}
+		return "ok", nil
+	})
+
+	//	Assert
+	if most != 3 {
+		t.Errorf("Expected at most 3 projects at once (and to use all 3), but got %v", most)
+	}
+
+	if len(results) != 12 || progress != 12 {
+		t.Fatalf("Expected 12 results and progress reports, but got %v and %v", len(results), progress)
+	}
+
+	for i, result := range results {
+		if result.Project != projects[i] || (i == 5) != (result.Err != nil) {
+			t.Errorf("Expected the results in order (with only Project 5 failing), but got %+v at %v", result, i)
+		}
+	}
+}
+
+// Requests sent at the same time should be spaced out to the rate
+func TestRateLimiter_SpacesOutRequests(t *testing.T) {
+
+	//	Arrange
+	server := tfstest.NewServer()
+	defer server.Close()
+	server.AddProject("DefaultCollection", tfs.Project{Name: "Website"})
+	client := server.Client()
+
+	restore := useTransport(tfs.NewRateLimiter(50, tfs.Transport))
+	defer restore()
+
+	//	Act
+	started := time.Now()
+	results := client.ForEachProject([]string{"1", "2", "3", "4", "5", "6"}, tfs.PoolOptions{Parallelism: 6}, func(project string) (string, error) {
+		_, err := client.GetListOfProjects("DefaultCollection")
+		return "", err
+	})
+	elapsed := time.Since(started)
+
+	//	Assert
+	for _, result := range results {
+		if result.Err != nil {
+			t.Fatalf("Expected no errors but got %s", result.Err)
+		}
+	}
+
+	//	6 requests at 50 a second are at least 100ms apart from first to last
+	if elapsed < 100*time.Millisecond {
+		t.Errorf("Expected the requests to be spaced out, but they took %s", elapsed)
+	}
+}
@@ -16,3 +16,78 @@ type Project struct {
 	Revision    int    `json:"revision"`
 	Visibility  string `json:"visibility"`
 }
+
+// ProjectCollectionResponse defines the response recieved when querying project collections
+type ProjectCollectionResponse struct {
+	Count       int                 `json:"count"`
+	Collections []ProjectCollection `json:"value"`
+}
+
+// ProjectCollection is a single TFS project collection
+type ProjectCollection struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// ProcessesResponse defines the response recieved when querying processes (process templates)
+type ProcessesResponse struct {
+	Count     int       `json:"count"`
+	Processes []Process `json:"value"`
+}
+
+// Process is a process template (like Agile or Scrum) that projects are created from
+type Process struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	IsDefault   bool   `json:"isDefault"`
+}
+
+// NewProject is the request to create a project
+type NewProject struct {
+	Name         string                 `json:"name"`
+	Description  string                 `json:"description"`
+	Visibility   string                 `json:"visibility,omitempty"`
+	Capabilities NewProjectCapabilities `json:"capabilities"`
+}
+
+// NewProjectCapabilities are the version control and process template for a new project
+type NewProjectCapabilities struct {
+	VersionControl struct {
+		SourceControlType string `json:"sourceControlType"`
+	} `json:"versioncontrol"`
+	ProcessTemplate struct {
+		TemplateTypeID string `json:"templateTypeId"`
+	} `json:"processTemplate"`
+}
+
+// Operation statuses
+const (
+	OperationSucceeded = "succeeded"
+	OperationFailed    = "failed"
+	OperationCancelled = "cancelled"
+)
+
+// OperationReference is a long running operation, like creating or deleting a project
+type OperationReference struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	URL    string `json:"url"`
+
+	// ResultMessage explains why an operation failed
+	ResultMessage string `json:"resultMessage,omitempty"`
+}
+
+// Done returns true if the operation has finished (successfully or not)
+func (op OperationReference) Done() bool {
+	return op.Status == OperationSucceeded || op.Status == OperationFailed || op.Status == OperationCancelled
+}
+
+// NewGitProject creates the request for a (private) project that uses Git and the given process
+func NewGitProject(name, description, processID string) NewProject {
+	retval := NewProject{Name: name, Description: description, Visibility: "private"}
+	retval.Capabilities.VersionControl.SourceControlType = "Git"
+	retval.Capabilities.ProcessTemplate.TemplateTypeID = processID
+	return retval
+}
@@ -0,0 +1,119 @@
+package tfs
+
+import (
+	"time"
+)
+
+// PullRequestsResponse defines the response recieved when querying pull requests
+type PullRequestsResponse struct {
+	Count        int           `json:"count"`
+	PullRequests []PullRequest `json:"value"`
+}
+
+// PullRequest is a single git pull request
+type PullRequest struct {
+	PullRequestID int    `json:"pullRequestId,omitempty"`
+	Title         string `json:"title"`
+	Description   string `json:"description,omitempty"`
+
+	// Status is the pull request status: active, abandoned or completed
+	Status string `json:"status,omitempty"`
+
+	// SourceRefName is the branch being merged (like refs/heads/feature)
+	SourceRefName string `json:"sourceRefName"`
+
+	// TargetRefName is the branch being merged into (like refs/heads/master)
+	TargetRefName string `json:"targetRefName"`
+
+	MergeStatus  string    `json:"mergeStatus,omitempty"`
+	IsDraft      bool      `json:"isDraft,omitempty"`
+	CreationDate time.Time `json:"creationDate,omitempty"`
+	URL          string    `json:"url,omitempty"`
+
+	CreatedBy *IdentityRef `json:"createdBy,omitempty"`
+
+	Repository *struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"repository,omitempty"`
+
+	// Reviewers is the list of reviewers and their votes
+	Reviewers []IdentityRefWithVote `json:"reviewers,omitempty"`
+}
+
+// PullRequestQuery defines the optional filters used when querying pull requests
+type PullRequestQuery struct {
+	RepositoryID string
+
+	// Status is the pull request status: active, abandoned, completed or all
+	Status string
+
+	// CreatorID and ReviewerID are identity ids
+	CreatorID  string
+	ReviewerID string
+
+	Top int
+}
+
+// IdentityRef is a reference to a single user (or group)
+type IdentityRef struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"displayName"`
+	UniqueName  string `json:"uniqueName"`
+}
+
+// IdentityRefWithVote is a single pull request reviewer and their vote
+type IdentityRefWithVote struct {
+	IdentityRef
+
+	// Vote is 10 (approved), 5 (approved with suggestions), 0 (no vote), -5 (waiting for author) or -10 (rejected)
+	Vote int `json:"vote"`
+
+	IsRequired bool `json:"isRequired"`
+}
+
+// Pull request votes
+const (
+	VoteApproved                = 10
+	VoteApprovedWithSuggestions = 5
+	VoteNone                    = 0
+	VoteWaitingForAuthor        = -5
+	VoteRejected                = -10
+)
+
+// VoteDescription describes a pull request vote
+func VoteDescription(vote int) string {
+	switch {
+	case vote >= VoteApproved:
+		return "approved"
+	case vote >= VoteApprovedWithSuggestions:
+		return "approved with suggestions"
+	case vote == VoteNone:
+		return "no vote"
+	case vote > VoteRejected:
+		return "waiting for author"
+	default:
+		return "rejected"
+	}
+}
+
+// IdentitiesResponse defines the response recieved when searching identities
+type IdentitiesResponse struct {
+	Count      int        `json:"count"`
+	Identities []Identity `json:"value"`
+}
+
+// Identity is a single user (or group) identity
+type Identity struct {
+	ID                  string `json:"id"`
+	ProviderDisplayName string `json:"providerDisplayName"`
+	IsActive            bool   `json:"isActive"`
+}
+
+// ConnectionData describes the connection to TFS, including the authenticated user
+type ConnectionData struct {
+	AuthenticatedUser struct {
+		ID                  string `json:"id"`
+		ProviderDisplayName string `json:"providerDisplayName"`
+	} `json:"authenticatedUser"`
+}
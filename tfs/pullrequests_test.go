@@ -0,0 +1,33 @@
+package tfs_test
+
+import (
+	"testing"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// Each pull request vote should have a readable description
+func TestVoteDescription_KnownVotes_ReturnsDescription(t *testing.T) {
+
+	//	Arrange
+	tests := []struct {
+		vote     int
+		expected string
+	}{
+		{tfs.VoteApproved, "approved"},
+		{tfs.VoteApprovedWithSuggestions, "approved with suggestions"},
+		{tfs.VoteNone, "no vote"},
+		{tfs.VoteWaitingForAuthor, "waiting for author"},
+		{tfs.VoteRejected, "rejected"},
+	}
+
+	//	Act
+	for _, tt := range tests {
+		actual := tfs.VoteDescription(tt.vote)
+
+		//	Assert
+		if actual != tt.expected {
+			t.Errorf("VoteDescription(%v) expected '%s' but got '%s'", tt.vote, tt.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package tfs
+
+import (
+	"time"
+)
+
+// ReleaseDefinitionsResponse defines the response recieved when querying release definitions
+type ReleaseDefinitionsResponse struct {
+	Count       int                 `json:"count"`
+	Definitions []ReleaseDefinition `json:"value"`
+}
+
+// ReleaseDefinition is a single (classic) release definition
+type ReleaseDefinition struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Path        string `json:"path"`
+	Description string `json:"description"`
+	Revision    int    `json:"revision"`
+	URL         string `json:"url"`
+
+	// Variables is the map of release-level variables
+	Variables map[string]Variable `json:"variables"`
+
+	// VariableGroups is the list of variable group ids linked at the release level
+	VariableGroups []int `json:"variableGroups"`
+
+	// Environments is the list of environments (stages) in this definition
+	Environments []ReleaseDefinitionEnvironment `json:"environments"`
+
+	CreatedBy struct {
+		DisplayName string `json:"displayName"`
+		UniqueName  string `json:"uniqueName"`
+		ID          string `json:"id"`
+	} `json:"createdBy"`
+
+	CreatedOn time.Time `json:"createdOn"`
+
+	ModifiedBy struct {
+		DisplayName string `json:"displayName"`
+		UniqueName  string `json:"uniqueName"`
+		ID          string `json:"id"`
+	} `json:"modifiedBy"`
+
+	ModifiedOn time.Time `json:"modifiedOn"`
+}
+
+// ReleaseDefinitionEnvironment is a single environment (stage) in a release definition
+type ReleaseDefinitionEnvironment struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Rank int    `json:"rank"`
+
+	// Variables is the map of environment-level variables
+	Variables map[string]Variable `json:"variables"`
+
+	// VariableGroups is the list of variable group ids linked to this environment
+	VariableGroups []int `json:"variableGroups"`
+}
@@ -0,0 +1,26 @@
+package tfs
+
+import (
+	"time"
+)
+
+// SecureFilesResponse defines the response recieved when querying secure files
+type SecureFilesResponse struct {
+	Count       int          `json:"count"`
+	SecureFiles []SecureFile `json:"value"`
+}
+
+// SecureFile is a single secure file (like a signing certificate or provisioning profile).  TFS never returns
+// the contents of a secure file
+type SecureFile struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name"`
+
+	// Properties is the map of metadata for the secure file
+	Properties map[string]string `json:"properties,omitempty"`
+
+	CreatedBy  IdentityRef `json:"createdBy,omitempty"`
+	CreatedOn  time.Time   `json:"createdOn"`
+	ModifiedBy IdentityRef `json:"modifiedBy,omitempty"`
+	ModifiedOn time.Time   `json:"modifiedOn"`
+}
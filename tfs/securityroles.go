@@ -0,0 +1,22 @@
+package tfs
+
+// RoleAssignmentsResponse defines the response recieved when querying security role assignments
+type RoleAssignmentsResponse struct {
+	Count           int              `json:"count"`
+	RoleAssignments []RoleAssignment `json:"value"`
+}
+
+// RoleAssignment is the security role a single user or group has on a resource (like a variable group)
+type RoleAssignment struct {
+	Identity IdentityRef `json:"identity"`
+
+	Role struct {
+		Name        string `json:"name"`
+		DisplayName string `json:"displayName"`
+		Scope       string `json:"scope"`
+	} `json:"role"`
+
+	// Access is either 'assigned' (on the resource itself) or 'inherited'
+	Access            string `json:"access"`
+	AccessDisplayName string `json:"accessDisplayName"`
+}
@@ -0,0 +1,47 @@
+package tfs
+
+// ServiceEndpointsResponse defines the response recieved when querying service endpoints
+type ServiceEndpointsResponse struct {
+	Count            int               `json:"count"`
+	ServiceEndpoints []ServiceEndpoint `json:"value"`
+}
+
+// ServiceEndpoint is a single service endpoint (service connection)
+type ServiceEndpoint struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	URL         string `json:"url"`
+	Description string `json:"description"`
+	Owner       string `json:"owner,omitempty"`
+	IsReady     bool   `json:"isReady"`
+	IsShared    bool   `json:"isShared,omitempty"`
+
+	// Data is the map of (non-secret) type-specific settings for the endpoint
+	Data map[string]string `json:"data"`
+
+	// Authorization is the authorization scheme and its parameters.  TFS doesn't return secret parameters
+	Authorization EndpointAuthorization `json:"authorization"`
+
+	CreatedBy struct {
+		DisplayName string `json:"displayName"`
+		UniqueName  string `json:"uniqueName"`
+		ID          string `json:"id"`
+	} `json:"createdBy,omitempty"`
+}
+
+// EndpointAuthorization is the authorization for a service endpoint
+type EndpointAuthorization struct {
+	Scheme     string            `json:"scheme"`
+	Parameters map[string]string `json:"parameters"`
+}
+
+// WithoutCredentials returns a copy of the endpoint with the values of all authorization parameters blanked out
+func (e ServiceEndpoint) WithoutCredentials() ServiceEndpoint {
+	retval := e
+	retval.Authorization.Parameters = map[string]string{}
+	for key := range e.Authorization.Parameters {
+		retval.Authorization.Parameters[key] = ""
+	}
+	return retval
+}
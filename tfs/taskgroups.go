@@ -0,0 +1,214 @@
+package tfs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TaskGroupsResponse defines the response recieved when querying task groups
+type TaskGroupsResponse struct {
+	Count      int         `json:"count"`
+	TaskGroups []TaskGroup `json:"value"`
+}
+
+// TaskGroup is a single version of a task group
+type TaskGroup struct {
+	ID                 string      `json:"id,omitempty"`
+	Name               string      `json:"name"`
+	FriendlyName       string      `json:"friendlyName,omitempty"`
+	Description        string      `json:"description"`
+	Category           string      `json:"category"`
+	Author             string      `json:"author,omitempty"`
+	IconURL            string      `json:"iconUrl,omitempty"`
+	InstanceNameFormat string      `json:"instanceNameFormat"`
+	Comment            string      `json:"comment,omitempty"`
+	Revision           int         `json:"revision,omitempty"`
+	ParentDefinitionID string      `json:"parentDefinitionId,omitempty"`
+	Deleted            bool        `json:"deleted,omitempty"`
+	Version            TaskVersion `json:"version"`
+
+	RunsOn  []string      `json:"runsOn,omitempty"`
+	Demands []interface{} `json:"demands,omitempty"`
+
+	// Inputs are the parameters of the task group
+	Inputs []map[string]interface{} `json:"inputs"`
+
+	// Tasks are the steps in the task group
+	Tasks []TaskGroupStep `json:"tasks"`
+
+	CreatedOn  time.Time `json:"createdOn"`
+	ModifiedOn time.Time `json:"modifiedOn"`
+}
+
+// TaskVersion is the version of a task (or task group)
+type TaskVersion struct {
+	Major  int  `json:"major"`
+	Minor  int  `json:"minor"`
+	Patch  int  `json:"patch"`
+	IsTest bool `json:"isTest"`
+}
+
+// String formats the version (like 2.0.1)
+func (v TaskVersion) String() string {
+	return fmt.Sprintf("%v.%v.%v", v.Major, v.Minor, v.Patch)
+}
+
+// Less returns true if the version comes before the other version
+func (v TaskVersion) Less(other TaskVersion) bool {
+	if v.Major != other.Major {
+		return v.Major < other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor < other.Minor
+	}
+	return v.Patch < other.Patch
+}
+
+// TaskGroupStep is a single step in a task group
+type TaskGroupStep struct {
+	DisplayName      string            `json:"displayName"`
+	Enabled          bool              `json:"enabled"`
+	AlwaysRun        bool              `json:"alwaysRun"`
+	ContinueOnError  bool              `json:"continueOnError"`
+	TimeoutInMinutes int               `json:"timeoutInMinutes"`
+	Condition        string            `json:"condition,omitempty"`
+	Inputs           map[string]string `json:"inputs"`
+	Environment      map[string]string `json:"environment,omitempty"`
+
+	Task TaskDefinitionReference `json:"task"`
+}
+
+// TaskDefinitionReference is a reference to the task (or nested task group) run by a step
+type TaskDefinitionReference struct {
+	ID          string `json:"id"`
+	VersionSpec string `json:"versionSpec"`
+
+	// DefinitionType is 'task' for a task, or 'metaTask' for a nested task group
+	DefinitionType string `json:"definitionType"`
+}
+
+// IsTaskGroup returns true if the step runs a nested task group
+func (s TaskGroupStep) IsTaskGroup() bool {
+	return s.Task.DefinitionType == "metaTask"
+}
+
+// SortTaskGroupVersions sorts the versions of a task group from oldest to newest
+func SortTaskGroupVersions(versions []TaskGroup) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].Version.Less(versions[j].Version)
+	})
+}
+
+// RemapTaskGroupReferences points the nested task group steps at new task group ids.  The ids of nested
+// task groups that aren't in the map are returned
+func RemapTaskGroupReferences(taskGroup *TaskGroup, newIDs map[string]string) []string {
+	missing := []string{}
+
+	for i := range taskGroup.Tasks {
+		step := &taskGroup.Tasks[i]
+		if !step.IsTaskGroup() {
+			continue
+		}
+
+		newID, ok := newIDs[step.Task.ID]
+		if !ok {
+			missing = append(missing, step.Task.ID)
+			continue
+		}
+		step.Task.ID = newID
+	}
+
+	return missing
+}
+
+// LatestTaskGroupVersions reduces a list of task group versions to the latest version of each task group
+func LatestTaskGroupVersions(versions []TaskGroup) []TaskGroup {
+	retval := []TaskGroup{}
+	index := map[string]int{}
+
+	for _, version := range versions {
+		i, ok := index[version.ID]
+		if !ok {
+			index[version.ID] = len(retval)
+			retval = append(retval, version)
+			continue
+		}
+
+		if retval[i].Version.Less(version.Version) {
+			retval[i] = version
+		}
+	}
+
+	return retval
+}
+
+// TaskGroupExport is a set of task groups (with their complete version history) that can be imported into
+// another project or collection
+type TaskGroupExport struct {
+	TaskGroups []TaskGroupHistory `json:"taskGroups"`
+
+	// References maps the id of each nested task group to its name, so references can be remapped on import
+	References map[string]string `json:"references"`
+}
+
+// TaskGroupHistory is every version of a single task group, from oldest to newest
+type TaskGroupHistory struct {
+	Name     string      `json:"name"`
+	Versions []TaskGroup `json:"versions"`
+}
+
+// NestedTaskGroupIDs gets the ids of the task groups referenced by any version in the history
+func (h TaskGroupHistory) NestedTaskGroupIDs() []string {
+	retval := []string{}
+	seen := map[string]bool{}
+
+	for _, version := range h.Versions {
+		for _, step := range version.Tasks {
+			if step.IsTaskGroup() && !seen[step.Task.ID] {
+				seen[step.Task.ID] = true
+				retval = append(retval, step.Task.ID)
+			}
+		}
+	}
+
+	return retval
+}
+
+// ImportOrder orders the task groups in the export so that nested task groups come before the
+// task groups that use them
+func (e TaskGroupExport) ImportOrder() []TaskGroupHistory {
+	retval := []TaskGroupHistory{}
+	added := map[string]bool{}
+	visiting := map[string]bool{}
+
+	byName := map[string]TaskGroupHistory{}
+	for _, history := range e.TaskGroups {
+		byName[strings.ToLower(history.Name)] = history
+	}
+
+	var visit func(history TaskGroupHistory)
+	visit = func(history TaskGroupHistory) {
+		key := strings.ToLower(history.Name)
+		if added[key] || visiting[key] {
+			return
+		}
+		visiting[key] = true
+
+		for _, id := range history.NestedTaskGroupIDs() {
+			if nested, ok := byName[strings.ToLower(e.References[id])]; ok {
+				visit(nested)
+			}
+		}
+
+		added[key] = true
+		retval = append(retval, history)
+	}
+
+	for _, history := range e.TaskGroups {
+		visit(history)
+	}
+
+	return retval
+}
@@ -0,0 +1,118 @@
+package tfs_test
+
+import (
+	"testing"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// Task group versions should be sorted by number (so 10.0.0 comes after 2.0.0), oldest first
+func TestSortTaskGroupVersions_Unsorted_SortsOldestFirst(t *testing.T) {
+
+	//	Arrange
+	versions := []tfs.TaskGroup{
+		{Name: "Deploy", Version: tfs.TaskVersion{Major: 2}},
+		{Name: "Deploy", Version: tfs.TaskVersion{Major: 1, Minor: 1}},
+		{Name: "Deploy", Version: tfs.TaskVersion{Major: 10}},
+		{Name: "Deploy", Version: tfs.TaskVersion{Major: 1}},
+	}
+	expected := []string{"1.0.0", "1.1.0", "2.0.0", "10.0.0"}
+
+	//	Act
+	tfs.SortTaskGroupVersions(versions)
+
+	//	Assert
+	for i, version := range versions {
+		if version.Version.String() != expected[i] {
+			t.Errorf("Version %v - expected %s but got %s", i, expected[i], version.Version)
+		}
+	}
+}
+
+// Only the latest version of each task group should be kept
+func TestLatestTaskGroupVersions_SeveralVersions_ReturnsLatestOfEach(t *testing.T) {
+
+	//	Arrange
+	versions := []tfs.TaskGroup{
+		{ID: "a", Name: "Deploy", Version: tfs.TaskVersion{Major: 1}},
+		{ID: "b", Name: "Smoke test", Version: tfs.TaskVersion{Major: 1}},
+		{ID: "a", Name: "Deploy", Version: tfs.TaskVersion{Major: 3}},
+		{ID: "a", Name: "Deploy", Version: tfs.TaskVersion{Major: 2}},
+	}
+
+	//	Act
+	latest := tfs.LatestTaskGroupVersions(versions)
+
+	//	Assert
+	if len(latest) != 2 {
+		t.Fatalf("Expected 2 task groups but got %v", len(latest))
+	}
+
+	if latest[0].ID != "a" || latest[0].Version.Major != 3 {
+		t.Errorf("Expected version 3 of 'a' but got version %v of '%s'", latest[0].Version.Major, latest[0].ID)
+	}
+}
+
+// References to nested task groups should point to their new ids, and the ones without a new id should be reported
+func TestRemapTaskGroupReferences_NestedGroups_RemapsKnownAndReportsMissing(t *testing.T) {
+
+	//	Arrange
+	taskGroup := tfs.TaskGroup{
+		Name: "Deploy",
+		Tasks: []tfs.TaskGroupStep{
+			{DisplayName: "Copy files", Task: tfs.TaskDefinitionReference{ID: "task-1", DefinitionType: "task"}},
+			{DisplayName: "Smoke test", Task: tfs.TaskDefinitionReference{ID: "old-1", DefinitionType: "metaTask"}},
+			{DisplayName: "Notify", Task: tfs.TaskDefinitionReference{ID: "old-2", DefinitionType: "metaTask"}},
+		},
+	}
+	newIDs := map[string]string{"old-1": "new-1"}
+
+	//	Act
+	missing := tfs.RemapTaskGroupReferences(&taskGroup, newIDs)
+
+	//	Assert
+	if taskGroup.Tasks[0].Task.ID != "task-1" {
+		t.Errorf("Expected the task to be left alone, but got '%s'", taskGroup.Tasks[0].Task.ID)
+	}
+
+	if taskGroup.Tasks[1].Task.ID != "new-1" {
+		t.Errorf("Expected the nested task group to be remapped to 'new-1', but got '%s'", taskGroup.Tasks[1].Task.ID)
+	}
+
+	if len(missing) != 1 || missing[0] != "old-2" {
+		t.Errorf("Expected 'old-2' to be reported missing, but got %v", missing)
+	}
+}
+
+// Task groups should be imported after the task groups they use
+func TestTaskGroupExportImportOrder_NestedGroups_NestedComeFirst(t *testing.T) {
+
+	//	Arrange
+	export := tfs.TaskGroupExport{
+		TaskGroups: []tfs.TaskGroupHistory{
+			{Name: "Deploy", Versions: []tfs.TaskGroup{
+				{Tasks: []tfs.TaskGroupStep{{Task: tfs.TaskDefinitionReference{ID: "smoke", DefinitionType: "metaTask"}}}},
+			}},
+			{Name: "Smoke test", Versions: []tfs.TaskGroup{
+				{Tasks: []tfs.TaskGroupStep{{Task: tfs.TaskDefinitionReference{ID: "ping", DefinitionType: "metaTask"}}}},
+			}},
+			{Name: "Ping", Versions: []tfs.TaskGroup{{}}},
+		},
+		References: map[string]string{"smoke": "Smoke test", "ping": "Ping"},
+	}
+	expected := []string{"Ping", "Smoke test", "Deploy"}
+
+	//	Act
+	ordered := export.ImportOrder()
+
+	//	Assert
+	if len(ordered) != len(expected) {
+		t.Fatalf("Expected %v task groups but got %v", len(expected), len(ordered))
+	}
+
+	for i, history := range ordered {
+		if history.Name != expected[i] {
+			t.Errorf("Position %v - expected '%s' but got '%s'", i, expected[i], history.Name)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package tfs
+
+// TeamsResponse defines the response recieved when querying teams
+type TeamsResponse struct {
+	Count int    `json:"count"`
+	Teams []Team `json:"value"`
+}
+
+// Team is a single team in a project
+type Team struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	URL         string `json:"url,omitempty"`
+}
+
+// TeamMembersResponse defines the response recieved when querying the members of a team
+type TeamMembersResponse struct {
+	Count   int          `json:"count"`
+	Members []TeamMember `json:"value"`
+}
+
+// TeamMember is a single member of a team
+type TeamMember struct {
+	Identity    IdentityRef `json:"identity"`
+	IsTeamAdmin bool        `json:"isTeamAdmin"`
+}
+
+// TeamFieldValues are the area paths owned by a team
+type TeamFieldValues struct {
+	DefaultValue string           `json:"defaultValue"`
+	Values       []TeamFieldValue `json:"values"`
+}
+
+// TeamFieldValue is a single area path owned by a team
+type TeamFieldValue struct {
+	Value           string `json:"value"`
+	IncludeChildren bool   `json:"includeChildren"`
+}
+
+// TeamSettingsIteration is a single iteration selected for a team
+type TeamSettingsIteration struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+	Path string `json:"path,omitempty"`
+}
@@ -0,0 +1,698 @@
+// Package tfstest provides an in-memory fake TFS server for tests and offline demos.
+//
+// The fake server implements the project collections, projects (including creating and
+// deleting them), processes, operations and variable group endpoints.  It checks the
+// personal access token, pages its results the way TFS does, and has hooks for injecting
+// errors and latency:
+//
+//	server := tfstest.NewServer()
+//	defer server.Close()
+//
+//	server.AddProject("DefaultCollection", tfs.Project{Name: "Website"})
+//	client := server.Client()
+//	projects, err := client.GetListOfProjects("DefaultCollection")
+package tfstest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/xid"
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// PAT is the personal access token the fake server accepts, unless Server.PAT is changed
+const PAT = "tfstest-pat"
+
+// Hook is called before the fake server handles each request.  If it returns true, the hook has
+// written the response and the request isn't handled any further.  Hooks are called one at a time,
+// and can call the server's methods
+type Hook func(w http.ResponseWriter, r *http.Request) bool
+
+// Server is an in-memory fake TFS server
+type Server struct {
+	*httptest.Server
+
+	// PAT is the personal access token the server accepts
+	PAT string
+
+	// PageSize is the most items returned in one page.  Zero means everything is returned in one page
+	PageSize int
+
+	mu             sync.Mutex
+	hookMu         sync.Mutex
+	previousPAT    interface{}
+	patChanged     bool
+	latency        time.Duration
+	hooks          []Hook
+	requests       []string
+	collections    []tfs.ProjectCollection
+	projects       map[string][]tfs.Project
+	variableGroups map[string][]tfs.VariableGroup
+	secrets        map[int]map[string]string
+	operations     map[string]tfs.OperationReference
+	nextGroupID    int
+}
+
+// Processes are the processes (process templates) every fake collection has.  Agile is the default
+var Processes = []tfs.Process{
+	{ID: "adcc42ab-9882-485e-a3ed-7678f01f66bc", Name: "Agile", IsDefault: true},
+	{ID: "6b724908-ef14-45cf-84f8-768b5384da45", Name: "Scrum"},
+	{ID: "27450541-8e31-4150-9947-dc59f998fc01", Name: "CMMI"},
+}
+
+// NewServer starts a new, empty fake TFS server.  Call Close when you're done with it
+func NewServer() *Server {
+	server := &Server{
+		PAT:            PAT,
+		projects:       map[string][]tfs.Project{},
+		variableGroups: map[string][]tfs.VariableGroup{},
+		secrets:        map[int]map[string]string{},
+		operations:     map[string]tfs.OperationReference{},
+		nextGroupID:    1,
+	}
+
+	server.Server = httptest.NewServer(http.HandlerFunc(server.handle))
+	return server
+}
+
+// NewServerWithGroup starts a fake server with the Website project in DefaultCollection, and a "Unicorn
+// variables" group in it (Horn=sparkly and Mane=rainbow).  It's the starting point for most tests
+func NewServerWithGroup() *Server {
+	server := NewServer()
+	server.AddProject("DefaultCollection", tfs.Project{Name: "Website"})
+	server.AddVariableGroup("DefaultCollection", "Website", tfs.VariableGroup{
+		Name: "Unicorn variables",
+		Variables: map[string]tfs.Variable{
+			"Horn": {Value: "sparkly"},
+			"Mane": {Value: "rainbow"},
+		},
+	})
+	return server
+}
+
+// Client gets a tfs.Client that talks to the fake server.  The tfs package reads the personal access
+// token from viper, so this sets the 'pat' setting to the server's PAT.  Close puts the old setting back
+func (s *Server) Client() tfs.Client {
+	s.mu.Lock()
+	if !s.patChanged {
+		s.previousPAT, s.patChanged = viper.Get("pat"), true
+	}
+	s.mu.Unlock()
+
+	viper.Set("pat", s.PAT)
+
+	return tfs.Client{
+		TfsURL: s.URL,
+	}
+}
+
+// Close stops the server, and puts back the 'pat' setting Client changed
+func (s *Server) Close() {
+	s.Server.Close()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.patChanged {
+		viper.Set("pat", s.previousPAT)
+		s.patChanged = false
+	}
+}
+
+// AddProject adds a project to a collection, and returns it (with an id, if it didn't have one)
+func (s *Server) AddProject(collection string, project tfs.Project) tfs.Project {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if project.ID == "" {
+		project.ID = xid.New().String()
+	}
+	if project.State == "" {
+		project.State = "wellFormed"
+	}
+
+	key := strings.ToLower(collection)
+	if _, found := s.projects[key]; !found {
+		s.collections = append(s.collections, tfs.ProjectCollection{ID: xid.New().String(), Name: collection})
+	}
+	s.projects[key] = append(s.projects[key], project)
+	return project
+}
+
+// Projects gets the projects in a collection, in the order they were added
+func (s *Server) Projects(collection string) []tfs.Project {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	projects := s.projects[strings.ToLower(collection)]
+	retval := make([]tfs.Project, len(projects))
+	copy(retval, projects)
+	return retval
+}
+
+// AddVariableGroup adds a variable group to a project, and returns it (with its new id)
+func (s *Server) AddVariableGroup(collection, project string, group tfs.VariableGroup) tfs.VariableGroup {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.addVariableGroup(collection, project, group)
+}
+
+// VariableGroups gets the variable groups in a project, in the order they were added
+func (s *Server) VariableGroups(collection, project string) []tfs.VariableGroup {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	groups := s.variableGroups[s.groupsKey(collection, project)]
+	retval := make([]tfs.VariableGroup, len(groups))
+	copy(retval, groups)
+	return retval
+}
+
+// SecretValue gets the value of a secret variable in a variable group.  The server never returns secret values,
+// but it keeps them so tests can check they weren't lost
+func (s *Server) SecretValue(groupID int, name string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	value, found := s.secrets[groupID][name]
+	return value, found
+}
+
+// Requests gets the method and path (like 'GET /DefaultCollection/_apis/projects') of every request
+// the server has handled
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	retval := make([]string, len(s.requests))
+	copy(retval, s.requests)
+	return retval
+}
+
+// AddHook adds a hook that is called before each request is handled
+func (s *Server) AddHook(hook Hook) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.hooks = append(s.hooks, hook)
+}
+
+// FailRequests makes every request whose method and path match the pattern (like 'POST .*/variablegroups')
+// fail with the given status code
+func (s *Server) FailRequests(pattern string, statusCode int) {
+	matcher := regexp.MustCompile(pattern)
+
+	s.AddHook(func(w http.ResponseWriter, r *http.Request) bool {
+		if !matcher.MatchString(r.Method + " " + r.URL.Path) {
+			return false
+		}
+		http.Error(w, http.StatusText(statusCode), statusCode)
+		return true
+	})
+}
+
+// FailNext makes the next request (only) fail with the given status code
+func (s *Server) FailNext(statusCode int) {
+	failed := false
+
+	s.AddHook(func(w http.ResponseWriter, r *http.Request) bool {
+		if failed {
+			return false
+		}
+		failed = true
+		http.Error(w, http.StatusText(statusCode), statusCode)
+		return true
+	})
+}
+
+// SetLatency makes the server wait before handling each request
+func (s *Server) SetLatency(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.latency = latency
+}
+
+// handle routes a single request
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+
+	s.mu.Lock()
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+	latency := s.latency
+	hooks := make([]Hook, len(s.hooks))
+	copy(hooks, s.hooks)
+	s.mu.Unlock()
+
+	if latency > 0 {
+		time.Sleep(latency)
+	}
+
+	//	Hooks run in the order they were added, one request at a time (so they can keep state).  They don't
+	//	hold the server's lock, so they can add projects and groups
+	if s.runHooks(hooks, w, r) {
+		return
+	}
+
+	if !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="tfstest"`)
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	//	Split the path into the collection, the (optional) project and the api path
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	apis := -1
+	for i, part := range parts {
+		if part == "_apis" {
+			apis = i
+			break
+		}
+	}
+
+	//	Project collections are listed from the server root
+	if len(parts) == 2 && parts[0] == "_apis" && parts[1] == "projectcollections" && r.Method == http.MethodGet {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		writeJSON(w, http.StatusOK, tfs.ProjectCollectionResponse{
+			Count:       len(s.collections),
+			Collections: append([]tfs.ProjectCollection{}, s.collections...),
+		})
+		return
+	}
+
+	if apis < 1 || apis > 2 {
+		http.NotFound(w, r)
+		return
+	}
+
+	collection := parts[0]
+	project := ""
+	if apis == 2 {
+		project = parts[1]
+	}
+	api := parts[apis+1:]
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case project == "" && len(api) == 1 && api[0] == "projects":
+		switch r.Method {
+		case http.MethodGet:
+			s.listProjects(w, r, collection)
+		case http.MethodPost:
+			s.createProject(w, r, collection)
+		default:
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		}
+	case project == "" && len(api) == 2 && api[0] == "projects" && r.Method == http.MethodDelete:
+		s.deleteProject(w, r, collection, api[1])
+	case project == "" && len(api) == 2 && api[0] == "process" && api[1] == "processes" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, tfs.ProcessesResponse{Count: len(Processes), Processes: append([]tfs.Process{}, Processes...)})
+	case project == "" && len(api) == 2 && api[0] == "operations" && r.Method == http.MethodGet:
+		operation, found := s.operations[api[1]]
+		if !found {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(w, http.StatusOK, operation)
+	case project != "" && len(api) == 2 && api[0] == "distributedtask" && api[1] == "variablegroups":
+		switch r.Method {
+		case http.MethodGet:
+			s.listVariableGroups(w, r, collection, project)
+		case http.MethodPost:
+			s.createVariableGroup(w, r, collection, project)
+		default:
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		}
+	case project != "" && len(api) == 3 && api[0] == "distributedtask" && api[1] == "variablegroups":
+		id, err := strconv.Atoi(api[2])
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			s.getVariableGroup(w, r, collection, project, id)
+		case http.MethodPut:
+			s.updateVariableGroup(w, r, collection, project, id)
+		case http.MethodDelete:
+			s.deleteVariableGroup(w, r, collection, project, id)
+		default:
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// runHooks runs the hooks until one of them handles the request, and returns true if one did
+func (s *Server) runHooks(hooks []Hook, w http.ResponseWriter, r *http.Request) bool {
+	s.hookMu.Lock()
+	defer s.hookMu.Unlock()
+
+	for _, hook := range hooks {
+		if hook(w, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// authorized returns true if the request has the server's PAT
+func (s *Server) authorized(r *http.Request) bool {
+	expected := "Basic " + base64.StdEncoding.EncodeToString([]byte(":"+s.PAT))
+	return r.Header.Get("Authorization") == expected
+}
+
+// listProjects lists the projects in a collection
+func (s *Server) listProjects(w http.ResponseWriter, r *http.Request, collection string) {
+	projects := s.projects[strings.ToLower(collection)]
+
+	start, end, next := s.page(r, len(projects), "$top")
+	writePage(w, next, tfs.ProjectResponse{
+		Count:    end - start,
+		Projects: append([]tfs.Project{}, projects[start:end]...),
+	})
+}
+
+// createProject creates a project.  Like TFS, it responds with a queued operation -- the operation
+// has already succeeded when it's checked
+func (s *Server) createProject(w http.ResponseWriter, r *http.Request, collection string) {
+	request := tfs.NewProject{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || strings.TrimSpace(request.Name) == "" {
+		http.Error(w, "A project name is required", http.StatusBadRequest)
+		return
+	}
+
+	known := false
+	for _, process := range Processes {
+		known = known || process.ID == request.Capabilities.ProcessTemplate.TemplateTypeID
+	}
+	if !known {
+		http.Error(w, "Unknown process template", http.StatusBadRequest)
+		return
+	}
+
+	if s.projectExists(collection, request.Name) {
+		http.Error(w, fmt.Sprintf("The project '%s' already exists", request.Name), http.StatusConflict)
+		return
+	}
+
+	key := strings.ToLower(collection)
+	if _, found := s.projects[key]; !found {
+		s.collections = append(s.collections, tfs.ProjectCollection{ID: xid.New().String(), Name: collection})
+	}
+	s.projects[key] = append(s.projects[key], tfs.Project{ID: xid.New().String(), Name: request.Name, Description: request.Description, State: "wellFormed"})
+
+	writeJSON(w, http.StatusAccepted, s.queueOperation(collection))
+}
+
+// deleteProject deletes a project (by id) and its variable groups
+func (s *Server) deleteProject(w http.ResponseWriter, r *http.Request, collection, id string) {
+	key := strings.ToLower(collection)
+	for i, project := range s.projects[key] {
+		if project.ID != id {
+			continue
+		}
+
+		groups := s.groupsKey(collection, project.Name)
+		for _, group := range s.variableGroups[groups] {
+			delete(s.secrets, group.ID)
+		}
+		delete(s.variableGroups, groups)
+		s.projects[key] = append(s.projects[key][:i], s.projects[key][i+1:]...)
+		writeJSON(w, http.StatusAccepted, s.queueOperation(collection))
+		return
+	}
+
+	http.NotFound(w, r)
+}
+
+// queueOperation records a (finished) operation, and returns it as it looked when it was queued.  The caller must hold the lock
+func (s *Server) queueOperation(collection string) tfs.OperationReference {
+	operation := tfs.OperationReference{ID: xid.New().String(), Status: "queued"}
+	operation.URL = s.URL + "/" + collection + "/_apis/operations/" + operation.ID
+
+	finished := operation
+	finished.Status = tfs.OperationSucceeded
+	s.operations[operation.ID] = finished
+
+	return operation
+}
+
+// listVariableGroups lists the variable groups in a project, filtered by the groupName parameter
+func (s *Server) listVariableGroups(w http.ResponseWriter, r *http.Request, collection, project string) {
+	if !s.projectExists(collection, project) {
+		http.NotFound(w, r)
+		return
+	}
+
+	matches := []tfs.VariableGroup{}
+	filter := r.URL.Query().Get("groupName")
+	for _, group := range s.variableGroups[s.groupsKey(collection, project)] {
+		if matchesGroupName(filter, group.Name) {
+			matches = append(matches, group)
+		}
+	}
+
+	start, end, next := s.page(r, len(matches), "top")
+	writePage(w, next, tfs.VariableGroupsResponse{
+		Count:          end - start,
+		VariableGroups: matches[start:end],
+	})
+}
+
+// getVariableGroup gets a single variable group
+func (s *Server) getVariableGroup(w http.ResponseWriter, r *http.Request, collection, project string, id int) {
+	i := s.findVariableGroup(collection, project, id)
+	if i < 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.variableGroups[s.groupsKey(collection, project)][i])
+}
+
+// createVariableGroup creates a variable group
+func (s *Server) createVariableGroup(w http.ResponseWriter, r *http.Request, collection, project string) {
+	if !s.projectExists(collection, project) {
+		http.NotFound(w, r)
+		return
+	}
+
+	group := tfs.VariableGroup{}
+	if err := json.NewDecoder(r.Body).Decode(&group); err != nil {
+		http.Error(w, fmt.Sprintf("Unable to read the variable group: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	if group.Name == "" {
+		http.Error(w, "The variable group needs a name", http.StatusBadRequest)
+		return
+	}
+
+	for _, existing := range s.variableGroups[s.groupsKey(collection, project)] {
+		if strings.EqualFold(existing.Name, group.Name) {
+			http.Error(w, fmt.Sprintf("A variable group named '%s' already exists", group.Name), http.StatusBadRequest)
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusOK, s.addVariableGroup(collection, project, group))
+}
+
+// updateVariableGroup replaces a variable group
+func (s *Server) updateVariableGroup(w http.ResponseWriter, r *http.Request, collection, project string, id int) {
+	i := s.findVariableGroup(collection, project, id)
+	if i < 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	group := tfs.VariableGroup{}
+	if err := json.NewDecoder(r.Body).Decode(&group); err != nil {
+		http.Error(w, fmt.Sprintf("Unable to read the variable group: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	groups := s.variableGroups[s.groupsKey(collection, project)]
+	group.ID = id
+	group.CreatedOn = groups[i].CreatedOn
+	group.ModifiedOn = time.Now().UTC()
+	s.secrets[id] = secretValues(group, s.secrets[id])
+	groups[i] = withoutSecretValues(group)
+
+	writeJSON(w, http.StatusOK, groups[i])
+}
+
+// deleteVariableGroup deletes a variable group
+func (s *Server) deleteVariableGroup(w http.ResponseWriter, r *http.Request, collection, project string, id int) {
+	i := s.findVariableGroup(collection, project, id)
+	if i < 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	key := s.groupsKey(collection, project)
+	s.variableGroups[key] = append(s.variableGroups[key][:i], s.variableGroups[key][i+1:]...)
+	delete(s.secrets, id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// addVariableGroup adds a variable group.  The caller must hold the lock
+func (s *Server) addVariableGroup(collection, project string, group tfs.VariableGroup) tfs.VariableGroup {
+	group.ID = s.nextGroupID
+	s.nextGroupID++
+
+	if group.Type == "" {
+		group.Type = "Vsts"
+	}
+	group.CreatedOn = time.Now().UTC()
+	group.ModifiedOn = group.CreatedOn
+	s.secrets[group.ID] = secretValues(group, nil)
+	group = withoutSecretValues(group)
+
+	key := s.groupsKey(collection, project)
+	s.variableGroups[key] = append(s.variableGroups[key], group)
+	return group
+}
+
+// findVariableGroup finds the index of a variable group, or -1.  The caller must hold the lock
+func (s *Server) findVariableGroup(collection, project string, id int) int {
+	for i, group := range s.variableGroups[s.groupsKey(collection, project)] {
+		if group.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// projectExists returns true if the project (by name or id) is in the collection.  The caller must hold the lock
+func (s *Server) projectExists(collection, project string) bool {
+	for _, item := range s.projects[strings.ToLower(collection)] {
+		if strings.EqualFold(item.Name, project) || item.ID == project {
+			return true
+		}
+	}
+	return false
+}
+
+// page works out which items go in the requested page.  The continuation token is the index of the
+// first item of the next page (blank if there isn't one)
+func (s *Server) page(r *http.Request, total int, topParameter string) (int, int, string) {
+	start, _ := strconv.Atoi(r.URL.Query().Get("continuationToken"))
+	if start < 0 || start > total {
+		start = total
+	}
+
+	size := s.PageSize
+	if top, err := strconv.Atoi(r.URL.Query().Get(topParameter)); err == nil && top > 0 && (size == 0 || top < size) {
+		size = top
+	}
+
+	end := total
+	if size > 0 && start+size < total {
+		end = start + size
+	}
+
+	next := ""
+	if end < total {
+		next = strconv.Itoa(end)
+	}
+
+	return start, end, next
+}
+
+// matchesGroupName returns true if a group name matches a groupName filter.  The filter can use * as a wildcard
+func matchesGroupName(filter, name string) bool {
+	if filter == "" {
+		return true
+	}
+
+	matched, err := path.Match(strings.ToLower(filter), strings.ToLower(name))
+	return err == nil && matched
+}
+
+// secretValues gets the values of a group's secret variables.  Like TFS, a secret that's sent without a value
+// (null or empty) keeps the value it had
+func secretValues(group tfs.VariableGroup, previous map[string]string) map[string]string {
+	retval := map[string]string{}
+	for name, variable := range group.Variables {
+		if !variable.IsSecret {
+			continue
+		}
+
+		if variable.Value != "" {
+			retval[name] = variable.Value
+		} else if value, found := previous[name]; found {
+			retval[name] = value
+		}
+	}
+	return retval
+}
+
+// withoutSecretValues blanks the values of secret variables, since TFS never returns them
+func withoutSecretValues(group tfs.VariableGroup) tfs.VariableGroup {
+	variables := map[string]tfs.Variable{}
+	for name, variable := range group.Variables {
+		if variable.IsSecret {
+			variable.Value = ""
+		}
+		variables[name] = variable
+	}
+	group.Variables = variables
+	return group
+}
+
+// groupsKey is the key of the variable groups for a project.  The project can be its name or id (like in a
+// request path), so both find the same groups.  The caller must hold the lock
+func (s *Server) groupsKey(collection, project string) string {
+	for _, item := range s.projects[strings.ToLower(collection)] {
+		if item.ID == project {
+			project = item.Name
+			break
+		}
+	}
+
+	return strings.ToLower(collection + "/" + project)
+}
+
+// writePage writes a page of results, with the continuation token header if there's another page
+func writePage(w http.ResponseWriter, next string, page interface{}) {
+	if next != "" {
+		w.Header().Set("x-ms-continuationtoken", next)
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, statusCode int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(value)
+}
+
+// SortedGroupNames gets the (sorted) names of a list of variable groups.  It's a convenience for tests
+func SortedGroupNames(groups []tfs.VariableGroup) []string {
+	retval := []string{}
+	for _, group := range groups {
+		retval = append(retval, group.Name)
+	}
+	sort.Strings(retval)
+	return retval
+}
@@ -0,0 +1,481 @@
+package tfstest_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/danesparza/tfsutil/tfs"
+	"github.com/danesparza/tfsutil/tfs/tfstest"
+)
+
+// newTestServer starts a fake server with a single project
+func newTestServer() *tfstest.Server {
+	server := tfstest.NewServer()
+	server.AddProject("DefaultCollection", tfs.Project{Name: "Website"})
+	return server
+}
+
+// Projects should be returned in pages (like TFS does), and the client should get every page
+func TestGetListOfProjects_SeveralPages_ReturnsEveryProject(t *testing.T) {
+
+	//	Arrange
+	server := tfstest.NewServer()
+	defer server.Close()
+	server.PageSize = 2
+
+	expected := []string{"Alpha", "Bravo", "Charlie", "Delta", "Echo"}
+	for _, name := range expected {
+		server.AddProject("DefaultCollection", tfs.Project{Name: name})
+	}
+
+	client := server.Client()
+
+	//	Act
+	retval, err := client.GetListOfProjects("DefaultCollection")
+
+	//	Assert
+	if err != nil {
+		t.Fatalf("GetListOfProjects failed: %s", err)
+	}
+
+	names := []string{}
+	for _, project := range retval.Projects {
+		names = append(names, project.Name)
+	}
+
+	if !reflect.DeepEqual(expected, names) || retval.Count != len(expected) {
+		t.Errorf("Expected %v but got %v (count %v)", expected, names, retval.Count)
+	}
+
+	if len(server.Requests()) != 3 {
+		t.Errorf("Expected 3 page requests but got %v", server.Requests())
+	}
+}
+
+// Each collection with projects should be listed once, in the order it was first used
+func TestGetListOfProjectCollections_ProjectsInTwoCollections_ReturnsBoth(t *testing.T) {
+
+	//	Arrange
+	server := newTestServer()
+	defer server.Close()
+	server.AddProject("DefaultCollection", tfs.Project{Name: "Mobile"})
+	server.AddProject("Archive", tfs.Project{Name: "Legacy"})
+	client := server.Client()
+
+	//	Act
+	retval, err := client.GetListOfProjectCollections()
+
+	//	Assert
+	if err != nil {
+		t.Fatalf("GetListOfProjectCollections failed: %s", err)
+	}
+
+	if retval.Count != 2 || retval.Collections[0].Name != "DefaultCollection" || retval.Collections[1].Name != "Archive" {
+		t.Errorf("Expected DefaultCollection and Archive but got %+v", retval.Collections)
+	}
+}
+
+// Variable groups should be filtered by name, with wildcards, the way TFS filters them
+func TestGetListOfMatchingVariableGroups_Filter_ReturnsMatchingGroups(t *testing.T) {
+
+	//	Arrange
+	server := newTestServer()
+	defer server.Close()
+
+	server.AddVariableGroup("DefaultCollection", "Website", tfs.VariableGroup{Name: "Website - Dev"})
+	server.AddVariableGroup("DefaultCollection", "Website", tfs.VariableGroup{Name: "Website - Prod"})
+	server.AddVariableGroup("DefaultCollection", "Website", tfs.VariableGroup{Name: "Shared secrets"})
+
+	client := server.Client()
+
+	//	Act
+	wildcard, err := client.GetListOfMatchingVariableGroups("DefaultCollection", "Website", "website*")
+	if err != nil {
+		t.Fatalf("GetListOfMatchingVariableGroups failed: %s", err)
+	}
+
+	exact, err := client.GetListOfMatchingVariableGroups("DefaultCollection", "Website", "Shared secrets")
+	if err != nil {
+		t.Fatalf("GetListOfMatchingVariableGroups failed: %s", err)
+	}
+
+	all, err := client.GetListOfVariableGroups("DefaultCollection", "Website")
+	if err != nil {
+		t.Fatalf("GetListOfVariableGroups failed: %s", err)
+	}
+
+	//	Assert
+	if names := tfstest.SortedGroupNames(wildcard.VariableGroups); !reflect.DeepEqual(names, []string{"Website - Dev", "Website - Prod"}) {
+		t.Errorf("Expected the two Website groups but got %v", names)
+	}
+
+	if exact.Count != 1 || exact.VariableGroups[0].Name != "Shared secrets" {
+		t.Errorf("Expected only 'Shared secrets' but got %v", tfstest.SortedGroupNames(exact.VariableGroups))
+	}
+
+	if all.Count != 3 {
+		t.Errorf("Expected 3 groups but got %v", all.Count)
+	}
+}
+
+// Variable groups should be returned in pages, and the client should get every page
+func TestGetListOfVariableGroups_MoreThanOnePage_ReturnsEveryGroup(t *testing.T) {
+
+	//	Arrange
+	server := newTestServer()
+	defer server.Close()
+	server.PageSize = 50
+
+	for i := 0; i < 120; i++ {
+		server.AddVariableGroup("DefaultCollection", "Website", tfs.VariableGroup{Name: "Group " + strings.Repeat("x", i+1)})
+	}
+
+	client := server.Client()
+
+	//	Act
+	retval, err := client.GetListOfVariableGroups("DefaultCollection", "Website")
+
+	//	Assert
+	if err != nil {
+		t.Fatalf("GetListOfVariableGroups failed: %s", err)
+	}
+
+	if retval.Count != 120 || len(retval.VariableGroups) != 120 {
+		t.Errorf("Expected 120 groups but got %v (%v in the list)", retval.Count, len(retval.VariableGroups))
+	}
+}
+
+// Creating, updating and deleting a variable group should change what the server has (without returning secret values)
+func TestVariableGroups_CreateUpdateDelete_ChangesServerState(t *testing.T) {
+
+	//	Arrange
+	server := newTestServer()
+	defer server.Close()
+	client := server.Client()
+
+	newGroup := tfs.VariableGroup{
+		Name: "Unicorn variables",
+		Variables: map[string]tfs.Variable{
+			"Horn":     {Value: "sparkly"},
+			"Password": {Value: "hunter2", IsSecret: true},
+		},
+	}
+
+	//	Act
+	created, createErr := client.CreateVariableGroup("DefaultCollection", "Website", newGroup)
+
+	created.Description = "Updated"
+	created.Variables["Mane"] = tfs.Variable{Value: "rainbow"}
+	updated, updateErr := client.UpdateVariableGroup("DefaultCollection", "Website", created)
+	afterUpdate := server.VariableGroups("DefaultCollection", "Website")
+
+	deleteErr := client.DeleteVariableGroup("DefaultCollection", "Website", created.ID)
+	afterDelete := server.VariableGroups("DefaultCollection", "Website")
+
+	//	Assert
+	if createErr != nil || updateErr != nil || deleteErr != nil {
+		t.Fatalf("Expected no errors but got create: %v, update: %v, delete: %v", createErr, updateErr, deleteErr)
+	}
+
+	if created.ID == 0 || created.Variables["Password"].Value != "" {
+		t.Errorf("Expected a new id and no secret value, but got id %v and password '%s'", created.ID, created.Variables["Password"].Value)
+	}
+
+	if updated.Description != "Updated" || len(afterUpdate) != 1 || len(afterUpdate[0].Variables) != 3 {
+		t.Errorf("Expected the update to be stored, but got %+v", afterUpdate)
+	}
+
+	if len(afterDelete) != 0 {
+		t.Errorf("Expected the group to be deleted, but got %+v", afterDelete)
+	}
+}
+
+// Creating a project should queue an operation that finishes it, and deleting it should remove it
+func TestProjects_CreateWaitDelete_ChangesServerState(t *testing.T) {
+
+	//	Arrange
+	server := newTestServer()
+	defer server.Close()
+	client := server.Client()
+
+	//	Act
+	processes, processErr := client.GetListOfProcesses("DefaultCollection")
+	queued, createErr := client.CreateProject("DefaultCollection", tfs.NewGitProject("Mobile", "Apps", processes.Processes[0].ID))
+	finished, waitErr := client.WaitForOperation("DefaultCollection", queued, time.Millisecond, time.Second)
+	_, duplicateErr := client.CreateProject("DefaultCollection", tfs.NewGitProject("mobile", "", processes.Processes[0].ID))
+	afterCreate := server.Projects("DefaultCollection")
+
+	deleting, deleteErr := client.DeleteProject("DefaultCollection", afterCreate[1].ID)
+	afterDelete := server.Projects("DefaultCollection")
+
+	//	Assert
+	if processErr != nil || createErr != nil || waitErr != nil || deleteErr != nil {
+		t.Fatalf("Expected no errors but got processes: %v, create: %v, wait: %v, delete: %v", processErr, createErr, waitErr, deleteErr)
+	}
+
+	if queued.Status == tfs.OperationSucceeded || finished.Status != tfs.OperationSucceeded || deleting.ID == "" {
+		t.Errorf("Expected a queued operation that then succeeded, but got %+v and %+v", queued, finished)
+	}
+
+	if duplicateErr == nil {
+		t.Errorf("Expected an error creating a duplicate project")
+	}
+
+	if len(afterCreate) != 2 || afterCreate[1].Name != "Mobile" || afterCreate[1].Description != "Apps" {
+		t.Errorf("Expected the project to be created, but got %+v", afterCreate)
+	}
+
+	if len(afterDelete) != 1 || afterDelete[0].Name != "Website" {
+		t.Errorf("Expected the project to be deleted, but got %+v", afterDelete)
+	}
+}
+
+// A group with the same name as another (in any case) shouldn't be created
+func TestCreateVariableGroup_DuplicateName_ReturnsError(t *testing.T) {
+
+	//	Arrange
+	server := newTestServer()
+	defer server.Close()
+	server.AddVariableGroup("DefaultCollection", "Website", tfs.VariableGroup{Name: "Unicorn variables"})
+	client := server.Client()
+
+	//	Act
+	_, err := client.CreateVariableGroup("DefaultCollection", "Website", tfs.VariableGroup{Name: "unicorn variables"})
+
+	//	Assert
+	if err == nil {
+		t.Errorf("Expected an error creating a duplicate group")
+	}
+}
+
+// A request with the wrong PAT should be refused
+func TestGetListOfProjects_WrongPAT_ReturnsError(t *testing.T) {
+
+	//	Arrange
+	server := newTestServer()
+	defer server.Close()
+	client := server.Client()
+	viper.Set("pat", "not-the-right-pat")
+
+	//	Act
+	_, err := client.GetListOfProjects("DefaultCollection")
+
+	//	Assert
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("Expected a 401 error but got %v", err)
+	}
+}
+
+// Only the requests that match the pattern should fail
+func TestServer_FailRequests_InjectsErrors(t *testing.T) {
+
+	//	Arrange
+	server := newTestServer()
+	defer server.Close()
+	server.FailRequests(`^POST .*/variablegroups$`, http.StatusServiceUnavailable)
+	client := server.Client()
+
+	//	Act
+	_, createErr := client.CreateVariableGroup("DefaultCollection", "Website", tfs.VariableGroup{Name: "Unicorn variables"})
+	_, listErr := client.GetListOfVariableGroups("DefaultCollection", "Website")
+
+	//	Assert
+	if createErr == nil || !strings.Contains(createErr.Error(), "503") {
+		t.Errorf("Expected a 503 error creating the group but got %v", createErr)
+	}
+
+	if listErr != nil {
+		t.Errorf("Expected listing groups to work, but got %s", listErr)
+	}
+}
+
+// Only the next request should fail
+func TestServer_FailNext_FailsOnlyOnce(t *testing.T) {
+
+	//	Arrange
+	server := newTestServer()
+	defer server.Close()
+	server.FailNext(http.StatusInternalServerError)
+	client := server.Client()
+
+	//	Act
+	_, firstErr := client.GetListOfProjects("DefaultCollection")
+	_, secondErr := client.GetListOfProjects("DefaultCollection")
+
+	//	Assert
+	if firstErr == nil {
+		t.Errorf("Expected the first request to fail")
+	}
+
+	if secondErr != nil {
+		t.Errorf("Expected the second request to work, but got %s", secondErr)
+	}
+}
+
+// Responses should be delayed by the latency
+func TestServer_SetLatency_DelaysResponses(t *testing.T) {
+
+	//	Arrange
+	server := newTestServer()
+	defer server.Close()
+	server.SetLatency(50 * time.Millisecond)
+	client := server.Client()
+
+	//	Act
+	start := time.Now()
+	_, err := client.GetListOfProjects("DefaultCollection")
+	elapsed := time.Since(start)
+
+	//	Assert
+	if err != nil {
+		t.Fatalf("GetListOfProjects failed: %s", err)
+	}
+
+	if elapsed < 50*time.Millisecond {
+		t.Errorf("Expected the request to take at least 50ms, but it took %s", elapsed)
+	}
+}
+
+// Closing the server should put back the PAT the test started with
+func TestServer_Close_RestoresPAT(t *testing.T) {
+
+	//	Arrange
+	viper.Set("pat", "someone-elses-pat")
+	defer viper.Set("pat", nil)
+	server := newTestServer()
+	server.Client()
+
+	//	Act
+	server.Close()
+
+	//	Assert
+	if pat := viper.GetString("pat"); pat != "someone-elses-pat" {
+		t.Errorf("Expected the PAT to be put back, but got '%s'", pat)
+	}
+}
+
+// A hook should be able to change the server (without waiting for the server's lock)
+func TestServer_AddHook_HookCanChangeServer(t *testing.T) {
+
+	//	Arrange
+	server := newTestServer()
+	defer server.Close()
+	server.AddHook(func(w http.ResponseWriter, r *http.Request) bool {
+		if len(server.Projects("DefaultCollection")) == 1 {
+			server.AddProject("DefaultCollection", tfs.Project{Name: "Added by a hook"})
+		}
+		return false
+	})
+	client := server.Client()
+
+	//	Act
+	retval, err := client.GetListOfProjects("DefaultCollection")
+
+	//	Assert
+	if err != nil {
+		t.Fatalf("GetListOfProjects failed: %s", err)
+	}
+
+	if retval.Count != 2 {
+		t.Errorf("Expected the hook's project to be listed, but got %v projects", retval.Count)
+	}
+}
+
+// Changing a group that was read from TFS (like vg set does) keeps the values of its secrets, which TFS
+// never returns, and sends them as null so real TFS keeps them too
+func TestUpdateVariableGroup_SecretsWithoutValues_KeepTheirValues(t *testing.T) {
+
+	//	Arrange
+	server := newTestServer()
+	defer server.Close()
+	stored := server.AddVariableGroup("DefaultCollection", "Website", tfs.VariableGroup{
+		Name: "Unicorn variables",
+		Variables: map[string]tfs.Variable{
+			"Horn":     {Value: "sparkly"},
+			"Password": {Value: "hunter2", IsSecret: true},
+			"ApiKey":   {Value: "abc123", IsSecret: true},
+		},
+	})
+
+	sent := ""
+	server.AddHook(func(w http.ResponseWriter, r *http.Request) bool {
+		if r.Method == http.MethodPut {
+			body, _ := ioutil.ReadAll(r.Body)
+			sent = string(body)
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+		return false
+	})
+	client := server.Client()
+
+	//	Act
+	group, findErr := client.FindVariableGroup("DefaultCollection", "Website", "Unicorn variables")
+	group.Variables["Mane"] = tfs.Variable{Value: "rainbow"}
+	group.Variables["ApiKey"] = tfs.Variable{Value: "xyz789", IsSecret: true}
+	_, updateErr := client.UpdateVariableGroup("DefaultCollection", "Website", group)
+
+	//	Assert
+	if findErr != nil || updateErr != nil {
+		t.Fatalf("Expected no errors but got find: %v, update: %v", findErr, updateErr)
+	}
+
+	if !strings.Contains(sent, `"Password":{"value":null`) {
+		t.Errorf("Expected the secret without a value to be sent as null, but sent %s", sent)
+	}
+
+	if password, _ := server.SecretValue(stored.ID, "Password"); password != "hunter2" {
+		t.Errorf("Expected the password to be kept, but got '%s'", password)
+	}
+
+	if apiKey, _ := server.SecretValue(stored.ID, "ApiKey"); apiKey != "xyz789" {
+		t.Errorf("Expected the new api key to be stored, but got '%s'", apiKey)
+	}
+}
+
+// A project's variable groups should be the same whether the project is given by name or by id, and deleting the
+// project by id should delete its groups
+func TestVariableGroups_ProjectByID_SameGroupsAsByName(t *testing.T) {
+
+	//	Arrange
+	server := tfstest.NewServerWithGroup()
+	defer server.Close()
+	client := server.Client()
+	website := server.Projects("DefaultCollection")[0]
+
+	//	Act
+	byID, listErr := client.GetListOfVariableGroups("DefaultCollection", website.ID)
+	created, createErr := client.CreateVariableGroup("DefaultCollection", website.ID, tfs.VariableGroup{
+		Name:      "Dragon variables",
+		Variables: map[string]tfs.Variable{"Wings": {Value: "leathery", IsSecret: true}},
+	})
+	_, secretStored := server.SecretValue(created.ID, "Wings")
+	byName := server.VariableGroups("DefaultCollection", "website")
+
+	_, deleteErr := client.DeleteProject("DefaultCollection", website.ID)
+	afterDelete := server.VariableGroups("DefaultCollection", "Website")
+	_, secretsKept := server.SecretValue(created.ID, "Wings")
+
+	//	Assert
+	if listErr != nil || createErr != nil || deleteErr != nil {
+		t.Fatalf("Expected no errors but got list: %v, create: %v, delete: %v", listErr, createErr, deleteErr)
+	}
+
+	if byID.Count != 1 || byID.VariableGroups[0].Name != "Unicorn variables" {
+		t.Errorf("Expected the project's group by its id, but got %+v", byID)
+	}
+
+	if names := tfstest.SortedGroupNames(byName); len(names) != 2 || names[0] != "Dragon variables" {
+		t.Errorf("Expected the group created by id to be in the project, but got %v", names)
+	}
+
+	if len(afterDelete) != 0 || !secretStored || secretsKept {
+		t.Errorf("Expected the project's groups to be deleted with it, but got %+v", afterDelete)
+	}
+}
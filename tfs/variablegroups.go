@@ -1,7 +1,14 @@
 package tfs
 
 import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
 	"time"
+
+	"github.com/rs/xid"
 )
 
 // VariableGroupsResponse defines the response recieved when querying variable groups
@@ -16,9 +23,13 @@ type VariableGroup struct {
 	Variables map[string]Variable `json:"variables"`
 	ID        int                 `json:"id,omitempty"`
 
-	// Type is the variable group type.  By default this should be 'Vsts'
+	// Type is the variable group type.  By default this should be 'Vsts'.  Groups linked to an
+	// Azure Key Vault are 'AzureKeyVault'
 	Type string `json:"type"`
 
+	// ProviderData is the Key Vault (and the service endpoint used to reach it) for 'AzureKeyVault' groups
+	ProviderData *VariableGroupProviderData `json:"providerData,omitempty"`
+
 	// Name is the name of the variable group
 	Name string `json:"name"`
 
@@ -56,7 +67,194 @@ type VariableGroup struct {
 	Description string `json:"description"`
 }
 
-// Variable defines a single variable in a variable group
+// VariableGroupProviderData is the Azure Key Vault linked to a variable group
+type VariableGroupProviderData struct {
+	ServiceEndpointID string `json:"serviceEndpointId"`
+	Vault             string `json:"vault"`
+}
+
+// IsKeyVault returns true if the variables in the group come from an Azure Key Vault
+func (g VariableGroup) IsKeyVault() bool {
+	return strings.EqualFold(g.Type, "AzureKeyVault")
+}
+
+// Provider describes where the variables in the group come from (like Vsts, or AzureKeyVault: vault-name)
+func (g VariableGroup) Provider() string {
+	if g.IsKeyVault() && g.ProviderData != nil {
+		return fmt.Sprintf("%s: %s", g.Type, g.ProviderData.Vault)
+	}
+	if g.Type == "" {
+		return "Vsts"
+	}
+	return g.Type
+}
+
+// Copy creates a new group (without an id) with the given name and the same description, provider and variables
+func (g VariableGroup) Copy(name string) VariableGroup {
+	retval := VariableGroup{
+		Name:         name,
+		Description:  g.Description,
+		Type:         g.Type,
+		ProviderData: g.ProviderData,
+		Variables:    map[string]Variable{},
+	}
+
+	for key, variable := range g.Variables {
+		retval.Variables[key] = variable
+	}
+
+	return retval
+}
+
+// CopyName makes a name for a copy of a group that's a bit unique, like 'Copy of Unicorn variables (9m4e2mr0ui3e8a215n4g)'
+func CopyName(name string) string {
+	return fmt.Sprintf("Copy of %s (%s)", name, xid.New().String())
+}
+
+// VariableGroupExport is the format of an exported variable group.  Key Vault linked groups also carry the
+// name of their service endpoint, so the endpoint can be found again in another project or collection
+type VariableGroupExport struct {
+	VariableGroup
+	ServiceEndpointName string `json:"serviceEndpointName,omitempty"`
+}
+
+// The kinds of differences between two variable groups
+const (
+	VariableAdded   = "added"
+	VariableRemoved = "removed"
+	VariableChanged = "changed"
+)
+
+// VariableDifference is a variable that's different in two variable groups
+type VariableDifference struct {
+	Name   string    `json:"name"`
+	Change string    `json:"change"`
+	From   *Variable `json:"from,omitempty"`
+	To     *Variable `json:"to,omitempty"`
+}
+
+// DiffVariableGroups compares the variables in two groups, and returns the differences sorted by name.
+// TFS doesn't return secret values, so secrets only differ if one of them isn't secret
+func DiffVariableGroups(from, to VariableGroup) []VariableDifference {
+	retval := []VariableDifference{}
+
+	for name, fromVariable := range from.Variables {
+		fromVariable := fromVariable
+		toVariable, found := to.Variables[name]
+		switch {
+		case !found:
+			retval = append(retval, VariableDifference{Name: name, Change: VariableRemoved, From: &fromVariable})
+		case fromVariable.Value != toVariable.Value || fromVariable.IsSecret != toVariable.IsSecret:
+			retval = append(retval, VariableDifference{Name: name, Change: VariableChanged, From: &fromVariable, To: &toVariable})
+		}
+	}
+
+	for name, toVariable := range to.Variables {
+		toVariable := toVariable
+		if _, found := from.Variables[name]; !found {
+			retval = append(retval, VariableDifference{Name: name, Change: VariableAdded, To: &toVariable})
+		}
+	}
+
+	sort.Slice(retval, func(i, j int) bool { return retval[i].Name < retval[j].Name })
+	return retval
+}
+
+// VariableMatch is a variable that matched a search
+type VariableMatch struct {
+	Project  string `json:"project"`
+	Group    string `json:"group"`
+	Variable string `json:"variable"`
+	Value    string `json:"value"`
+	IsSecret bool   `json:"isSecret,omitempty"`
+
+	// ValueMatched is true if the value (not the name) matched
+	ValueMatched bool `json:"valueMatched,omitempty"`
+}
+
+// SearchVariableGroups finds the variables in a project's groups whose names match a pattern.  If searchValues
+// is true, variables whose values match are found too -- but never secrets (TFS doesn't return their values).
+// The matches are sorted by group, then variable
+func SearchVariableGroups(project string, groups []VariableGroup, pattern *regexp.Regexp, searchValues bool) []VariableMatch {
+	retval := []VariableMatch{}
+
+	for _, group := range groups {
+		for name, variable := range group.Variables {
+			nameMatched := pattern.MatchString(name)
+			valueMatched := !nameMatched && searchValues && !variable.IsSecret && pattern.MatchString(variable.Value)
+			if !nameMatched && !valueMatched {
+				continue
+			}
+
+			retval = append(retval, VariableMatch{
+				Project:      project,
+				Group:        group.Name,
+				Variable:     name,
+				Value:        variable.Value,
+				IsSecret:     variable.IsSecret,
+				ValueMatched: valueMatched,
+			})
+		}
+	}
+
+	sort.Slice(retval, func(i, j int) bool {
+		if retval[i].Group != retval[j].Group {
+			return retval[i].Group < retval[j].Group
+		}
+		return retval[i].Variable < retval[j].Variable
+	})
+	return retval
+}
+
+// Variable defines a single variable in a variable group (or a release definition)
 type Variable struct {
 	Value string `json:"value"`
+
+	// IsSecret indicates the value is secret.  TFS doesn't return secret values
+	IsSecret bool `json:"isSecret,omitempty"`
+
+	// AllowOverride indicates the value can be overridden at release time (release variables only)
+	AllowOverride bool `json:"allowOverride,omitempty"`
+
+	// Enabled, ContentType and Expires describe secrets in a Key Vault linked group
+	Enabled     bool       `json:"enabled,omitempty"`
+	ContentType string     `json:"contentType,omitempty"`
+	Expires     *time.Time `json:"expires,omitempty"`
+}
+
+// MarshalJSON writes a variable.  TFS never returns secret values, so a secret without a value is written with
+// a null value -- which tells TFS to keep the value it has, instead of clearing it
+func (v Variable) MarshalJSON() ([]byte, error) {
+	type variable Variable
+	if v.IsSecret && v.Value == "" {
+		return json.Marshal(struct {
+			Value *string `json:"value"`
+			variable
+		}{nil, variable(v)})
+	}
+	return json.Marshal(variable(v))
+}
+
+// FindVariableReferences returns the (sorted) list of variable names that are referenced in the given text,
+// either as a macro like $(name) or as an expression like variables.name or variables['name']
+func FindVariableReferences(text string, names []string) []string {
+	retval := []string{}
+
+	for _, name := range names {
+		quoted := regexp.QuoteMeta(name)
+		pattern := fmt.Sprintf(`(?i)\$\(\s*%[1]s\s*\)|variables\.%[1]s\b|variables\[\s*'%[1]s'\s*\]`, quoted)
+		if regexp.MustCompile(pattern).MatchString(text) {
+			retval = append(retval, name)
+		}
+	}
+
+	sort.Strings(retval)
+	return retval
+}
+
+// ReferencesVariableGroupByName returns true if the given pipeline YAML links the named variable group
+// (with a '- group: name' entry)
+func ReferencesVariableGroupByName(yaml, groupName string) bool {
+	pattern := fmt.Sprintf(`(?im)^\s*-?\s*group\s*:\s*['"]?%s['"]?\s*$`, regexp.QuoteMeta(groupName))
+	return regexp.MustCompile(pattern).MatchString(yaml)
 }
@@ -0,0 +1,215 @@
+package tfs_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// Variables referenced as macros or expressions should be found, and others ignored
+func TestFindVariableReferences_MixedReferences_ReturnsReferencedNames(t *testing.T) {
+
+	//	Arrange
+	text := `steps:
+- script: echo $(DbServer) and $( ApiKey )
+- script: echo ${{ variables.Environment }}
+  condition: eq(variables['Region'], 'east')`
+	names := []string{"Region", "DbServer", "Unused", "ApiKey", "Environment"}
+	expected := []string{"ApiKey", "DbServer", "Environment", "Region"}
+
+	//	Act
+	actual := tfs.FindVariableReferences(text, names)
+
+	//	Assert
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("FindVariableReferences expected %v but got %v", expected, actual)
+	}
+}
+
+// A YAML group reference should only match the exact group name
+func TestReferencesVariableGroupByName_GroupEntry_ReturnsMatch(t *testing.T) {
+
+	//	Arrange
+	yaml := `variables:
+- group: 'Special unicorn variables'
+- group: Other group
+- name: group
+  value: Special`
+
+	//	Act + Assert
+	if !tfs.ReferencesVariableGroupByName(yaml, "Special unicorn variables") {
+		t.Errorf("ReferencesVariableGroupByName should find a quoted group name, but didn't")
+	}
+
+	if !tfs.ReferencesVariableGroupByName(yaml, "Other group") {
+		t.Errorf("ReferencesVariableGroupByName should find an unquoted group name, but didn't")
+	}
+
+	if tfs.ReferencesVariableGroupByName(yaml, "Special") {
+		t.Errorf("ReferencesVariableGroupByName shouldn't match a partial group name, but did")
+	}
+}
+
+// A Key Vault linked group should keep its provider data when it is decoded and encoded again
+func TestVariableGroup_KeyVaultGroup_RoundTripsProviderData(t *testing.T) {
+
+	//	Arrange
+	raw := `{"id":7,"type":"AzureKeyVault","name":"Prod secrets","description":"",
+"providerData":{"serviceEndpointId":"0f6a2b63-2b9f-4e7b-9b5c-3c1a6d0e8f21","vault":"prod-vault"},
+"variables":{"DbPassword":{"isSecret":true,"value":null,"enabled":true,"contentType":""}}}`
+
+	//	Act
+	group := tfs.VariableGroup{}
+	if err := json.Unmarshal([]byte(raw), &group); err != nil {
+		t.Fatalf("Unable to decode the group: %s", err)
+	}
+
+	encoded, err := json.Marshal(group)
+	if err != nil {
+		t.Fatalf("Unable to encode the group: %s", err)
+	}
+
+	roundTripped := tfs.VariableGroup{}
+	if err := json.Unmarshal(encoded, &roundTripped); err != nil {
+		t.Fatalf("Unable to decode the encoded group: %s", err)
+	}
+
+	//	Assert
+	if !roundTripped.IsKeyVault() {
+		t.Errorf("Expected a Key Vault group, but got type '%s'", roundTripped.Type)
+	}
+
+	if roundTripped.ProviderData == nil || roundTripped.ProviderData.Vault != "prod-vault" || roundTripped.ProviderData.ServiceEndpointID != "0f6a2b63-2b9f-4e7b-9b5c-3c1a6d0e8f21" {
+		t.Errorf("Expected the provider data to round-trip, but got %+v", roundTripped.ProviderData)
+	}
+
+	if !roundTripped.Variables["DbPassword"].Enabled || !roundTripped.Variables["DbPassword"].IsSecret {
+		t.Errorf("Expected DbPassword to be an enabled secret, but got %+v", roundTripped.Variables["DbPassword"])
+	}
+}
+
+// The provider should describe where the variables come from
+func TestVariableGroupProvider_Types_ReturnsDescription(t *testing.T) {
+
+	//	Arrange
+	tests := []struct {
+		group    tfs.VariableGroup
+		expected string
+	}{
+		{tfs.VariableGroup{}, "Vsts"},
+		{tfs.VariableGroup{Type: "Vsts"}, "Vsts"},
+		{tfs.VariableGroup{Type: "AzureKeyVault", ProviderData: &tfs.VariableGroupProviderData{Vault: "prod-vault"}}, "AzureKeyVault: prod-vault"},
+	}
+
+	for _, test := range tests {
+		//	Act
+		actual := test.group.Provider()
+
+		//	Assert
+		if actual != test.expected {
+			t.Errorf("Provider expected '%s' but got '%s'", test.expected, actual)
+		}
+	}
+}
+
+// Secrets can't be compared by value, so they only differ if one of them isn't secret
+func TestDiffVariableGroups_Secrets_OnlyDifferWhenNoLongerSecret(t *testing.T) {
+
+	//	Arrange
+	from := tfs.VariableGroup{Variables: map[string]tfs.Variable{
+		"Password": {IsSecret: true},
+		"ApiKey":   {IsSecret: true},
+		"Horn":     {Value: "sparkly"},
+	}}
+	to := tfs.VariableGroup{Variables: map[string]tfs.Variable{
+		"Password": {IsSecret: true},
+		"ApiKey":   {Value: "not-so-secret"},
+		"Horn":     {Value: "sparkly"},
+	}}
+
+	//	Act
+	differences := tfs.DiffVariableGroups(from, to)
+
+	//	Assert
+	if len(differences) != 1 || differences[0].Name != "ApiKey" || differences[0].Change != tfs.VariableChanged {
+		t.Errorf("Expected only ApiKey to have changed, but got %+v", differences)
+	}
+}
+
+// A copy shouldn't share its variables with the original
+func TestVariableGroupCopy_ChangeCopy_OriginalUnchanged(t *testing.T) {
+
+	//	Arrange
+	group := tfs.VariableGroup{ID: 7, Name: "Unicorn variables", Variables: map[string]tfs.Variable{"Horn": {Value: "sparkly"}}}
+
+	//	Act
+	copied := group.Copy("Dragon variables")
+	copied.Variables["Wings"] = tfs.Variable{Value: "yes"}
+
+	//	Assert
+	if copied.ID != 0 || copied.Name != "Dragon variables" {
+		t.Errorf("Expected a new group named 'Dragon variables', but got %+v", copied)
+	}
+
+	if len(group.Variables) != 1 {
+		t.Errorf("Expected the original group to be unchanged, but got %+v", group.Variables)
+	}
+}
+
+// Secrets without values should be sent as null, so TFS keeps their values
+func TestVariableMarshalJSON_SecretWithoutValue_WritesNull(t *testing.T) {
+
+	//	Arrange
+	variables := map[string]tfs.Variable{
+		"Password": {IsSecret: true},
+		"Token":    {Value: "new-token", IsSecret: true},
+		"Horn":     {Value: ""},
+	}
+
+	//	Act
+	data, err := json.Marshal(variables)
+
+	//	Assert
+	if err != nil {
+		t.Fatalf("Marshal failed: %s", err)
+	}
+
+	expected := `{"Horn":{"value":""},"Password":{"value":null,"isSecret":true},"Token":{"value":"new-token","isSecret":true}}`
+	if string(data) != expected {
+		t.Errorf("Expected %s but got %s", expected, data)
+	}
+}
+
+// Variables should be found by name, and by value when values are searched (sorted by group and variable)
+func TestSearchVariableGroups_NamesAndValues_ReturnsMatches(t *testing.T) {
+
+	//	Arrange
+	groups := []tfs.VariableGroup{
+		{Name: "Website", Variables: map[string]tfs.Variable{
+			"DB_CONNECTION": {Value: "Server=db1"},
+			"DbPassword":    {Value: "", IsSecret: true},
+			"Theme":         {Value: "unicorn"},
+		}},
+		{Name: "Api", Variables: map[string]tfs.Variable{
+			"ConnectionString": {Value: "Server=db2"},
+			"LogLevel":         {Value: "db"},
+		}},
+	}
+	pattern := regexp.MustCompile(`(?i)^db`)
+
+	//	Act
+	names := tfs.SearchVariableGroups("Web", groups, pattern, false)
+	values := tfs.SearchVariableGroups("Web", groups, regexp.MustCompile(`db\d`), true)
+
+	//	Assert
+	if len(names) != 2 || names[0].Variable != "DB_CONNECTION" || names[1].Variable != "DbPassword" || names[0].Project != "Web" || names[0].Group != "Website" {
+		t.Errorf("Expected DB_CONNECTION and DbPassword (by name only), but got %+v", names)
+	}
+
+	if len(values) != 2 || values[0].Group != "Api" || values[0].Variable != "ConnectionString" || !values[0].ValueMatched || values[1].Variable != "DB_CONNECTION" {
+		t.Errorf("Expected the variables whose values match (sorted by group), but got %+v", values)
+	}
+}
@@ -0,0 +1,177 @@
+package tfs
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// WiqlResult defines the response recieved when running a WIQL query
+type WiqlResult struct {
+	// QueryType is flat, tree or oneHop
+	QueryType string    `json:"queryType"`
+	AsOf      time.Time `json:"asOf"`
+
+	// Columns are the fields selected by the query
+	Columns []WorkItemFieldReference `json:"columns"`
+
+	// WorkItems are the work items returned by a flat query
+	WorkItems []WorkItemReference `json:"workItems"`
+
+	// WorkItemRelations are the work items returned by a tree or one hop query
+	WorkItemRelations []WorkItemLink `json:"workItemRelations"`
+}
+
+// IDs gets the ids of the work items in the query result (in order, without duplicates)
+func (r WiqlResult) IDs() []int {
+	retval := []int{}
+	seen := map[int]bool{}
+
+	add := func(ref *WorkItemReference) {
+		if ref != nil && !seen[ref.ID] {
+			seen[ref.ID] = true
+			retval = append(retval, ref.ID)
+		}
+	}
+
+	for i := range r.WorkItems {
+		add(&r.WorkItems[i])
+	}
+
+	for _, link := range r.WorkItemRelations {
+		add(link.Source)
+		add(link.Target)
+	}
+
+	return retval
+}
+
+// Fields gets the reference names of the columns selected by the query
+func (r WiqlResult) Fields() []string {
+	retval := []string{}
+	for _, column := range r.Columns {
+		retval = append(retval, column.ReferenceName)
+	}
+	return retval
+}
+
+// WorkItemFieldReference is a reference to a single work item field
+type WorkItemFieldReference struct {
+	ReferenceName string `json:"referenceName"`
+	Name          string `json:"name"`
+	URL           string `json:"url"`
+}
+
+// WorkItemReference is a reference to a single work item
+type WorkItemReference struct {
+	ID  int    `json:"id"`
+	URL string `json:"url"`
+}
+
+// WorkItemLink is a link between two work items, as returned by a tree or one hop query
+type WorkItemLink struct {
+	Rel    string             `json:"rel"`
+	Source *WorkItemReference `json:"source"`
+	Target *WorkItemReference `json:"target"`
+}
+
+// WorkItemsResponse defines the response recieved when getting a batch of work items
+type WorkItemsResponse struct {
+	Count     int        `json:"count"`
+	WorkItems []WorkItem `json:"value"`
+}
+
+// WorkItem is a single work item
+type WorkItem struct {
+	ID  int `json:"id"`
+	Rev int `json:"rev"`
+
+	// Fields is the map of field reference names (like System.Title) to values
+	Fields map[string]interface{} `json:"fields"`
+
+	Relations []WorkItemRelation `json:"relations"`
+
+	URL string `json:"url"`
+}
+
+// WorkItemRelation is a single link from a work item (to another work item, a hyperlink, an attachment, etc.)
+type WorkItemRelation struct {
+	Rel        string                 `json:"rel"`
+	URL        string                 `json:"url"`
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+// Field gets the value of a field formatted as a string.  Identities are formatted as their display name
+func (w WorkItem) Field(referenceName string) string {
+	if referenceName == "System.Id" {
+		return strconv.Itoa(w.ID)
+	}
+
+	switch v := w.Fields[referenceName].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case map[string]interface{}:
+		if name, ok := v["displayName"].(string); ok {
+			return name
+		}
+		return fmt.Sprint(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// QueryHierarchyItem is a single shared (or personal) work item query, or a query folder
+type QueryHierarchyItem struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Wiql     string `json:"wiql"`
+	IsFolder bool   `json:"isFolder"`
+	URL      string `json:"url"`
+}
+
+// JSONPatchOperation is a single JSON Patch operation, used to create and update work items
+type JSONPatchOperation struct {
+	// Op is the operation: add, remove, replace, move, copy or test
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// FieldPatch creates the operations to set the given work item fields (in field name order)
+func FieldPatch(fields map[string]string) []JSONPatchOperation {
+	names := []string{}
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	retval := []JSONPatchOperation{}
+	for _, name := range names {
+		retval = append(retval, JSONPatchOperation{
+			Op:    "add",
+			Path:  "/fields/" + name,
+			Value: fields[name],
+		})
+	}
+
+	return retval
+}
+
+// ParentLinkPatch creates the operation to link a work item to its parent (given the parent work item url)
+func ParentLinkPatch(parentURL string) JSONPatchOperation {
+	return JSONPatchOperation{
+		Op:   "add",
+		Path: "/relations/-",
+		Value: map[string]interface{}{
+			"rel": "System.LinkTypes.Hierarchy-Reverse",
+			"url": parentURL,
+		},
+	}
+}
@@ -0,0 +1,92 @@
+package tfs_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/danesparza/tfsutil/tfs"
+)
+
+// Tree query results should list each work item once, in order
+func TestWiqlResult_TreeQuery_IDs_ReturnsUniqueIDs(t *testing.T) {
+
+	//	Arrange
+	result := tfs.WiqlResult{
+		QueryType: "tree",
+		WorkItemRelations: []tfs.WorkItemLink{
+			{Target: &tfs.WorkItemReference{ID: 10}},
+			{Rel: "System.LinkTypes.Hierarchy-Forward", Source: &tfs.WorkItemReference{ID: 10}, Target: &tfs.WorkItemReference{ID: 11}},
+			{Rel: "System.LinkTypes.Hierarchy-Forward", Source: &tfs.WorkItemReference{ID: 10}, Target: &tfs.WorkItemReference{ID: 12}},
+		},
+	}
+	expected := []int{10, 11, 12}
+
+	//	Act
+	actual := result.IDs()
+
+	//	Assert
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("IDs expected %v but got %v", expected, actual)
+	}
+}
+
+// Work item fields should be formatted as strings, with identities formatted as their display name
+func TestWorkItem_MixedFields_Field_ReturnsFormattedValue(t *testing.T) {
+
+	//	Arrange
+	item := tfs.WorkItem{
+		ID: 42,
+		Fields: map[string]interface{}{
+			"System.Title":                          "Add login page",
+			"Microsoft.VSTS.Scheduling.StoryPoints": float64(3),
+			"System.AssignedTo":                     map[string]interface{}{"displayName": "Jamie Smith", "uniqueName": "jamie@example.com"},
+			"Microsoft.VSTS.Common.BacklogPriority": 1.5,
+		},
+	}
+
+	tests := map[string]string{
+		"System.Id":                             "42",
+		"System.Title":                          "Add login page",
+		"Microsoft.VSTS.Scheduling.StoryPoints": "3",
+		"System.AssignedTo":                     "Jamie Smith",
+		"Microsoft.VSTS.Common.BacklogPriority": "1.5",
+		"System.Missing":                        "",
+	}
+
+	//	Act
+	for field, expected := range tests {
+		actual := item.Field(field)
+
+		//	Assert
+		if actual != expected {
+			t.Errorf("Field('%s') expected '%s' but got '%s'", field, expected, actual)
+		}
+	}
+}
+
+// Field patches should be in field name order, and the parent link should use the reverse hierarchy link
+func TestFieldPatch_Fields_ReturnsOrderedOperations(t *testing.T) {
+
+	//	Arrange
+	fields := map[string]string{
+		"System.Title":         "Write login tests",
+		"System.IterationPath": "Website\\Sprint 12",
+	}
+
+	//	Act
+	patch := append(tfs.FieldPatch(fields), tfs.ParentLinkPatch("http://tfs/DefaultCollection/_apis/wit/workItems/12"))
+
+	//	Assert
+	if len(patch) != 3 {
+		t.Fatalf("Expected 3 operations but got %v", len(patch))
+	}
+
+	if patch[0].Path != "/fields/System.IterationPath" || patch[1].Path != "/fields/System.Title" || patch[1].Value != "Write login tests" {
+		t.Errorf("FieldPatch expected ordered field operations but got %+v", patch[:2])
+	}
+
+	link, _ := patch[2].Value.(map[string]interface{})
+	if patch[2].Path != "/relations/-" || link["rel"] != "System.LinkTypes.Hierarchy-Reverse" {
+		t.Errorf("ParentLinkPatch expected a reverse hierarchy link but got %+v", patch[2])
+	}
+}